@@ -1,6 +1,11 @@
 package main
 
-import app "github.com/Tamplier2911/gorest/internal"
+import (
+	"fmt"
+	"os"
+
+	app "github.com/Tamplier2911/gorest/internal"
+)
 
 // @title Go REST API example
 // @version 2.0
@@ -12,6 +17,27 @@ import app "github.com/Tamplier2911/gorest/internal"
 // @BasePath /api/v2
 func main() {
 	app := app.Application{}
+
+	// manage schema migrations: app migrate up|down [-steps n]|status|create <name>
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := app.Migrate(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	app.Setup()
+
+	// re-encrypt stored secrets after key rotation: app rekey
+	if len(os.Args) > 1 && os.Args[1] == "rekey" {
+		err := app.Rekey()
+		if err != nil {
+			app.Logger.Fatalw("failed to rekey stored secrets", "err", err)
+		}
+		return
+	}
+
 	app.Start()
 }
@@ -1,7 +1,6 @@
 package app
 
 import (
-	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/service"
 
 	_ "github.com/Tamplier2911/gorest/internal/docs"
@@ -10,6 +9,9 @@ import (
 	"github.com/Tamplier2911/gorest/internal/v2/auth"
 	"github.com/Tamplier2911/gorest/internal/v2/comments"
 	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/internal/v2/search"
+	"github.com/Tamplier2911/gorest/internal/v2/tokens"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
 
@@ -26,16 +28,44 @@ func (a *Application) Setup() {
 
 	// default port '8080' || export GOREST_PORT='8080' || m.Server.Addr = ":3000"
 
-	// automigrate models
-	a.Logger.Info("automigrating models")
-	err := a.MySQL.AutoMigrate(
-		&models.User{},
-		&models.AuthProvider{},
-		&models.Post{},
-		&models.Comment{},
-	)
+	// apply pending migrations
+	if a.Config.MigrateOnStart {
+		a.Logger.Info("applying migrations")
+		applied, err := a.MigrateUp()
+		if err != nil {
+			a.Logger.Fatalw("failed to apply migrations", "err", err)
+		}
+		a.Logger.Infow("successfully applied migrations", "applied", len(applied))
+	}
+
+	// automigrate models, meant for development
+	if a.Config.AutoMigrate {
+		a.Logger.Info("automigrating models")
+		err := a.AutoMigrate()
+		if err != nil {
+			a.Logger.Fatalw("failed to automigrate models", "err", err)
+		}
+	}
+
+	a.Mount()
+}
+
+// Mount is used to create repositories, search backend and routes of initialized service with migrated database.
+func (a *Application) Mount() {
+	var err error
+
+	// create repositories
+	a.Logger.Infow("wiring repositories", "backend", a.Config.RepositoryBackend)
+	a.Repositories, err = a.NewRepositories()
+	if err != nil {
+		a.Logger.Fatalw("failed to create repositories", "err", err)
+	}
+
+	// create search backend
+	a.Logger.Infow("wiring search backend", "backend", a.Config.SearchBackend)
+	a.Search, err = a.NewSearch()
 	if err != nil {
-		a.Logger.Fatalw("failed to automigrate models", "err", err)
+		a.Logger.Fatalw("failed to create search backend", "err", err)
 	}
 
 	// /swagger/index.html
@@ -52,4 +82,10 @@ func (a *Application) Setup() {
 	posts.Posts{}.Setup(&a.Service)
 	// /api/v2/comments
 	comments.Comments{}.Setup(&a.Service)
+	// /api/v2/users
+	users.Users{}.Setup(&a.Service)
+	// /api/v2/search
+	search.Search{}.Setup(&a.Service)
+	// /api/v2/tokens
+	tokens.Tokens{}.Setup(&a.Service)
 }
@@ -0,0 +1,21 @@
+// Package apptest is used to boot application in tests without running MySQL server.
+package apptest
+
+import (
+	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+)
+
+// New is used to setup application against database of test harness,
+// see testclient.Boot for selection of database.
+func New() *app.Application {
+	a, _ := Boot()
+	return a
+}
+
+// Boot is used to setup application and get harness of it for isolation of tests and fixture builders.
+func Boot() (*app.Application, *testclient.Harness) {
+	a := &app.Application{}
+	h := testclient.MustBoot(&a.Service, a.Mount)
+	return a, h
+}
@@ -26,389 +26,420 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/facebook/callback": {
-            "get": {
-                "description": "Verifies code and state, exchanges code with authorization token,",
+        "/auth/local/login": {
+            "post": {
+                "description": "Verifies password of local account with verified email, signs JWT and responds with session tokens.\nEmail and ip address are blocked for a while after too many failed attempts.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Callback triggered once user respond to facebook authorization popup.",
-                "operationId": "FacebookCallback",
+                "summary": "Logins user with email and password.",
+                "operationId": "LocalLogin",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Parameter for code grant",
-                        "name": "code",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Parameter for state",
-                        "name": "state",
-                        "in": "query",
-                        "required": true
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/LocalLoginRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.FacebookCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/LocalLoginResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.FacebookCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.FacebookCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/auth.FacebookCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/auth.FacebookCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/facebook/login": {
-            "get": {
-                "description": "Directs users to facebook popup to grant access to user account.",
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Login with facebook.",
-                "operationId": "FacebookLogin",
-                "responses": {
-                    "307": {
-                        "description": "url",
-                        "schema": {
-                            "type": "string"
-                        }
+        "/auth/local/password": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
-            }
-        },
-        "/auth/github/callback": {
-            "get": {
-                "description": "Verifies code and state, exchanges code with authorization token,",
+                ],
+                "description": "Changes password of local account, current password is required.\nUser which logged in with provider only sets password without current one and can login with email afterwards.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Callback triggered once user respond to github authorization popup.",
-                "operationId": "GithubCallback",
+                "summary": "Changes password of current user.",
+                "operationId": "ChangePassword",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Parameter for code grant",
-                        "name": "code",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Parameter for state",
-                        "name": "state",
-                        "in": "query",
-                        "required": true
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChangePasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.GithubCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/ChangePasswordResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.GithubCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "401": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.GithubCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/auth.GithubCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/auth.GithubCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/github/login": {
-            "get": {
-                "description": "Directs users to github popup to grant access to user account.",
+        "/auth/local/password/forgot": {
+            "post": {
+                "description": "Sends password reset token if local account with email exists,\nresponse does not reveal if account exists.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Login with github.",
-                "operationId": "GithubLogin",
+                "summary": "Sends password reset token.",
+                "operationId": "ForgotPassword",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ForgotPasswordRequest"
+                        }
+                    }
+                ],
                 "responses": {
-                    "307": {
-                        "description": "url",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/ForgotPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/google/callback": {
-            "get": {
-                "description": "Verifies code and state, exchanges code with authorization token,",
+        "/auth/local/password/reset": {
+            "post": {
+                "description": "Consumes token sent by email and sets new password, email is considered verified\nand every session of user is logged out.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Callback triggered once user respond to google authorization popup.",
-                "operationId": "GoogleCallback",
+                "summary": "Resets password of local account.",
+                "operationId": "ResetPassword",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Parameter for code grant",
-                        "name": "code",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Parameter for state",
-                        "name": "state",
-                        "in": "query",
-                        "required": true
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ResetPasswordRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.GoogleCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/ResetPasswordResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/auth.GoogleCallbackHandlerResponseBody"
-                        }
-                    },
-                    "401": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/auth.GoogleCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/auth.GoogleCallbackHandlerResponseBody"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/auth.GoogleCallbackHandlerResponseBody"
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/google/login": {
-            "get": {
-                "description": "Directs users to google popup to grant access to user account.",
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Login with google.",
-                "operationId": "GoogleLogin",
-                "responses": {
-                    "307": {
-                        "description": "url",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/auth/refresh": {
+        "/auth/local/register": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+                "description": "Creates user with local auth provider and sends email verification token,\nuser can login once email is verified.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
-                "description": "Validate user token and produce token with prolonged expire data.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Refreshes token.",
-                "operationId": "RefreshToken",
+                "summary": "Registers user with email and password.",
+                "operationId": "Register",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RegisterRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
+                            "$ref": "#/definitions/RegisterResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "404": {
+                    "409": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/RefreshTokenResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/comments": {
-            "get": {
-                "description": "Gets comment records from database using provided query.",
+        "/auth/local/verify": {
+            "post": {
+                "description": "Consumes token sent by email on registration and allows user to login with password.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Gets comment records.",
-                "operationId": "GetComments",
+                "summary": "Verifies email of local account.",
+                "operationId": "VerifyEmail",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "postID",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "name": "userID",
-                        "in": "query"
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VerifyEmailRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/GetCommentsResponse"
+                            "$ref": "#/definitions/VerifyEmailResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/GetCommentsResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentsResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/GetCommentsResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/GetCommentsResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/local/verify/resend": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Creates comment record in database using provided data.",
+                "description": "Sends new verification token if local account with email is not verified yet,\nresponse does not reveal if account exists.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Creates comment record.",
-                "operationId": "CreateComment",
+                "summary": "Resends email verification token.",
+                "operationId": "ResendVerification",
                 "parameters": [
                     {
                         "description": "data",
@@ -416,278 +447,205 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreateCommentRequest"
+                            "$ref": "#/definitions/ResendVerificationRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/CreateCommentResponse"
+                            "$ref": "#/definitions/ResendVerificationResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/CreateCommentResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/CreateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/CreateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/CreateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/comments/{id}": {
-            "get": {
-                "description": "Gets comment record from database using provided id.",
-                "produces": [
-                    "application/json",
-                    "text/xml"
-                ],
-                "tags": [
-                    "Comments"
-                ],
-                "summary": "Gets comment record.",
-                "operationId": "GetComment",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentResponse"
-                        }
-                    },
-                    "default": {
-                        "description": "",
-                        "schema": {
-                            "$ref": "#/definitions/GetCommentResponse"
-                        }
-                    }
-                }
-            },
-            "put": {
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates comment record in database using provided data.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revokes session family of access token, its refresh tokens and access tokens are no longer accepted.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Comments"
-                ],
-                "summary": "Updates comment record.",
-                "operationId": "UpdateComment",
-                "parameters": [
-                    {
-                        "description": "data",
-                        "name": "fields",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UpdateCommentRequest"
-                        }
-                    }
+                    "Auth"
                 ],
+                "summary": "Logs out current session.",
+                "operationId": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UpdateCommentResponse"
+                            "$ref": "#/definitions/LogoutResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/UpdateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "404": {
+                    "401": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/UpdateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/UpdateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/UpdateCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout/all": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes comment record from database using provided id.",
+                "description": "Revokes every session of current user and rejects access tokens issued before request.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Deletes comment record.",
-                "operationId": "DeleteComment",
+                "summary": "Logs out all devices.",
+                "operationId": "LogoutAll",
                 "responses": {
-                    "204": {
-                        "description": "No Content",
-                        "schema": {
-                            "$ref": "#/definitions/DeleteCommentResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeleteCommentResponse"
+                            "$ref": "#/definitions/LogoutAllResponse"
                         }
                     },
-                    "404": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/DeleteCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/DeleteCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/DeleteCommentResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/posts": {
+        "/auth/providers": {
             "get": {
-                "description": "Gets post records from database using provided query.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds with provider accounts linked to current user in order of linking.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Posts"
-                ],
-                "summary": "Gets post records.",
-                "operationId": "GetPosts",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "Auth"
                 ],
+                "summary": "Lists identity providers linked to current user.",
+                "operationId": "GetLinkedProviders",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/GetLinkedProvidersResponse"
                         }
                     },
-                    "404": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/refresh": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Creates post record in database using provided data.",
+                "description": "Exchanges refresh token for new access token and refresh token, reuse of rotated refresh token revokes whole session family.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Posts"
+                    "Auth"
                 ],
-                "summary": "Creates post record.",
-                "operationId": "CreatePost",
+                "summary": "Rotates refresh token.",
+                "operationId": "RefreshToken",
                 "parameters": [
                     {
                         "description": "data",
@@ -695,7 +653,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/CreatePostRequest"
+                            "$ref": "#/definitions/RefreshTokenRequest"
                         }
                     }
                 ],
@@ -703,140 +661,200 @@ var doc = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/RefreshTokenResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
-                    "404": {
+                    "401": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/CreatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/posts/{id}": {
+        "/auth/{provider}/callback": {
             "get": {
-                "description": "Gets post record from database using provided id.",
+                "description": "Verifies code and state, exchanges code with authorization token,",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Posts"
+                    "Auth"
+                ],
+                "summary": "Callback triggered once user respond to provider authorization popup.",
+                "operationId": "Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter for code grant",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parameter for state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Gets post record.",
-                "operationId": "GetPost",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/CallbackResponse"
+                        }
+                    },
+                    "303": {
+                        "description": "post login redirect url with tokens in fragment",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/{provider}/link": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates post record in database using provided data.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Responds with provider url, once user grants access callback links provider account to current user.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Posts"
+                    "Auth"
                 ],
-                "summary": "Updates post record.",
-                "operationId": "UpdatePost",
+                "summary": "Starts linking of identity provider to current user.",
+                "operationId": "LinkProvider",
                 "parameters": [
                     {
-                        "description": "data",
-                        "name": "fields",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/UpdatePostRequest"
-                        }
+                        "type": "string",
+                        "description": "Name of identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Post link redirect url, relative or on base url host",
+                        "name": "redirect",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UpdatePostResponse"
+                            "$ref": "#/definitions/LinkProviderResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/UpdatePostResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/UpdatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/UpdatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/UpdatePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -847,138 +865,2113 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Deletes post record from database using provided id.",
+                "description": "Removes provider account linked to current user, last linked provider can not be removed.",
                 "produces": [
                     "application/json",
-                    "text/xml"
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
                 ],
                 "tags": [
-                    "Posts"
+                    "Auth"
+                ],
+                "summary": "Unlinks identity provider from current user.",
+                "operationId": "UnlinkProvider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of identity provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Deletes post record.",
-                "operationId": "DeletePost",
                 "responses": {
-                    "204": {
-                        "description": "No Content",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeletePostResponse"
+                            "$ref": "#/definitions/UnlinkProviderResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/{provider}/login": {
+            "get": {
+                "description": "Directs users to provider popup to grant access to user account.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Login with identity provider.",
+                "operationId": "Login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of identity provider, google, facebook, github or configured OpenID Connect provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Post login redirect url, relative or on base url host",
+                        "name": "redirect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "307": {
+                        "description": "url",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/DeletePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/DeletePostResponse"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/DeletePostResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments": {
+            "get": {
+                "description": "Gets comment records from database using provided query, supports offset and cursor pagination.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Gets comment records.",
+                "operationId": "GetComments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "postID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "skipTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "userID",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetCommentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "default": {
                         "description": "",
                         "schema": {
-                            "$ref": "#/definitions/DeletePostResponse"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates comment record in database using provided data.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Creates comment record.",
+                "operationId": "CreateComment",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreateCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "get": {
+                "description": "Gets comment record from database using provided id.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Gets comment record.",
+                "operationId": "GetComment",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates comment record in database using provided data.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Updates comment record.",
+                "operationId": "UpdateComment",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UpdateCommentResponse"
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes comment record from database using provided id.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Comments"
+                ],
+                "summary": "Deletes comment record.",
+                "operationId": "DeleteComment",
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/DeleteCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "Gets post records from database using provided query, supports offset and cursor pagination.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Gets post records.",
+                "operationId": "GetPosts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "skipTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates post record in database using provided data.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Creates post record.",
+                "operationId": "CreatePost",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}": {
+            "get": {
+                "description": "Gets post record from database using provided id.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Gets post record.",
+                "operationId": "GetPost",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github.com_Tamplier2911_gorest_internal_v2_posts.GetPostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates post record in database using provided data.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Updates post record.",
+                "operationId": "UpdatePost",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdatePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UpdatePostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes post record from database using provided id.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Deletes post record.",
+                "operationId": "DeletePost",
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "$ref": "#/definitions/DeletePostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/comments": {
+            "get": {
+                "description": "Gets comment records that belong to post with provided id using provided query, supports offset and cursor pagination.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Gets comment records of post.",
+                "operationId": "GetPostComments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "skipTotal",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetPostCommentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates comment record that belongs to post with provided id using provided data.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Creates comment record in post.",
+                "operationId": "CreatePostComment",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreatePostCommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/comments/count": {
+            "get": {
+                "description": "Counts comment records that belong to post with provided id.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Posts"
+                ],
+                "summary": "Counts comment records of post.",
+                "operationId": "CountPostComments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/CountPostCommentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Searches post titles and bodies or comment names and bodies, results are ranked and highlighted, supports offset and cursor pagination.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Searches posts or comments.",
+                "operationId": "Search",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "name": "skipTotal",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "type",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets personal access tokens of authenticated user, token values are never returned.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Gets personal access tokens.",
+                "operationId": "GetTokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetTokensResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates personal access token with provided scopes, token value is returned only once.\nPersonal access tokens can only grant scopes they hold themselves.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Creates personal access token.",
+                "operationId": "CreateToken",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/CreateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes personal access token of authenticated user, revoked token is rejected immediately.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Revokes personal access token.",
+                "operationId": "RevokeToken",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of token",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RevokeTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets public user records from database using provided query, emails are not exposed.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Gets user records.",
+                "operationId": "GetUsers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "userRole",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets record of authenticated user from database using id from token.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Gets current user record.",
+                "operationId": "GetMe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetMeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates username and avatar of authenticated user, omitted fields are left unchanged.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Updates current user record.",
+                "operationId": "UpdateMe",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UpdateMeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Gets public user record from database using provided id, email is not exposed.",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Gets user record.",
+                "operationId": "GetUser",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates role of user with provided id, available for admins only.",
+                "consumes": [
+                    "application/json",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Updates user role.",
+                "operationId": "UpdateUserRole",
+                "parameters": [
+                    {
+                        "description": "data",
+                        "name": "fields",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UpdateUserRoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "default": {
+                        "description": "",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "CallbackResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string"
+                },
+                "newPassword": {
+                    "type": "string"
+                }
+            }
+        },
+        "ChangePasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "Comment": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "postId": {
+                    "description": "fk",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "CountPostCommentsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateCommentRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "name",
+                "postId"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "postId": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreateCommentResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "$ref": "#/definitions/Comment"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreatePostCommentRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "name"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreatePostCommentResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "$ref": "#/definitions/Comment"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreatePostRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "CreatePostResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "post": {
+                    "$ref": "#/definitions/Post"
+                }
+            }
+        },
+        "CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "expiresAt",
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "personalAccessToken": {
+                    "$ref": "#/definitions/PersonalAccessToken"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeleteCommentResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeletePostResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is path of field named as in request, e.g. scopes[0].",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message is human readable description of failed rule in language accepted by client.",
+                    "type": "string"
+                },
+                "param": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "ForgotPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "GetCommentResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "$ref": "#/definitions/Comment"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "GetCommentsResponse": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Comment"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "prevCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "GetLinkedProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/LinkedProvider"
+                    }
+                }
+            }
+        },
+        "GetMeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/User"
+                }
+            }
+        },
+        "GetPostCommentsResponse": {
+            "type": "object",
+            "properties": {
+                "comments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Comment"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "nextCursor": {
+                    "type": "string"
+                },
+                "prevCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "GetTokensResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "personalAccessTokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PersonalAccessToken"
+                    }
+                }
+            }
+        },
+        "GetUserResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/PublicUser"
+                }
+            }
+        },
+        "GetUsersResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/PublicUser"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "Comment": {
+        },
+        "LinkProviderResponse": {
             "type": "object",
             "properties": {
-                "body": {
+                "message": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "LinkedProvider": {
+            "type": "object",
+            "properties": {
+                "linkedAt": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "providerUid": {
+                    "type": "string"
+                }
+            }
+        },
+        "LocalLoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "LocalLoginResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "LogoutAllResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "PersonalAccessToken": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
+                "lastUsedAt": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
-                "postId": {
-                    "description": "fk",
+                "prefix": {
+                    "type": "string"
+                },
+                "revokedAt": {
                     "type": "string"
                 },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "userId": {
+                    "description": "fk",
                     "type": "string"
                 }
             }
         },
-        "CreateCommentRequest": {
+        "Post": {
             "type": "object",
-            "required": [
-                "body",
-                "name",
-                "postId"
-            ],
             "properties": {
                 "body": {
                     "type": "string"
                 },
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "postId": {
+                "title": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "fk",
                     "type": "string"
                 }
             }
         },
-        "CreateCommentResponse": {
+        "Problem": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "$ref": "#/definitions/Comment"
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "PublicUser": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
                 },
+                "userRole": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "RefreshTokenResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "CreatePostRequest": {
+        "RegisterRequest": {
             "type": "object",
             "required": [
-                "body",
-                "title"
+                "email",
+                "password",
+                "username"
             ],
             "properties": {
-                "body": {
+                "email": {
                     "type": "string"
                 },
-                "title": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "CreatePostResponse": {
+        "RegisterResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
                 },
-                "post": {
-                    "$ref": "#/definitions/Post"
+                "user": {
+                    "$ref": "#/definitions/User"
                 }
             }
         },
-        "DeleteCommentResponse": {
+        "ResendVerificationRequest": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "message": {
+                "email": {
                     "type": "string"
                 }
             }
         },
-        "DeletePostResponse": {
+        "ResendVerificationResponse": {
             "type": "object",
             "properties": {
                 "message": {
@@ -986,59 +2979,98 @@ var doc = `{
                 }
             }
         },
-        "GetCommentResponse": {
+        "ResetPasswordRequest": {
             "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
             "properties": {
-                "comment": {
-                    "$ref": "#/definitions/Comment"
+                "password": {
+                    "type": "string"
                 },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "GetCommentsResponse": {
+        "RevokeTokenResponse": {
             "type": "object",
             "properties": {
-                "comments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/Comment"
-                    }
-                },
                 "message": {
                     "type": "string"
-                },
-                "total": {
-                    "type": "integer"
                 }
             }
         },
-        "Post": {
+        "SearchHighlight": {
             "type": "object",
             "properties": {
-                "body": {
+                "field": {
+                    "type": "string"
+                },
+                "fragment": {
                     "type": "string"
+                }
+            }
+        },
+        "SearchHit": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SearchHighlight"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
-                "title": {
+                "postId": {
                     "type": "string"
                 },
-                "userId": {
-                    "description": "fk",
+                "score": {
+                    "type": "number"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "RefreshTokenResponse": {
+        "SearchResponse": {
             "type": "object",
             "properties": {
+                "hits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SearchHit"
+                    }
+                },
                 "message": {
                     "type": "string"
                 },
-                "token": {
+                "nextCursor": {
+                    "type": "string"
+                },
+                "prevCursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "UnlinkProviderResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
                     "type": "string"
                 }
             }
@@ -1069,6 +3101,28 @@ var doc = `{
                 }
             }
         },
+        "UpdateMeRequest": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "UpdateMeResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/User"
+                }
+            }
+        },
         "UpdatePostRequest": {
             "type": "object",
             "required": [
@@ -1095,36 +3149,64 @@ var doc = `{
                 }
             }
         },
-        "auth.FacebookCallbackHandlerResponseBody": {
+        "UpdateUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "userRole"
+            ],
+            "properties": {
+                "userRole": {
+                    "type": "string"
+                }
+            }
+        },
+        "UpdateUserRoleResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
                 },
-                "token": {
-                    "type": "string"
+                "user": {
+                    "$ref": "#/definitions/User"
                 }
             }
         },
-        "auth.GithubCallbackHandlerResponseBody": {
+        "User": {
             "type": "object",
             "properties": {
-                "message": {
+                "avatarUrl": {
+                    "type": "string"
+                },
+                "email": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
+                "userRole": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
                 "token": {
                     "type": "string"
                 }
             }
         },
-        "auth.GoogleCallbackHandlerResponseBody": {
+        "VerifyEmailResponse": {
             "type": "object",
             "properties": {
                 "message": {
                     "type": "string"
-                },
-                "token": {
-                    "type": "string"
                 }
             }
         },
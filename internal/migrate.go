@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"github.com/Tamplier2911/gorest/pkg/migrations"
+	"github.com/Tamplier2911/gorest/pkg/service"
+)
+
+// migrateUsage describes subcommands of migrate command.
+const migrateUsage = "usage: migrate up | down [-steps n] | status | create [-dir path] <name>"
+
+// MigrateUp is used to apply pending migrations of initialized service.
+func (a *Application) MigrateUp() ([]migrate.Migration, error) {
+	m, err := a.NewMigrator()
+	if err != nil {
+		return nil, err
+	}
+	return m.Up(context.Background())
+}
+
+// Migrate is used to run migrate subcommand with its arguments, service is initialized by subcommand
+// so migrations are not applied on start.
+func (a *Application) Migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		a.Initialize(&service.InitializeOptions{MySQL: true})
+
+		applied, err := a.MigrateUp()
+		if err != nil {
+			return err
+		}
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		fmt.Printf("applied %d migrations\n", len(applied))
+		return nil
+	case "down":
+		flags := flag.NewFlagSet("down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		err := flags.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+		a.Initialize(&service.InitializeOptions{MySQL: true})
+
+		m, err := a.NewMigrator()
+		if err != nil {
+			return err
+		}
+		rolledBack, err := m.Down(context.Background(), *steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("rolled back %d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		a.Initialize(&service.InitializeOptions{MySQL: true})
+
+		m, err := a.NewMigrator()
+		if err != nil {
+			return err
+		}
+		statuses, err := m.Status(context.Background())
+		if err != nil {
+			return err
+		}
+		return PrintMigrationStatus(os.Stdout, statuses)
+	case "create":
+		flags := flag.NewFlagSet("create", flag.ContinueOnError)
+		dir := flags.String("dir", migrations.Dir, "directory of sql migrations")
+		err := flags.Parse(args[1:])
+		if err != nil {
+			return err
+		}
+		if flags.NArg() != 1 {
+			return fmt.Errorf(migrateUsage)
+		}
+
+		up, down, err := migrate.Create(*dir, flags.Arg(0), time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created %s\ncreated %s\n", up, down)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q, %s", args[0], migrateUsage)
+	}
+}
+
+// PrintMigrationStatus is used to write table of migration statuses.
+func PrintMigrationStatus(w io.Writer, statuses []migrate.Status) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		if status.Missing {
+			appliedAt += " (missing)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return tw.Flush()
+}
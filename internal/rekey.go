@@ -0,0 +1,26 @@
+package app
+
+import "fmt"
+
+// encryptedColumns lists columns of tables holding values encrypted with key ring.
+var encryptedColumns = []struct {
+	Table  string
+	Column string
+}{
+	{Table: "auth_providers", Column: "refresh_token"},
+}
+
+// Rekey is used to re-encrypt stored secrets with primary key of ring,
+// should be run after primary key is rotated and before old key is removed from ring.
+func (a *Application) Rekey() error {
+	for _, ec := range encryptedColumns {
+		a.Logger.Infow("rekeying column", "table", ec.Table, "column", ec.Column, "primaryKey", a.KeyRing.Primary())
+		updated, err := a.KeyRing.Rekey(a.MySQL, ec.Table, ec.Column)
+		if err != nil {
+			return fmt.Errorf("failed to rekey %s.%s: %s", ec.Table, ec.Column, err)
+		}
+		a.Logger.Infow("successfully rekeyed column", "table", ec.Table, "column", ec.Column, "updated", updated)
+	}
+
+	return nil
+}
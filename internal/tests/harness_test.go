@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/comments"
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHarness(t *testing.T) {
+	// init service
+	_, h := apptest.Boot()
+
+	var post *models.Post
+	var comment *models.Comment
+
+	t.Run("should serve records created by builders", func(t *testing.T) {
+		h.Isolate(t)
+
+		author := h.User().Create(t)
+		post = h.Post(author).Title("harness post").Create(t)
+		comment = h.Comment(post, author).Create(t)
+
+		var res posts.GetPostHandlerResponseBody
+		err := h.Client(nil).Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s", post.ID),
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, "harness post", res.Post.Title, "invalid title")
+	})
+
+	t.Run("should roll back records of isolated test", func(t *testing.T) {
+		_, err := h.Posts.Get(context.Background(), post.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound, "post was not rolled back")
+
+		_, err = h.Comments.Get(context.Background(), comment.ID)
+		require.ErrorIs(t, err, repository.ErrNotFound, "comment was not rolled back")
+	})
+
+	t.Run("should run transactions of handlers inside isolated test", func(t *testing.T) {
+		h.Isolate(t)
+
+		author := h.User().Create(t)
+		post := h.Post(author).Create(t)
+		comment := h.Comment(post, author).Create(t)
+
+		// post is deleted with its comments in transaction
+		err := h.Client(author).Request(&testclient.RequestOptions{
+			Method:   "DELETE",
+			URL:      fmt.Sprintf("/api/v2/posts/%s", post.ID),
+			Response: &posts.DeletePostHandlerResponseBody{},
+		})
+		require.NoError(t, err, "failed to delete post")
+
+		err = h.Client(nil).Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/comments/%s", comment.ID),
+			Response: &comments.GetCommentHandlerResponseBody{},
+		})
+		require.Error(t, err, "comment of deleted post was found")
+	})
+
+	t.Run("should build admin", func(t *testing.T) {
+		h.Isolate(t)
+
+		admin := h.User().Role(models.UserRoleAdmin).Create(t)
+		user, err := h.Users.Get(context.Background(), admin.ID)
+		require.NoError(t, err, "failed to get user")
+		require.Equal(t, models.UserRoleAdmin, user.UserRole, "invalid role")
+	})
+}
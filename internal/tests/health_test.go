@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/health"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/Tamplier2911/gorest/pkg/version"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test clients for both api versions
+	echoClient := testclient.TestClient{}
+	echoClient.Setup(&testclient.Options{
+		Router: a.Echo,
+	})
+	muxClient := testclient.TestClient{}
+	muxClient.Setup(&testclient.Options{
+		Router: a.Router,
+	})
+
+	t.Run("should report liveness", func(t *testing.T) {
+		for _, client := range []testclient.TestClient{echoClient, muxClient} {
+			var res health.Report
+			err := client.Request(&testclient.RequestOptions{
+				Method:   "GET",
+				URL:      "/healthz",
+				Response: &res,
+			})
+			require.NoError(t, err, "unexpected response")
+			require.Equal(t, health.StatusOK, res.Status, "invalid status")
+		}
+	})
+
+	t.Run("should report version", func(t *testing.T) {
+		for _, client := range []testclient.TestClient{echoClient, muxClient} {
+			var res version.Info
+			err := client.Request(&testclient.RequestOptions{
+				Method:   "GET",
+				URL:      "/version",
+				Response: &res,
+			})
+			require.NoError(t, err, "unexpected response")
+			require.Equal(t, version.Commit, res.Commit, "invalid commit")
+			require.Equal(t, version.BuildTime, res.BuildTime, "invalid build time")
+		}
+	})
+
+	t.Run("should not be ready until service is started", func(t *testing.T) {
+		var res health.Report
+		err := echoClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/readyz",
+			Response: &res,
+		})
+		require.Error(t, err, "service was ready before start")
+		require.Contains(t, err.Error(), `"status":"fail"`, "invalid check status")
+		require.NotContains(t, err.Error(), "service is not running", "check error was exposed")
+	})
+
+	t.Run("should be ready while service is running", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go a.Lifecycle.Run(ctx)
+		require.Eventually(t, a.Lifecycle.Ready, time.Second, 10*time.Millisecond, "service did not become ready")
+
+		for _, client := range []testclient.TestClient{echoClient, muxClient} {
+			var res health.Report
+			err := client.Request(&testclient.RequestOptions{
+				Method:   "GET",
+				URL:      "/readyz",
+				Response: &res,
+			})
+			require.NoError(t, err, "unexpected response")
+			require.Equal(t, health.StatusOK, res.Status, "invalid status")
+			for _, check := range res.Checks {
+				require.Equal(t, health.StatusOK, check.Status, "check %s failed", check.Name)
+			}
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/jwk"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS(t *testing.T) {
+	// generate retired rsa key and primary ed25519 key
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate rsa key")
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate ed25519 key")
+
+	encode := func(key interface{}) string {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err, "failed to marshal key")
+		return base64.StdEncoding.EncodeToString(der)
+	}
+
+	os.Setenv("GOREST_JWT_SIGNING_KEYS", "2021-rsa:"+encode(rsaKey)+",2022-ed:"+encode(edKey))
+	os.Setenv("GOREST_JWT_PRIMARY_KEY_ID", "2022-ed")
+	defer os.Unsetenv("GOREST_JWT_SIGNING_KEYS")
+	defer os.Unsetenv("GOREST_JWT_PRIMARY_KEY_ID")
+
+	// init service
+	a := apptest.New()
+
+	// init test clients for both api versions
+	echoClient := testclient.TestClient{}
+	echoClient.Setup(&testclient.Options{
+		Router: a.Echo,
+	})
+	muxClient := testclient.TestClient{}
+	muxClient.Setup(&testclient.Options{
+		Router: a.Router,
+	})
+
+	token := &access.Token{
+		UserID:   uuid.New(),
+		UserRole: models.UserRoleUser,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		},
+	}
+
+	t.Run("should publish public keys", func(t *testing.T) {
+		for _, client := range []testclient.TestClient{echoClient, muxClient} {
+			var res jwk.Set
+			err := client.Request(&testclient.RequestOptions{
+				Method:   "GET",
+				URL:      "/.well-known/jwks.json",
+				Response: &res,
+			})
+			require.NoError(t, err, "unexpected response")
+			require.Len(t, res.Keys, 2, "unexpected number of keys")
+			require.Equal(t, "RS256", res.Keys[0].Alg, "unexpected algorithm")
+			require.Equal(t, "EdDSA", res.Keys[1].Alg, "unexpected algorithm")
+		}
+	})
+
+	t.Run("should sign tokens with primary key verifiable with published key", func(t *testing.T) {
+		signed, err := a.Tokens.Encode(token)
+		require.NoError(t, err, "failed to sign token")
+
+		key, ok := a.Tokens.JWKS().Find("2022-ed")
+		require.True(t, ok, "primary key is not published")
+		public, err := key.PublicKey()
+		require.NoError(t, err, "failed to decode published key")
+
+		parsed, err := jwt.ParseWithClaims(signed, &access.Token{}, func(t *jwt.Token) (interface{}, error) {
+			return public, nil
+		})
+		require.NoError(t, err, "failed to verify token with published key")
+		require.Equal(t, "EdDSA", parsed.Method.Alg(), "unexpected algorithm")
+		require.Equal(t, "2022-ed", parsed.Header["kid"], "unexpected key id")
+	})
+
+	t.Run("should verify tokens signed with retired key", func(t *testing.T) {
+		jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, token)
+		jwtToken.Header["kid"] = "2021-rsa"
+		signed, err := jwtToken.SignedString(rsaKey)
+		require.NoError(t, err, "failed to sign token")
+
+		decoded, err := a.Tokens.Decode(signed)
+		require.NoError(t, err, "failed to decode token")
+		require.Equal(t, token.UserID, decoded.UserID, "unexpected user id")
+	})
+
+	t.Run("should reject tokens with algorithm of other key", func(t *testing.T) {
+		jwtToken := jwt.NewWithClaims(jwt.SigningMethodRS256, token)
+		jwtToken.Header["kid"] = "2022-ed"
+		signed, err := jwtToken.SignedString(rsaKey)
+		require.NoError(t, err, "failed to sign token")
+
+		_, err = a.Tokens.Decode(signed)
+		require.Error(t, err, "token was accepted")
+	})
+
+	t.Run("should reject HS256 tokens without fallback", func(t *testing.T) {
+		_, err := a.Tokens.Decode(access.MustEncodeToken(token, a.Config.HMACSecret))
+		require.Error(t, err, "token was accepted")
+
+		client := testclient.TestClient{}
+		client.Setup(&testclient.Options{
+			Router: a.Echo,
+			Token:  access.MustEncodeToken(token, a.Config.HMACSecret),
+		})
+		err = client.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users/me",
+		})
+		require.Error(t, err, "token was accepted")
+	})
+
+	t.Run("should accept HS256 tokens with fallback", func(t *testing.T) {
+		keys, err := access.ParseKeySet(a.Config.HMACSecret, true, "2022-ed", "2022-ed:"+encode(edKey))
+		require.NoError(t, err, "failed to parse keys")
+
+		_, err = keys.Decode(access.MustEncodeToken(token, a.Config.HMACSecret))
+		require.NoError(t, err, "token was rejected")
+	})
+}
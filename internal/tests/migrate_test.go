@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"github.com/Tamplier2911/gorest/pkg/migrations"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMigrate(t *testing.T) {
+	// own database so schema of harness is not touched
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:migrate_%s?mode=memory&cache=shared", uuid.New())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to open database")
+
+	files, err := migrate.Load(fstest.MapFS{
+		"sql/1_create_notes.up.sql":   {Data: []byte("-- notes\nCREATE TABLE notes (id INTEGER PRIMARY KEY);\nCREATE INDEX idx_notes_id ON notes (id);\n")},
+		"sql/1_create_notes.down.sql": {Data: []byte("DROP TABLE notes;\n")},
+		"sql/3_create_tags.up.sql":    {Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY);")},
+	}, "sql")
+	require.NoError(t, err, "failed to load migrations")
+	require.Len(t, files, 2, "invalid number of loaded migrations")
+
+	all := append(files, migrate.Migration{
+		Version: 2,
+		Name:    "add_note_body",
+		Up:      migrate.SQL("ALTER TABLE notes ADD COLUMN body TEXT;"),
+		Down:    migrate.SQL("ALTER TABLE notes DROP COLUMN body;"),
+	})
+	m, err := migrate.New(db, zap.NewNop().Sugar(), all, time.Second)
+	require.NoError(t, err, "failed to create migrator")
+
+	t.Run("should apply pending migrations in order of versions", func(t *testing.T) {
+		applied, err := m.Up(context.Background())
+		require.NoError(t, err, "failed to apply migrations")
+		require.Len(t, applied, 3, "invalid number of applied migrations")
+		require.Equal(t, []int64{1, 2, 3}, []int64{applied[0].Version, applied[1].Version, applied[2].Version}, "invalid order")
+		require.True(t, db.Migrator().HasColumn("notes", "body"), "column was not added")
+
+		applied, err = m.Up(context.Background())
+		require.NoError(t, err, "failed to apply migrations again")
+		require.Empty(t, applied, "applied migrations were applied again")
+	})
+
+	t.Run("should refuse to roll back migration without down step", func(t *testing.T) {
+		_, err := m.Down(context.Background(), 1)
+		require.Error(t, err, "migration without down step was rolled back")
+		require.True(t, db.Migrator().HasTable("tags"), "table was dropped")
+	})
+
+	t.Run("should report status of migrations", func(t *testing.T) {
+		// record of migration unknown to binary
+		err := db.Create(&migrate.Record{Version: 4, Name: "removed", AppliedAt: time.Now()}).Error
+		require.NoError(t, err, "failed to create record")
+
+		statuses, err := m.Status(context.Background())
+		require.NoError(t, err, "failed to get status")
+		require.Len(t, statuses, 4, "invalid number of statuses")
+		require.NotNil(t, statuses[0].AppliedAt, "applied migration is pending")
+		require.True(t, statuses[3].Missing, "unknown migration is not missing")
+
+		var out bytes.Buffer
+		err = app.PrintMigrationStatus(&out, statuses)
+		require.NoError(t, err, "failed to print status")
+		require.Contains(t, out.String(), "add_note_body", "status has no migration name")
+		require.Contains(t, out.String(), "(missing)", "status has no missing migration")
+
+		err = db.Delete(&migrate.Record{}, 4).Error
+		require.NoError(t, err, "failed to delete record")
+	})
+
+	t.Run("should roll back latest migrations", func(t *testing.T) {
+		err := db.Migrator().DropTable("tags")
+		require.NoError(t, err, "failed to drop table")
+		// migration of tags is no longer known to binary
+		err = db.Delete(&migrate.Record{}, 3).Error
+		require.NoError(t, err, "failed to delete record")
+
+		m, err := migrate.New(db, zap.NewNop().Sugar(), []migrate.Migration{files[0], all[2]}, time.Second)
+		require.NoError(t, err, "failed to create migrator")
+
+		rolledBack, err := m.Down(context.Background(), 2)
+		require.NoError(t, err, "failed to roll back migrations")
+		require.Len(t, rolledBack, 2, "invalid number of rolled back migrations")
+		require.Equal(t, int64(2), rolledBack[0].Version, "latest migration was not rolled back first")
+		require.False(t, db.Migrator().HasTable("notes"), "table was not dropped")
+
+		statuses, err := m.Status(context.Background())
+		require.NoError(t, err, "failed to get status")
+		for _, status := range statuses {
+			require.Nil(t, status.AppliedAt, "rolled back migration is applied")
+		}
+	})
+
+	t.Run("should create migration files", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "sql")
+		now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+		up, down, err := migrate.Create(dir, "Add Note Title", now)
+		require.NoError(t, err, "failed to create migration")
+		require.Equal(t, filepath.Join(dir, "20261018120000_add_note_title.up.sql"), up, "invalid up file")
+		require.FileExists(t, down, "down file was not created")
+
+		_, _, err = migrate.Create(dir, "Add Note Title", now)
+		require.Error(t, err, "existing migration was overwritten")
+
+		_, _, err = migrate.Create(dir, "add-note!", now)
+		require.Error(t, err, "invalid name was accepted")
+
+		loaded, err := migrate.Load(os.DirFS(dir), ".")
+		require.NoError(t, err, "failed to load created migration")
+		require.Len(t, loaded, 1, "invalid number of loaded migrations")
+	})
+
+	t.Run("should load embedded migrations", func(t *testing.T) {
+		all, err := migrations.All()
+		require.NoError(t, err, "failed to load embedded migrations")
+		require.NotEmpty(t, all, "no embedded migrations")
+
+		_, err = migrate.New(db, zap.NewNop().Sugar(), all, time.Second)
+		require.NoError(t, err, "invalid embedded migrations")
+	})
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit(t *testing.T) {
+	// redis stand-in for shared store
+	redisServer, err := miniredis.Run()
+	require.NoError(t, err, "failed to start redis stand-in")
+	defer redisServer.Close()
+
+	os.Setenv("GOREST_RATE_LIMIT_ENABLED", "true")
+	os.Setenv("GOREST_RATE_LIMIT_POLICIES", `{
+		"default": {"requests": 100, "period": "1m"},
+		"search": {"requests": 2, "period": "1h", "key": "ip"},
+		"tokens": {"requests": 1, "period": "1h", "key": "user"}
+	}`)
+	os.Setenv("GOREST_REDIS_ADDR", redisServer.Addr())
+	defer os.Unsetenv("GOREST_RATE_LIMIT_ENABLED")
+	defer os.Unsetenv("GOREST_RATE_LIMIT_POLICIES")
+	defer os.Unsetenv("GOREST_RATE_LIMIT_STORE")
+	defer os.Unsetenv("GOREST_REDIS_ADDR")
+
+	for _, store := range []string{"memory", "redis"} {
+		t.Run(store, func(t *testing.T) {
+			os.Setenv("GOREST_RATE_LIMIT_STORE", store)
+			redisServer.FlushAll()
+
+			// init service
+			a := apptest.New()
+
+			// request is used to send request from client ip with optional token
+			request := func(url string, ip string, token string, accept string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest("GET", url, nil)
+				req.RemoteAddr = ip + ":4321"
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				if accept != "" {
+					req.Header.Set("Accept", accept)
+				}
+				recorder := httptest.NewRecorder()
+				a.Echo.ServeHTTP(recorder, req)
+				return recorder
+			}
+
+			t.Run("should limit anonymous requests by ip", func(t *testing.T) {
+				url := "/api/v2/search?q=ratelimit&type=posts"
+				for i := 0; i < 2; i++ {
+					res := request(url, "10.0.0.1", "", "")
+					require.Equal(t, http.StatusOK, res.Code, "request was not allowed")
+					require.Equal(t, "2", res.Header().Get("RateLimit-Limit"), "invalid limit header")
+					require.Equal(t, strconv.Itoa(1-i), res.Header().Get("RateLimit-Remaining"), "invalid remaining header")
+					require.Equal(t, "2;w=3600", res.Header().Get("RateLimit-Policy"), "invalid policy header")
+				}
+
+				// limited request is answered in accepted format
+				res := request(url, "10.0.0.1", "", "application/xml")
+				require.Equal(t, http.StatusTooManyRequests, res.Code, "request was not limited")
+				require.NotEmpty(t, res.Header().Get("Retry-After"), "retry after header is missing")
+				var body problem.Problem
+				err := xml.Unmarshal(res.Body.Bytes(), &body)
+				require.NoError(t, err, "failed to decode xml response")
+				require.Equal(t, "rate limit exceeded", body.Detail, "invalid detail")
+				require.Equal(t, problem.CodeTooManyRequests, body.Code, "invalid code")
+
+				// other clients are not affected
+				res = request(url, "10.0.0.2", "", "")
+				require.Equal(t, http.StatusOK, res.Code, "other ip was limited")
+			})
+
+			t.Run("should ignore forwarded ip of untrusted clients", func(t *testing.T) {
+				url := "/api/v2/search?q=spoofed&type=posts"
+				for i := 0; i < 3; i++ {
+					req := httptest.NewRequest("GET", url, nil)
+					req.RemoteAddr = "10.0.0.5:4321"
+					req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+					req.Header.Set("X-Real-IP", fmt.Sprintf("203.0.113.%d", i))
+					res := httptest.NewRecorder()
+					a.Echo.ServeHTTP(res, req)
+
+					status := http.StatusOK
+					if i == 2 {
+						status = http.StatusTooManyRequests
+					}
+					require.Equal(t, status, res.Code, "forwarded ip was trusted")
+				}
+			})
+
+			t.Run("should limit authenticated requests by user", func(t *testing.T) {
+				tokenA := access.MustEncodeToken(&access.Token{UserID: uuid.New()}, a.Config.HMACSecret)
+				tokenB := access.MustEncodeToken(&access.Token{UserID: uuid.New()}, a.Config.HMACSecret)
+
+				res := request("/api/v2/tokens", "10.0.0.3", tokenA, "")
+				require.Equal(t, http.StatusOK, res.Code, "request was not allowed")
+
+				// same user is limited from other ip
+				res = request("/api/v2/tokens", "10.0.0.4", tokenA, "")
+				require.Equal(t, http.StatusTooManyRequests, res.Code, "request was not limited")
+
+				// other user is not affected from same ip
+				res = request("/api/v2/tokens", "10.0.0.3", tokenB, "")
+				require.Equal(t, http.StatusOK, res.Code, "other user was limited")
+			})
+
+			// buckets of redis store are shared through redis server
+			if store == "redis" {
+				require.NotEmpty(t, redisServer.Keys(), "buckets were not stored in redis")
+			}
+		})
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	os.Setenv("GOREST_TRUSTED_PROXIES", "10.1.0.0/16")
+	defer os.Unsetenv("GOREST_TRUSTED_PROXIES")
+
+	// init service
+	a := apptest.New()
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded string
+		ip        string
+	}{
+		{name: "should use forwarded ip of trusted proxy", peer: "10.1.0.1", forwarded: "203.0.113.7", ip: "203.0.113.7"},
+		{name: "should skip trusted proxies in chain", peer: "10.1.0.1", forwarded: "203.0.113.7, 10.1.0.2", ip: "203.0.113.7"},
+		{name: "should use peer ip of untrusted client", peer: "10.2.0.1", forwarded: "203.0.113.7", ip: "10.2.0.1"},
+		{name: "should not trust private network by default", peer: "192.168.0.1", forwarded: "203.0.113.7", ip: "192.168.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.peer + ":4321"
+			req.Header.Set("X-Forwarded-For", tt.forwarded)
+
+			ip := a.Echo.NewContext(req, httptest.NewRecorder()).RealIP()
+			require.Equal(t, tt.ip, ip, "invalid client ip")
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/keyring"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRekey(t *testing.T) {
+	// init service, records are rolled back after test
+	a, h := apptest.Boot()
+	h.Isolate(t)
+
+	// rawToken is used to read stored column value bypassing serializer
+	rawToken := func(id uuid.UUID) string {
+		var raw string
+		err := a.MySQL.
+			Table("auth_providers").
+			Select("refresh_token").
+			Where("id = ?", id).
+			Scan(&raw).
+			Error
+		require.NoError(t, err, "failed to read raw token")
+		return raw
+	}
+
+	// create test providers of user, one with legacy plaintext token
+	userID := h.User().Create(t).ID
+	encrypted := models.AuthProvider{
+		UserID:           userID,
+		ProviderUID:      "Rekey#1",
+		RefreshToken:     "rekey-encrypted-token",
+		AuthProviderType: models.AuthProviderTypeGoogle,
+	}
+	err := a.MySQL.Create(&encrypted).Error
+	require.NoError(t, err, "failed to create test provider")
+
+	legacyID := uuid.New()
+	err = a.MySQL.Exec(
+		"INSERT INTO auth_providers (id, created_at, updated_at, user_id, provider_uid, refresh_token, auth_provider_type) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		legacyID, time.Now(), time.Now(), userID, "Rekey#2", "rekey-legacy-token", models.AuthProviderTypeGithub,
+	).Error
+	require.NoError(t, err, "failed to create legacy provider")
+
+	t.Run("should store provider token encrypted", func(t *testing.T) {
+		raw := rawToken(encrypted.ID)
+		require.True(t, strings.HasPrefix(raw, "enc:"+a.KeyRing.Primary()+":"), "token is not encrypted with primary key")
+		require.NotContains(t, raw, encrypted.RefreshToken, "token is stored in plaintext")
+
+		var provider models.AuthProvider
+		err := a.MySQL.First(&provider, "id = ?", encrypted.ID).Error
+		require.NoError(t, err, "failed to find provider")
+		require.Equal(t, encrypted.RefreshToken, provider.RefreshToken, "token was not decrypted")
+	})
+
+	t.Run("should read legacy plaintext token", func(t *testing.T) {
+		var provider models.AuthProvider
+		err := a.MySQL.First(&provider, "id = ?", legacyID).Error
+		require.NoError(t, err, "failed to find provider")
+		require.Equal(t, "rekey-legacy-token", provider.RefreshToken, "unexpected token")
+	})
+
+	t.Run("should rekey tokens with rotated primary key", func(t *testing.T) {
+		// rotate key, previous primary key stays in ring for decryption
+		key := make([]byte, keyring.KeySize)
+		_, err := rand.Read(key)
+		require.NoError(t, err, "failed to generate key")
+
+		oldRing := a.KeyRing
+		ring, err := oldRing.Rotate("rotated", key)
+		require.NoError(t, err, "failed to rotate key ring")
+
+		a.KeyRing = ring
+		defer func() { a.KeyRing = oldRing }()
+
+		err = a.Rekey()
+		require.NoError(t, err, "failed to rekey")
+
+		for id, want := range map[uuid.UUID]string{encrypted.ID: encrypted.RefreshToken, legacyID: "rekey-legacy-token"} {
+			raw := rawToken(id)
+			require.True(t, strings.HasPrefix(raw, "enc:rotated:"), "token is not encrypted with rotated key")
+
+			plaintext, err := ring.Decrypt(raw)
+			require.NoError(t, err, "failed to decrypt with rotated ring")
+			require.Equal(t, want, plaintext, "unexpected plaintext")
+		}
+	})
+}
@@ -1,20 +1,22 @@
 package comments
 
 import (
-	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 )
 
 // Represent input data of CreateCommentHandler
 type CreateCommentHandlerRequestBody struct {
-	PostID string `json:"postId" form:"postId" url:"postId" binding:"required" validate:"required"`
+	PostID string `json:"postId" form:"postId" url:"postId" binding:"required" validate:"required,post"`
 	Name   string `json:"name" form:"name" url:"name" binding:"required" validate:"required"`
-	Body   string `json:"body" form:"body" url:"body" binding:"required" validate:"required"`
+	Body   string `json:"body" form:"body" url:"body" binding:"required" validate:"required,nohtml"`
 } // @name CreateCommentRequest
 
 // Represent output data of CreateCommentHandler
@@ -25,7 +27,7 @@ type CreateCommentHandlerResponseBody struct {
 
 // Creates comment instance and stores it in database
 func (c *Comments) CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
-	logger := c.Logger.Named("CreateCommentHandler")
+	logger := tracing.Logger(r.Context(), c.Logger.Named("CreateCommentHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -34,20 +36,24 @@ func (c *Comments) CreateCommentHandler(w http.ResponseWriter, r *http.Request)
 	// parse body data
 	logger.Infow("parsing request body")
 	var body CreateCommentHandlerRequestBody
-	err := json.NewDecoder(r.Body).Decode(&body)
+	err := c.Negotiator.Decode(r, &body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, negotiate.ErrUnsupportedMediaType) {
+			c.HTTPErrorWriter(w, r, problem.From(err))
+			return
+		}
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse request body"))
 		return
 	}
 	logger = logger.With("body", body)
 
 	// validate body data
 	logger.Infow("validating request body")
-	err = c.Validator.Struct(&body)
+	err = c.Validator.StructCtx(r.Context(), &body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.Validation("failed to validate body", err))
 		return
 	}
 
@@ -56,7 +62,7 @@ func (c *Comments) CreateCommentHandler(w http.ResponseWriter, r *http.Request)
 	postUuid, err := uuid.Parse(string(body.PostID))
 	if err != nil {
 		logger.Errorw("failed to parse uuids from body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuids from body"))
 		return
 	}
 	logger = logger.With("postUuid", postUuid)
@@ -69,13 +75,10 @@ func (c *Comments) CreateCommentHandler(w http.ResponseWriter, r *http.Request)
 		Name:   body.Name,
 		Body:   body.Body,
 	}
-	err = c.MySQL.
-		Model(&models.Comment{}).
-		Create(&comment).
-		Error
+	err = c.Comments.Create(r.Context(), &comment)
 	if err != nil {
 		logger.Errorw("failed to save comment in database", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to save comment in database"))
 		return
 	}
 
@@ -87,32 +90,10 @@ func (c *Comments) CreateCommentHandler(w http.ResponseWriter, r *http.Request)
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully created comment record in database")
+	err = c.HTTPResponseWriter(w, r, http.StatusCreated, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusCreated)
-
-	logger.Infow("successfully created comment record in database")
-	w.Write(b)
 }
@@ -1,16 +1,16 @@
 package comments
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
-	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 // Represent output data of DeleteCommentHandler
@@ -20,7 +20,7 @@ type DeleteCommentHandlerResponseBody struct {
 
 // Deletes comment by provided id from database
 func (c *Comments) DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
-	logger := c.Logger.Named("DeleteCommentHandler")
+	logger := tracing.Logger(r.Context(), c.Logger.Named("DeleteCommentHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -33,7 +33,7 @@ func (c *Comments) DeleteCommentHandler(w http.ResponseWriter, r *http.Request)
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -43,46 +43,41 @@ func (c *Comments) DeleteCommentHandler(w http.ResponseWriter, r *http.Request)
 	commentUuid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("commentUuid", commentUuid)
 
 	// getting comment from database
-	var comment models.Comment
 	logger.Infow("getting comment from database")
-	err = c.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: commentUuid}}).
-		First(&comment).
-		Error
+	comment, err := c.Comments.Get(r.Context(), commentUuid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment record in database with provided id", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			c.HTTPErrorWriter(w, r, problem.NotFound("failed to find comment record in database with provided id"))
 			return
 		}
 		logger.Errorw("failed to find comment record in database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.HTTPErrorWriter(w, r, problem.Internal("failed to find comment record in database"))
 		return
 	}
 	logger = logger.With("comment", comment)
 
-	// check if user is comment author
-	logger.Infow("checking if user is author a comment")
-	if token.UserID != comment.UserID {
-		logger.Errorw("user is not author of current comment", "err", err)
-		err := errors.New("user is not author of current comment")
-		http.Error(w, err.Error(), http.StatusForbidden)
+	// check if user is allowed to delete comment
+	logger.Infow("checking if user is allowed to delete comment")
+	err = policy.Authorize(token, policy.ResourceComment, policy.ActionDelete, comment.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to delete current comment", "err", err)
+		c.HTTPErrorWriter(w, r, problem.Forbidden("user is not allowed to delete current comment"))
 		return
 	}
 
 	// delete comment from database
 	logger.Infow("deleting comment from database")
-	err = c.MySQL.Delete(&comment).Error
+	err = c.Comments.Delete(r.Context(), comment)
 	if err != nil {
 		logger.Errorw("failed to delete comment with provided id from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.HTTPErrorWriter(w, r, problem.Internal("failed to delete comment with provided id from database"))
 		return
 	}
 
@@ -93,32 +88,10 @@ func (c *Comments) DeleteCommentHandler(w http.ResponseWriter, r *http.Request)
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully deleted comment from database")
+	err = c.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully deleted comment from database")
-	w.Write(b)
 }
@@ -1,15 +1,15 @@
 package comments
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 // Represent output data of GetCommentHandler
@@ -20,7 +20,7 @@ type GetCommentHandlerResponseBody struct {
 
 // Gets comment by provided id from database, returns comment
 func (c *Comments) GetCommentHandler(w http.ResponseWriter, r *http.Request) {
-	logger := c.Logger.Named("GetCommentHandler")
+	logger := tracing.Logger(r.Context(), c.Logger.Named("GetCommentHandler"))
 
 	// get id from path
 	logger.Infow("getting id from path")
@@ -29,7 +29,7 @@ func (c *Comments) GetCommentHandler(w http.ResponseWriter, r *http.Request) {
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -39,28 +39,23 @@ func (c *Comments) GetCommentHandler(w http.ResponseWriter, r *http.Request) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("uid", uid)
 
 	// retreive comment from database
 	logger.Infow("getting comment from database")
-	var comment models.Comment
-	err = c.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: uid}}).
-		First(&comment).
-		Error
+	comment, err := c.Comments.Get(r.Context(), uid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment with provided id in database", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			c.HTTPErrorWriter(w, r, problem.NotFound("failed to find comment with provided id in database"))
 			return
 		}
 
 		logger.Errorw("failed to get comment from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.HTTPErrorWriter(w, r, problem.Internal("failed to get comment from database"))
 		return
 	}
 	logger = logger.With("comment", comment)
@@ -68,37 +63,15 @@ func (c *Comments) GetCommentHandler(w http.ResponseWriter, r *http.Request) {
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetCommentHandlerResponseBody{
-		Comment: &comment,
+		Comment: comment,
 		Message: "successfully retrieved comment",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully retrieved comment by id from database")
+	err = c.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully retrieved comment by id from database")
-	w.Write(b)
 }
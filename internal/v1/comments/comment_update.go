@@ -1,22 +1,24 @@
 package comments
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 // Represent input data of UpdateCommentHandler
 type UpdateCommentHandlerRequestBody struct {
 	Name string `json:"name" form:"name" url:"name" binding:"required" validate:"required"`
-	Body string `json:"body" form:"body" url:"body" binding:"required" validate:"required"`
+	Body string `json:"body" form:"body" url:"body" binding:"required" validate:"required,nohtml"`
 } // @UpdateCommentRequest
 
 // Represent output data of UpdateCommentHandler
@@ -27,7 +29,7 @@ type UpdateCommentHandlerResponseBody struct {
 
 // Updates post instance in database
 func (c *Comments) UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
-	logger := c.Logger.Named("UpdateCommentHandler")
+	logger := tracing.Logger(r.Context(), c.Logger.Named("UpdateCommentHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -40,7 +42,7 @@ func (c *Comments) UpdateCommentHandler(w http.ResponseWriter, r *http.Request)
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -50,7 +52,7 @@ func (c *Comments) UpdateCommentHandler(w http.ResponseWriter, r *http.Request)
 	commentUuid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("commentUuid", commentUuid)
@@ -58,10 +60,14 @@ func (c *Comments) UpdateCommentHandler(w http.ResponseWriter, r *http.Request)
 	// parse body data
 	logger.Infow("parsing request body")
 	var body UpdateCommentHandlerRequestBody
-	err = json.NewDecoder(r.Body).Decode(&body)
+	err = c.Negotiator.Decode(r, &body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, negotiate.ErrUnsupportedMediaType) {
+			c.HTTPErrorWriter(w, r, problem.From(err))
+			return
+		}
+		c.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse request body"))
 		return
 	}
 	logger = logger.With("body", body)
@@ -71,85 +77,57 @@ func (c *Comments) UpdateCommentHandler(w http.ResponseWriter, r *http.Request)
 	err = c.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		c.HTTPErrorWriter(w, r, problem.Validation("failed to validate body", err))
 		return
 	}
 
 	// getting comment from database
-	var comment models.Comment
 	logger.Infow("getting comment from database")
-	err = c.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: commentUuid}}).
-		First(&comment).
-		Error
+	comment, err := c.Comments.Get(r.Context(), commentUuid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment record in database with provided id", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			c.HTTPErrorWriter(w, r, problem.NotFound("failed to find comment record in database with provided id"))
 			return
 		}
 		logger.Errorw("failed to find comment record in database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.HTTPErrorWriter(w, r, problem.Internal("failed to find comment record in database"))
 		return
 	}
 	logger = logger.With("comment", comment)
 
-	// check if user is comment author
-	logger.Infow("checking if user is author a comment")
-	if token.UserID != comment.UserID {
-		logger.Errorw("user is not author of current comment", "err", err)
-		err := errors.New("user is not author of current comment")
-		http.Error(w, err.Error(), http.StatusForbidden)
+	// check if user is allowed to update comment
+	logger.Infow("checking if user is allowed to update comment")
+	err = policy.Authorize(token, policy.ResourceComment, policy.ActionUpdate, comment.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to update current comment", "err", err)
+		c.HTTPErrorWriter(w, r, problem.Forbidden("user is not allowed to update current comment"))
 		return
 	}
 
 	// update post in database
 	logger.Infow("updating post in database")
-	err = c.MySQL.
-		Model(&comment).
-		Updates(&models.Comment{Name: body.Name, Body: body.Body}).
-		Error
+	comment.Name = body.Name
+	comment.Body = body.Body
+	err = c.Comments.Update(r.Context(), comment)
 	if err != nil {
 		logger.Errorw("failed to update comment in database", "err", err)
-		http.Error(w, err.Error(), http.StatusForbidden)
+		c.HTTPErrorWriter(w, r, problem.Forbidden("failed to update comment in database"))
 		return
 	}
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := UpdateCommentHandlerResponseBody{
-		Comment: &comment,
+		Comment: comment,
 		Message: "successfully updated post",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully updated post in database")
+	err = c.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully updated post in database")
-	w.Write(b)
 }
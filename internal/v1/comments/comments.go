@@ -3,6 +3,7 @@ package comments
 import (
 	"net/http"
 
+	"github.com/Tamplier2911/gorest/pkg/problem"
 	"github.com/Tamplier2911/gorest/pkg/service"
 )
 
@@ -19,9 +20,9 @@ func (c Comments) Setup(s *service.Service) {
 		case http.MethodGet:
 			c.GetCommentsHandler(w, r)
 		case http.MethodPost:
-			service.AuthWrapperDP(c.CreateCommentHandler, c.Logger, c.Config, w, r)
+			service.AuthWrapperDP(c.CreateCommentHandler, c.Logger, c.Tokens, c.Revocations, w, r)
 		default:
-			w.WriteHeader(http.StatusNotFound)
+			c.HTTPErrorWriter(w, r, problem.NotFound("not found"))
 		}
 	})
 
@@ -30,11 +31,11 @@ func (c Comments) Setup(s *service.Service) {
 		case http.MethodGet:
 			c.GetCommentHandler(w, r)
 		case http.MethodPut:
-			service.AuthWrapperDP(c.UpdateCommentHandler, c.Logger, c.Config, w, r)
+			service.AuthWrapperDP(c.UpdateCommentHandler, c.Logger, c.Tokens, c.Revocations, w, r)
 		case http.MethodDelete:
-			service.AuthWrapperDP(c.DeleteCommentHandler, c.Logger, c.Config, w, r)
+			service.AuthWrapperDP(c.DeleteCommentHandler, c.Logger, c.Tokens, c.Revocations, w, r)
 		default:
-			w.WriteHeader(http.StatusNotFound)
+			c.HTTPErrorWriter(w, r, problem.NotFound("not found"))
 		}
 	})
 }
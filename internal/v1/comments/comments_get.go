@@ -1,14 +1,14 @@
 package comments
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"net/http"
 	"strconv"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm/clause"
 )
 
 // Represent output data of GetCommentsHandler
@@ -20,10 +20,10 @@ type GetCommentsHandlerResponseBody struct {
 
 // Get all comments from database, takes limit and offset query parameters, returns comments
 func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
-	logger := c.Logger.Named("GetCommentsHandler")
+	logger := tracing.Logger(r.Context(), c.Logger.Named("GetCommentsHandler"))
 
-	// define db statement
-	stmt := c.MySQL.Model(&models.Comment{})
+	// define comments filter
+	var filter repository.CommentFilter
 
 	limit := 10
 	// get limit from query parameters
@@ -33,7 +33,7 @@ func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 		lmtInt, err := strconv.Atoi(lmt)
 		if err != nil {
 			logger.Infow("invalid limit in query")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			c.HTTPErrorWriter(w, r, problem.Internal("invalid limit in query"))
 			return
 		}
 		logger = logger.With("limit", limit)
@@ -48,7 +48,7 @@ func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 		ofstInt, err := strconv.Atoi(ofst)
 		if err != nil {
 			logger.Infow("invalid offset in query")
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			c.HTTPErrorWriter(w, r, problem.Internal("invalid offset in query"))
 			return
 		}
 		logger = logger.With("offset", offset)
@@ -62,11 +62,11 @@ func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 		postUuid, err := uuid.Parse(postId)
 		if err != nil {
 			logger.Errorw("failed to parse uuid from body", "err", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			c.HTTPErrorWriter(w, r, problem.Internal("failed to parse uuid from body"))
 			return
 		}
-		// add clause to statement
-		stmt.Where(&models.Comment{PostID: postUuid})
+		// add condition to filter
+		filter.PostID = postUuid
 	}
 
 	// get user id from query parameters
@@ -76,27 +76,19 @@ func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 		userUuid, err := uuid.Parse(userId)
 		if err != nil {
 			logger.Errorw("failed to parse uuid from body", "err", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			c.HTTPErrorWriter(w, r, problem.Internal("failed to parse uuid from body"))
 			return
 		}
-		// add clause to statement
-		stmt.Where(&models.Comment{UserID: userUuid})
+		// add condition to filter
+		filter.UserID = userUuid
 	}
 
 	// retreive comments from database
 	logger.Infow("getting comments from database")
-	var total int64
-	var comments []models.Comment
-	err := stmt.
-		Count(&total).
-		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}).
-		Limit(limit).
-		Offset(offset).
-		Find(&comments).
-		Error
+	comments, list, err := c.Comments.List(r.Context(), filter, repository.ListOptions{Limit: limit, Offset: offset})
 	if err != nil {
 		logger.Errorw("failed to get comments from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		c.HTTPErrorWriter(w, r, problem.Internal("failed to get comments from database"))
 		return
 	}
 	logger = logger.With("comments", comments)
@@ -105,37 +97,15 @@ func (c *Comments) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Infow("assembling response body")
 	res := GetCommentsHandlerResponseBody{
 		Comments: &comments,
-		Total:    total,
+		Total:    list.Total,
 		Message:  "successfully retrieved comments",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully retrieved all comments from database")
+	err = c.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully retrieved all comments from database")
-	w.Write(b)
 }
@@ -3,7 +3,7 @@ package tests
 import (
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v1/comments"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
@@ -14,8 +14,7 @@ import (
 
 func TestCreateCommentHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v1/comments"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
@@ -14,8 +14,7 @@ import (
 
 func TestGetCommentHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -1,18 +1,20 @@
 package posts
 
 import (
-	"encoding/json"
-	"encoding/xml"
+	"errors"
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 )
 
 // Represent input data of CreatePostHandler
 type CreatePostHandlerRequestBody struct {
-	Title string `json:"title" form:"title" url:"title" binding:"required" validate:"required"`
-	Body  string `json:"body" form:"body" url:"body" binding:"required" validate:"required"`
+	Title string `json:"title" form:"title" url:"title" binding:"required" validate:"required,title"`
+	Body  string `json:"body" form:"body" url:"body" binding:"required" validate:"required,nohtml"`
 } // @CreatePostResponse
 
 // Represent output data of CreatePostHandler
@@ -23,7 +25,7 @@ type CreatePostHandlerResponseBody struct {
 
 // Creates post instance and stores it in database
 func (p *Posts) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
-	logger := p.Logger.Named("CreatePostHandler")
+	logger := tracing.Logger(r.Context(), p.Logger.Named("CreatePostHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -32,10 +34,14 @@ func (p *Posts) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 	// parse body data
 	logger.Infow("parsing request body")
 	var body CreatePostHandlerRequestBody
-	err := json.NewDecoder(r.Body).Decode(&body)
+	err := p.Negotiator.Decode(r, &body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, negotiate.ErrUnsupportedMediaType) {
+			p.HTTPErrorWriter(w, r, problem.From(err))
+			return
+		}
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse request body"))
 		return
 	}
 	logger = logger.With("body", body)
@@ -45,7 +51,7 @@ func (p *Posts) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 	err = p.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.Validation("failed to validate body", err))
 		return
 	}
 
@@ -56,10 +62,10 @@ func (p *Posts) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 		Title:  body.Title,
 		Body:   body.Body,
 	}
-	err = p.MySQL.Model(&models.Post{}).Create(&post).Error
+	err = p.Posts.Create(r.Context(), &post)
 	if err != nil {
 		logger.Errorw("failed to save post in database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to save post in database"))
 		return
 	}
 
@@ -71,32 +77,10 @@ func (p *Posts) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully created post record in database")
+	err = p.HTTPResponseWriter(w, r, http.StatusCreated, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusCreated)
-
-	logger.Infow("successfully created post record in database")
-	w.Write(b)
 }
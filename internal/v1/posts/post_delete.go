@@ -1,17 +1,16 @@
 package posts
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
-	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // Represent output data of DeletePostHandler
@@ -21,7 +20,7 @@ type DeletePostHandlerResponseBody struct {
 
 // Deletes post by provided id from database
 func (p *Posts) DeletePostHandler(w http.ResponseWriter, r *http.Request) {
-	logger := p.Logger.Named("DeletePostsHandler")
+	logger := tracing.Logger(r.Context(), p.Logger.Named("DeletePostsHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -34,7 +33,7 @@ func (p *Posts) DeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -44,49 +43,41 @@ func (p *Posts) DeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("uid", uid)
 
 	// get post from database
-	var post models.Post
 	logger.Infow("getting post from database")
-	err = p.MySQL.
-		Model(&models.Post{}).
-		Where(&models.Post{Base: models.Base{ID: uid}}).
-		First(&post).
-		Error
+	post, err := p.Posts.Get(r.Context(), uid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post record in database with provided id", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			p.HTTPErrorWriter(w, r, problem.NotFound("failed to find post record in database with provided id"))
 			return
 		}
 		logger.Errorw("failed to find post record in database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to find post record in database"))
 		return
 	}
 	logger = logger.With("post", post)
 
-	// check if user is post author
-	logger.Infow("checking if user is author a post")
-	if token.UserID != post.UserID {
-		logger.Errorw("user is not author of current post", "err", err)
-		err := errors.New("user is not author of current post")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// check if user is allowed to delete post
+	logger.Infow("checking if user is allowed to delete post")
+	err = policy.Authorize(token, policy.ResourcePost, policy.ActionDelete, post.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to delete current post", "err", err)
+		p.HTTPErrorWriter(w, r, problem.Forbidden("user is not allowed to delete current post"))
 		return
 	}
 
 	// delete post from database
 	logger.Infow("deleting post from database")
-	err = p.MySQL.
-		Select(clause.Associations).
-		Delete(&post).
-		Error
+	err = p.Posts.Delete(r.Context(), post)
 	if err != nil {
 		logger.Errorw("failed to delete post record from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to delete post record from database"))
 		return
 	}
 
@@ -97,32 +88,10 @@ func (p *Posts) DeletePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully deleted post from database")
+	err = p.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully deleted post from database")
-	w.Write(b)
 }
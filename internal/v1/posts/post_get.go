@@ -1,15 +1,15 @@
 package posts
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 // Represent output data of GetPostHandler
@@ -20,7 +20,7 @@ type GetPostHandlerResponseBody struct {
 
 // Gets post by provided id from database, returns posts
 func (p *Posts) GetPostHandler(w http.ResponseWriter, r *http.Request) {
-	logger := p.Logger.Named("GetPostHandler")
+	logger := tracing.Logger(r.Context(), p.Logger.Named("GetPostHandler"))
 
 	// get id from path
 	logger.Infow("getting id from path")
@@ -29,7 +29,7 @@ func (p *Posts) GetPostHandler(w http.ResponseWriter, r *http.Request) {
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -39,24 +39,23 @@ func (p *Posts) GetPostHandler(w http.ResponseWriter, r *http.Request) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("uid", uid)
 
 	// retreive post from database
 	logger.Infow("getting post from database")
-	var post models.Post
-	err = p.MySQL.Model(&models.Post{}).Where(&models.Post{Base: models.Base{ID: uid}}).First(&post).Error
+	post, err := p.Posts.Get(r.Context(), uid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post with provided id in database", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			p.HTTPErrorWriter(w, r, problem.NotFound("failed to find post with provided id in database"))
 			return
 		}
 
 		logger.Errorw("failed to get posts from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to get posts from database"))
 		return
 	}
 	logger = logger.With("post", post)
@@ -64,37 +63,15 @@ func (p *Posts) GetPostHandler(w http.ResponseWriter, r *http.Request) {
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetPostHandlerResponseBody{
-		Post:    &post,
+		Post:    post,
 		Message: "successfully retrieved post",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully retrieved post by id from database")
+	err = p.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully retrieved post by id from database")
-	w.Write(b)
 }
@@ -1,22 +1,24 @@
 package posts
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 // Represent input data of UpdatePostHandler
 type UpdatePostHandlerRequestBody struct {
-	Title string `json:"title" form:"title" url:"title" binding:"required" validate:"required"`
-	Body  string `json:"body" form:"body" url:"body" binding:"required" validate:"required"`
+	Title string `json:"title" form:"title" url:"title" binding:"required" validate:"required,title"`
+	Body  string `json:"body" form:"body" url:"body" binding:"required" validate:"required,nohtml"`
 } // @name UpdatePostRequest
 
 // Represent output data of UpdatePostHandler
@@ -27,7 +29,7 @@ type UpdatePostHandlerResponseBody struct {
 
 // Updates post instance in database
 func (p *Posts) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
-	logger := p.Logger.Named("UpdatePostHandler")
+	logger := tracing.Logger(r.Context(), p.Logger.Named("UpdatePostHandler"))
 
 	// get token from context
 	token := r.Context().Value("token").(*access.Token)
@@ -40,7 +42,7 @@ func (p *Posts) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 	if id == "" {
 		err := errors.New("failed to get id from path")
 		logger.Errorw("failed to get id from path", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to get id from path"))
 		return
 	}
 	logger = logger.With("id", id)
@@ -50,7 +52,7 @@ func (p *Posts) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse uuid"))
 		return
 	}
 	logger = logger.With("uid", uid)
@@ -58,10 +60,14 @@ func (p *Posts) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 	// parse body data
 	logger.Infow("parsing request body")
 	var body UpdatePostHandlerRequestBody
-	err = json.NewDecoder(r.Body).Decode(&body)
+	err = p.Negotiator.Decode(r, &body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		if errors.Is(err, negotiate.ErrUnsupportedMediaType) {
+			p.HTTPErrorWriter(w, r, problem.From(err))
+			return
+		}
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to parse request body"))
 		return
 	}
 	logger = logger.With("body", body)
@@ -71,87 +77,57 @@ func (p *Posts) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 	err = p.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		p.HTTPErrorWriter(w, r, problem.Validation("failed to validate body", err))
 		return
 	}
 
 	// get post from database
-	var post models.Post
 	logger.Infow("getting post from database")
-	err = p.MySQL.
-		Model(&models.Post{}).
-		Where(&models.Post{Base: models.Base{ID: uid}}).
-		First(&post).
-		Error
+	post, err := p.Posts.Get(r.Context(), uid)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post record in database with provided id", "err", err)
-			http.Error(w, err.Error(), http.StatusNotFound)
+			p.HTTPErrorWriter(w, r, problem.NotFound("failed to find post record in database with provided id"))
 			return
 		}
 		logger.Errorw("failed to find post record in database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to find post record in database"))
 		return
 	}
 	logger = logger.With("post", post)
 
-	// check if user is post author
-	logger.Infow("checking if user is author a post")
-	if token.UserID != post.UserID {
-		logger.Errorw("user is not author of current post", "err", err)
-		err := errors.New("user is not author of current post")
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// check if user is allowed to update post
+	logger.Infow("checking if user is allowed to update post")
+	err = policy.Authorize(token, policy.ResourcePost, policy.ActionUpdate, post.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to update current post", "err", err)
+		p.HTTPErrorWriter(w, r, problem.Forbidden("user is not allowed to update current post"))
 		return
 	}
 
 	// update post in database
 	logger.Infow("updating post in database")
-	result := p.MySQL.
-		Model(&post).
-		Updates(&models.Post{Title: body.Title, Body: body.Body})
-	if result.Error != nil || result.RowsAffected == 0 {
-		if result.Error == nil {
-			result.Error = errors.New("record not found")
-		}
-		logger.Errorw("failed to update post in database", "err", result.Error)
-		http.Error(w, result.Error.Error(), http.StatusBadRequest)
+	post.Title = body.Title
+	post.Body = body.Body
+	err = p.Posts.Update(r.Context(), post)
+	if err != nil {
+		logger.Errorw("failed to update post in database", "err", err)
+		p.HTTPErrorWriter(w, r, problem.BadRequest("failed to update post in database"))
 		return
 	}
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := UpdatePostHandlerResponseBody{
-		Post:    &post,
+		Post:    post,
 		Message: "successfully updated post",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully updated post in database")
+	err = p.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully updated post in database")
-	w.Write(b)
 }
@@ -3,6 +3,7 @@ package posts
 import (
 	"net/http"
 
+	"github.com/Tamplier2911/gorest/pkg/problem"
 	"github.com/Tamplier2911/gorest/pkg/service"
 )
 
@@ -19,9 +20,9 @@ func (p Posts) Setup(s *service.Service) {
 		case http.MethodGet:
 			p.GetPostsHandler(w, r)
 		case http.MethodPost:
-			service.AuthWrapperDP(p.CreatePostHandler, p.Logger, p.Config, w, r)
+			service.AuthWrapperDP(p.CreatePostHandler, p.Logger, p.Tokens, p.Revocations, w, r)
 		default:
-			w.WriteHeader(http.StatusNotFound)
+			p.HTTPErrorWriter(w, r, problem.NotFound("not found"))
 		}
 	})
 
@@ -30,11 +31,11 @@ func (p Posts) Setup(s *service.Service) {
 		case http.MethodGet:
 			p.GetPostHandler(w, r)
 		case http.MethodPut:
-			service.AuthWrapperDP(p.UpdatePostHandler, p.Logger, p.Config, w, r)
+			service.AuthWrapperDP(p.UpdatePostHandler, p.Logger, p.Tokens, p.Revocations, w, r)
 		case http.MethodDelete:
-			service.AuthWrapperDP(p.DeletePostHandler, p.Logger, p.Config, w, r)
+			service.AuthWrapperDP(p.DeletePostHandler, p.Logger, p.Tokens, p.Revocations, w, r)
 		default:
-			w.WriteHeader(http.StatusNotFound)
+			p.HTTPErrorWriter(w, r, problem.NotFound("not found"))
 		}
 	})
 }
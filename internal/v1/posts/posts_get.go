@@ -1,13 +1,13 @@
 package posts
 
 import (
-	"encoding/json"
-	"encoding/xml"
 	"net/http"
 	"strconv"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
-	"gorm.io/gorm/clause"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 )
 
 // Represent output data of GetPostsHandler
@@ -19,10 +19,10 @@ type GetPostsHandlerResponseBody struct {
 
 // Get all posts from database, takes limit and offset query parameters, returns posts
 func (p *Posts) GetPostsHandler(w http.ResponseWriter, r *http.Request) {
-	logger := p.Logger.Named("GetPostsHandler")
+	logger := tracing.Logger(r.Context(), p.Logger.Named("GetPostsHandler"))
 
-	// define db statement
-	stmt := p.MySQL.Model(&models.Post{})
+	// define listing options, posts are not limited by default
+	var opts repository.ListOptions
 
 	// get limit from query parameters
 	limit := r.FormValue("limit")
@@ -31,9 +31,9 @@ func (p *Posts) GetPostsHandler(w http.ResponseWriter, r *http.Request) {
 		lm, err := strconv.Atoi(limit)
 		if err != nil {
 			logger.Infow("invalid limit query")
-			stmt.Limit(10)
+			opts.Limit = 10
 		} else {
-			stmt.Limit(lm)
+			opts.Limit = lm
 			logger = logger.With("limit", lm)
 		}
 	}
@@ -45,25 +45,19 @@ func (p *Posts) GetPostsHandler(w http.ResponseWriter, r *http.Request) {
 		of, err := strconv.Atoi(offset)
 		if err != nil {
 			logger.Infow("invalid offset query")
-			stmt.Offset(0)
+			opts.Offset = 0
 		} else {
-			stmt.Offset(of)
+			opts.Offset = of
 			logger = logger.With("offset", of)
 		}
 	}
 
 	// retreive posts from database
 	logger.Infow("getting posts from database")
-	var total int64
-	var posts []models.Post
-	err := stmt.
-		Count(&total).
-		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}).
-		Find(&posts).
-		Error
+	posts, list, err := p.Posts.List(r.Context(), opts)
 	if err != nil {
 		logger.Errorw("failed to get posts from database", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		p.HTTPErrorWriter(w, r, problem.Internal("failed to get posts from database"))
 		return
 	}
 	logger = logger.With("posts", posts)
@@ -72,37 +66,15 @@ func (p *Posts) GetPostsHandler(w http.ResponseWriter, r *http.Request) {
 	logger.Infow("assembling response body")
 	res := GetPostsHandlerResponseBody{
 		Posts:   &posts,
-		Total:   total,
+		Total:   list.Total,
 		Message: "successfully retrieved posts",
 	}
 	logger = logger.With("res", res)
 
-	// get clients accept header
-	accept := r.Header.Get("Accept")
-
-	var b []byte
-	switch accept {
-	case string(models.MimeTypesXML):
-		// response with xml
-		logger.Infow("marshaling response body to xml")
-		w.Header().Set("Content-Type", string(models.MimeTypesXML))
-		b, err = xml.Marshal(res)
-	default:
-		// default response with json
-		logger.Infow("marshaling response body to json")
-		w.Header().Set("Content-Type", string(models.MimeTypesJSON))
-		b, err = json.Marshal(res)
-	}
-
+	// write response in format accepted by client
+	logger.Infow("successfully retrieved all posts from database")
+	err = p.HTTPResponseWriter(w, r, http.StatusOK, res)
 	if err != nil {
-		logger.Errorw("failed to marshal response body", "err", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		logger.Errorw("failed to write response body", "err", err)
 	}
-
-	// write headers
-	w.WriteHeader(http.StatusOK)
-
-	logger.Infow("successfully retrieved all posts from database")
-	w.Write(b)
 }
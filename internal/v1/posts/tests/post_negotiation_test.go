@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostBodyNegotiation(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	token := access.MustEncodeToken(&access.Token{
+		UserID: testData.TestUserOneID,
+	}, a.Config.HMACSecret)
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	updateURL := fmt.Sprintf("/api/v1/posts/%s", testData.TestPostOneUserOneID)
+
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		body        string
+		status      int
+		code        problem.Code
+	}{
+		{name: "create with unsupported content type", method: "POST", url: "/api/v1/posts", contentType: "text/plain", body: "title", status: http.StatusUnsupportedMediaType, code: problem.CodeUnsupportedMediaType},
+		{name: "create with malformed body", method: "POST", url: "/api/v1/posts", contentType: "application/json", body: `{"title":`, status: http.StatusBadRequest, code: problem.CodeBadRequest},
+		{name: "update with unsupported content type", method: "PUT", url: updateURL, contentType: "text/plain", body: "title", status: http.StatusUnsupportedMediaType, code: problem.CodeUnsupportedMediaType},
+		{name: "update with malformed body", method: "PUT", url: updateURL, contentType: "application/json", body: `{"title":`, status: http.StatusBadRequest, code: problem.CodeBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+			a.Router.ServeHTTP(recorder, req)
+
+			var p problem.Problem
+			err := json.Unmarshal(recorder.Body.Bytes(), &p)
+			require.NoError(t, err, "failed to decode problem")
+			require.Equal(t, tt.status, recorder.Code, "unexpected status code")
+			require.Equal(t, tt.code, p.Code, "invalid code")
+		})
+	}
+}
@@ -1,88 +1,66 @@
 package auth
 
 import (
-	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
 	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/Tamplier2911/gorest/pkg/throttle"
 	"github.com/labstack/echo/v4"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/facebook"
-	"golang.org/x/oauth2/github"
-	"golang.org/x/oauth2/google"
 )
 
 type Auth struct {
 	*service.Service
-	GoogleOAuthConfig   *oauth2.Config
-	FacebookOauthConfig *oauth2.Config
-	GithubOauthConfig   *oauth2.Config
+
+	// identity providers by name used in login and callback routes
+	Providers map[string]Provider
+
+	// failed password logins by email and by ip address
+	EmailThrottle *throttle.Throttle
+	IPThrottle    *throttle.Throttle
 }
 
 func (a Auth) Setup(s *service.Service) {
 	a.Service = s
 
-	// setup google oauth config
-	a.GoogleOAuthConfig = &oauth2.Config{
-		RedirectURL:  a.Config.GoogleRedirectURL,
-		ClientID:     a.Config.GoogleClientID,
-		ClientSecret: a.Config.GoogleClientSecret,
-		Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email"},
-		Endpoint:     google.Endpoint,
+	// register built in and configured OpenID Connect providers
+	err := a.setupProviders()
+	if err != nil {
+		a.Logger.Fatalw("failed to setup auth providers", "err", err)
 	}
 
-	// configure router
-	GoogleAuthRouter := a.Echo.Group("/api/v2/auth/google")
-	GoogleAuthRouter.GET("/login", a.GoogleLoginHandler)
-	GoogleAuthRouter.GET("/callback", a.GoogleCallbackHandler)
-
-	// setup facebook oauth config
-	a.FacebookOauthConfig = &oauth2.Config{
-		ClientID:     a.Config.FacebookClientID,
-		ClientSecret: a.Config.FacebookClientSecret,
-		RedirectURL:  a.Config.FacebookRedirectURL,
-		Scopes:       []string{"public_profile", "email"},
-		Endpoint:     facebook.Endpoint,
-	}
-
-	// configure router
-	FacebookAuthRouter := a.Echo.Group("/api/v2/auth/facebook")
-	FacebookAuthRouter.GET("/login", a.FacebookLoginHandler)
-	FacebookAuthRouter.GET("/callback", a.FacebookCallbackHandler)
+	// block password guessing
+	a.EmailThrottle = throttle.New(a.Config.LoginMaxAttempts, a.Config.LoginThrottleWindow)
+	a.IPThrottle = throttle.New(a.Config.LoginIPMaxAttempts, a.Config.LoginThrottleWindow)
 
-	// setup github oauth config
-	a.GithubOauthConfig = &oauth2.Config{
-		ClientID:     a.Config.GithubClientID,
-		ClientSecret: a.Config.GithubClientSecret,
-		RedirectURL:  a.Config.GithubRedirectURL,
-		Scopes:       []string{"user:email"},
-		Endpoint:     github.Endpoint,
+	// limit requests of route group, authenticated requests are counted by user
+	limit := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return service.RateLimitMiddleware(a.Logger, a.RateLimiter, "auth", next)
 	}
 
 	// configure router
-	GithubAuthRouter := a.Echo.Group("/api/v2/auth/github")
-	GithubAuthRouter.GET("/login", a.GithubLoginHandler)
-	GithubAuthRouter.GET("/callback", a.GithubCallbackHandler)
-
 	AuthRouter := a.Echo.Group("/api/v2/auth")
-	AuthRouter.GET("/refresh", service.AuthenticationMiddleware(a.Logger, a.Config, a.RefreshTokenHandler))
-}
-
-// Writes response based on accept header
-// if header has application/xml mime type as first index, write response in xml else write response in json
-func (p *Auth) ResponseWriter(c echo.Context, statusCode int, res interface{}) error {
-	// check accept header
-	accept := c.Request().Header["Accept"]
-	if len(accept) == 0 {
-		// default response if accept header is not provided
-		return c.JSON(statusCode, res)
-	}
-
-	// based on first value in accept header write response
-	switch accept[0] {
-	case string(models.MimeTypesXML):
-		// response with xml
-		return c.XML(statusCode, res)
-	default:
-		// default response with json
-		return c.JSON(statusCode, res)
-	}
+	AuthRouter.POST("/refresh", limit(a.RefreshTokenHandler))
+	AuthRouter.POST("/logout", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations, limit(a.LogoutHandler)))
+	AuthRouter.POST("/logout/all", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations,
+		service.ScopeMiddleware(a.Logger, []string{policy.ScopeUsersWrite}, limit(a.LogoutAllHandler)),
+	))
+	AuthRouter.POST("/local/register", limit(a.RegisterHandler))
+	AuthRouter.POST("/local/verify", limit(a.VerifyEmailHandler))
+	AuthRouter.POST("/local/verify/resend", limit(a.ResendVerificationHandler))
+	AuthRouter.POST("/local/login", limit(a.LocalLoginHandler))
+	AuthRouter.POST("/local/password/forgot", limit(a.ForgotPasswordHandler))
+	AuthRouter.POST("/local/password/reset", limit(a.ResetPasswordHandler))
+	AuthRouter.PUT("/local/password", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations,
+		service.ScopeMiddleware(a.Logger, []string{policy.ScopeUsersWrite}, limit(a.ChangePasswordHandler)),
+	))
+	AuthRouter.GET("/:provider/login", limit(a.LoginHandler))
+	AuthRouter.GET("/:provider/callback", limit(a.CallbackHandler))
+	AuthRouter.GET("/providers", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations,
+		service.ScopeMiddleware(a.Logger, []string{policy.ScopeUsersRead}, limit(a.GetLinkedProvidersHandler)),
+	))
+	AuthRouter.POST("/:provider/link", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations,
+		service.ScopeMiddleware(a.Logger, []string{policy.ScopeUsersWrite}, limit(a.LinkProviderHandler)),
+	))
+	AuthRouter.DELETE("/:provider/link", service.AuthenticationMiddleware(a.Logger, a.Service, a.Revocations,
+		service.ScopeMiddleware(a.Logger, []string{policy.ScopeUsersWrite}, limit(a.UnlinkProviderHandler)),
+	))
 }
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/oauth2"
+)
+
+// Represents output body of CallbackHandler
+type CallbackHandlerResponseBody struct {
+	Token        *string `json:"token" xml:"token"`
+	RefreshToken *string `json:"refreshToken" xml:"refreshToken"`
+	Message      string  `json:"message" xml:"message"`
+} // @name CallbackResponse
+
+// CallbackHandler godoc
+//
+// @id				Callback
+// @Summary 		Callback triggered once user respond to provider authorization popup.
+// @Description 	Verifies code and state, exchanges code with authorization token,
+//
+//	resolves user identity with provider, finds user linked to identity or registers new user,
+//	signs JWT and responds with signed JWT token. Provider is linked to current user if flow was started by link.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param provider path string true "Name of identity provider"
+// @Param code query string true "Parameter for code grant"
+// @Param state query string true "Parameter for state"
+//
+// @Success 200 	{object} CallbackHandlerResponseBody
+// @Success 303 	{string} Url "post login redirect url with tokens in fragment"
+// @Failure 400,401,403,404,409 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /auth/{provider}/callback [GET]
+func (a *Auth) CallbackHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("CallbackHandler"))
+
+	// outbound calls use traced client and become children of request span
+	ctx := context.WithValue(c.Request().Context(), oauth2.HTTPClient, a.HTTPClient)
+
+	// get provider from path
+	name := c.Param("provider")
+	logger = logger.With("provider", name)
+	provider, ok := a.Providers[name]
+	if !ok {
+		logger.Errorw("unknown provider")
+		return problem.NotFound("unknown provider")
+	}
+
+	// get authorization grant from query
+	code := c.QueryParam("code")
+	logger = logger.With("code", code)
+
+	// check state against cookie set by login
+	logger.Infow("checking state")
+	state, verifier, err := a.verifyState(c, name)
+	if err != nil {
+		logger.Errorw("invalid state", "err", err)
+		return problem.Unauthorized("invalid auth state")
+	}
+	logger = logger.With("state", state)
+
+	config, err := provider.OAuthConfig(ctx)
+	if err != nil {
+		logger.Errorw("failed to get provider config", "err", err)
+		return problem.Internal("failed to login user")
+	}
+
+	// exchange authorization grant with token
+	logger.Infow("exchanging token")
+	token, err := config.Exchange(ctx, code, codeVerifier(verifier))
+	if err != nil {
+		logger.Errorw("failed to exchange token", "err", err)
+		return problem.Unauthorized("code exchange failed")
+	}
+
+	// resolve identity of user with provider
+	logger.Infow("getting user info")
+	identity, err := provider.Identity(ctx, token, state.Nonce)
+	if err != nil {
+		logger.Errorw("failed to get user info", "err", err)
+		return problem.Unauthorized("failed getting user info")
+	}
+	logger = logger.With("subject", identity.Subject, "email", identity.Email)
+
+	logger.Infow("successfully authorized with provider")
+
+	// attach provider to user which started linking instead of login
+	if state.UserID != "" {
+		return a.linkCallback(c, logger, name, state, identity)
+	}
+
+	// get auth provider linked to identity from database
+	logger.Infow("getting auth provider from database")
+	authProvider, err := a.AuthProviders.Find(c.Request().Context(), models.AuthProviderType(name), identity.Subject)
+	if err != nil && err != repository.ErrNotFound {
+		logger.Errorw("failed to find auth provider in database", "err", err)
+		return problem.Internal("failed to login user")
+	}
+
+	// if identity is linked login user owning auth provider
+	var user *models.User
+	if err == nil {
+		logger.Infow("getting user from database")
+		user, err = a.Users.Get(c.Request().Context(), authProvider.UserID)
+		if err != nil && err != repository.ErrNotFound {
+			logger.Errorw("failed to find user in database", "err", err)
+			return problem.Internal("failed to login user")
+		}
+
+		// if user was deleted remove stale auth provider and register identity again
+		if err == repository.ErrNotFound {
+			logger.Infow("user of auth provider was deleted, removing auth provider")
+			user = nil
+			err = a.AuthProviders.Delete(c.Request().Context(), authProvider)
+			if err != nil {
+				logger.Errorw("failed to delete auth provider from database", "err", err)
+				return problem.Internal("failed to login user")
+			}
+		}
+	}
+
+	// token is encrypted by serializer of model
+	// providers may not issue new refresh token on every login
+	if user != nil && identity.Token != "" {
+		logger.Infow("updating auth provider in database")
+		authProvider.RefreshToken = identity.Token
+		err = a.AuthProviders.UpdateRefreshToken(c.Request().Context(), authProvider)
+		if err != nil {
+			logger.Errorw("failed to update auth provider in database", "err", err)
+			return problem.Internal("failed to login user")
+		}
+	}
+
+	// if identity is not linked find user by email or register new user
+	if user == nil {
+		logger.Infow("identity is not linked, registering identity")
+		user, err = a.registerIdentity(a.MySQL.WithContext(c.Request().Context()), name, identity)
+		switch {
+		case errors.Is(err, errAccountExists):
+			logger.Errorw("user with email already exists", "err", err)
+			return problem.Conflict(err.Error())
+		case err != nil:
+			logger.Errorw("failed to register identity", "err", err)
+			return problem.Internal("failed to register new user")
+		}
+	}
+	logger = logger.With("user", user)
+
+	// start new session
+	logger.Infow("issuing session tokens")
+	accessToken, refreshToken, err := a.issueTokens(c, a.MySQL.WithContext(c.Request().Context()), user, uuid.Nil)
+	if err != nil {
+		logger.Errorw("failed to issue session tokens", "err", err)
+		return problem.Internal("failed to login user")
+	}
+
+	// redirect to post login url if requested
+	if state.Redirect != "" {
+		logger.Infow("successfully logged in, redirecting", "redirect", state.Redirect)
+		return a.loginRedirect(c, state.Redirect, accessToken, refreshToken)
+	}
+
+	logger.Infow("successfully logged in")
+	return a.ResponseWriter(c, http.StatusOK, CallbackHandlerResponseBody{
+		Token:        &accessToken,
+		RefreshToken: &refreshToken,
+		Message:      "successfully logged in",
+	})
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Errors returned when identity can not be linked to user.
+var (
+	errAccountExists             = errors.New("account with this email address already exists, login and link provider to account")
+	errProviderLinkedToOtherUser = errors.New("provider account is linked to other user")
+	errProviderAlreadyLinked     = errors.New("other account of provider is already linked")
+	errLastProvider              = errors.New("last auth provider can not be unlinked")
+)
+
+// registerIdentity is used to attach identity which is not linked yet to user with same email or to new user.
+// Identity is merged with existing user only if provider verified email and auto linking is enabled,
+// otherwise new user is registered with just linked identity and email is kept only if it is verified.
+func (a *Auth) registerIdentity(db *gorm.DB, name string, identity *Identity) (*models.User, error) {
+	var user models.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// empty email would match any user
+		err := gorm.ErrRecordNotFound
+		if identity.Email != "" {
+			err = tx.Model(&models.User{}).
+				Where(&models.User{Email: identity.Email}).
+				First(&user).
+				Error
+		}
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err == nil && (!identity.EmailVerified || !a.Config.AuthAutoLink) {
+			return errAccountExists
+		}
+
+		// password of local account with unverified email may be set by someone else, drop it before merging
+		if err == nil {
+			err = tx.Unscoped().
+				Where("user_id = ? AND auth_provider_type = ? AND verified_at IS NULL", user.ID, models.AuthProviderTypeLocal).
+				Delete(&models.AuthProvider{}).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// if user not found create new user record, unverified email could be used to merge other identities later
+		if err == gorm.ErrRecordNotFound {
+			user = models.User{
+				Username:  identity.Username,
+				AvatarURL: identity.AvatarURL,
+				UserRole:  models.UserRoleUser,
+			}
+			if identity.EmailVerified {
+				user.Email = identity.Email
+			}
+			err = tx.Create(&user).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// token is encrypted by serializer of model
+		return tx.Create(&models.AuthProvider{
+			UserID:           user.ID,
+			ProviderUID:      identity.Subject,
+			AuthProviderType: models.AuthProviderType(name),
+			RefreshToken:     identity.Token,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// linkIdentity is used to attach identity to user, identity linked to other user
+// or second account of same provider are rejected.
+func (a *Auth) linkIdentity(db *gorm.DB, name string, userID uuid.UUID, identity *Identity) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var authProvider models.AuthProvider
+		err := tx.Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{
+				ProviderUID:      identity.Subject,
+				AuthProviderType: models.AuthProviderType(name),
+			}).
+			First(&authProvider).
+			Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		// if identity is already linked refresh stored token
+		if err == nil {
+			if authProvider.UserID != userID {
+				return errProviderLinkedToOtherUser
+			}
+			if identity.Token == "" {
+				return nil
+			}
+			return tx.Model(&authProvider).
+				Updates(&models.AuthProvider{RefreshToken: identity.Token}).
+				Error
+		}
+
+		var count int64
+		err = tx.Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{UserID: userID, AuthProviderType: models.AuthProviderType(name)}).
+			Count(&count).
+			Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errProviderAlreadyLinked
+		}
+
+		// token is encrypted by serializer of model
+		return tx.Create(&models.AuthProvider{
+			UserID:           userID,
+			ProviderUID:      identity.Subject,
+			AuthProviderType: models.AuthProviderType(name),
+			RefreshToken:     identity.Token,
+		}).Error
+	})
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/oauthstate"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// Represent output data of LinkProviderHandler
+type LinkProviderHandlerResponseBody struct {
+	URL     string `json:"url" xml:"url"`
+	Message string `json:"message" xml:"message"`
+} // @name LinkProviderResponse
+
+// LinkProviderHandler godoc
+//
+// @id				LinkProvider
+// @Summary 		Starts linking of identity provider to current user.
+// @Description 	Responds with provider url, once user grants access callback links provider account to current user.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param provider path string true "Name of identity provider"
+// @Param redirect query string false "Post link redirect url, relative or on base url host"
+//
+// @Success 200 	{object} LinkProviderHandlerResponseBody
+// @Failure 400,401,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/{provider}/link [POST]
+func (a *Auth) LinkProviderHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("LinkProviderHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get provider from path
+	name := c.Param("provider")
+	logger = logger.With("provider", name)
+	provider, ok := a.Providers[name]
+	if !ok {
+		logger.Errorw("unknown provider")
+		return problem.NotFound("unknown provider")
+	}
+
+	// get authorization grant bound to current user
+	url, err := a.authCodeURL(c, name, provider, token.UserID.String())
+	if err != nil {
+		if err == errInvalidRedirect {
+			logger.Errorw("invalid redirect url", "redirect", c.QueryParam("redirect"))
+			return problem.BadRequest(err.Error())
+		}
+		logger.Errorw("failed to create redirect url", "err", err)
+		return problem.Internal("failed to link provider")
+	}
+
+	logger.Infow("successfully created link url")
+	return a.ResponseWriter(c, http.StatusOK, LinkProviderHandlerResponseBody{
+		URL:     url,
+		Message: "successfully created link url",
+	})
+}
+
+// linkCallback is used to complete callback of flow started by LinkProviderHandler.
+func (a *Auth) linkCallback(c echo.Context, logger *zap.SugaredLogger, name string, state *oauthstate.State, identity *Identity) error {
+	userID, err := uuid.Parse(state.UserID)
+	if err != nil {
+		logger.Errorw("invalid user id in state", "err", err)
+		return problem.Unauthorized("invalid auth state")
+	}
+	logger = logger.With("userId", userID)
+
+	// check user still exists
+	logger.Infow("getting user from database")
+	_, err = a.Users.Get(c.Request().Context(), userID)
+	if err == repository.ErrNotFound {
+		logger.Errorw("user not found")
+		return problem.Unauthorized("user not found")
+	}
+	if err != nil {
+		logger.Errorw("failed to find user in database", "err", err)
+		return problem.Internal("failed to link provider")
+	}
+
+	// link identity to user
+	logger.Infow("linking provider")
+	err = a.linkIdentity(a.MySQL.WithContext(c.Request().Context()), name, userID, identity)
+	if errors.Is(err, errProviderLinkedToOtherUser) || errors.Is(err, errProviderAlreadyLinked) {
+		logger.Errorw("failed to link provider", "err", err)
+		return problem.Conflict(err.Error())
+	}
+	if err != nil {
+		logger.Errorw("failed to link provider", "err", err)
+		return problem.Internal("failed to link provider")
+	}
+
+	// redirect to post link url if requested
+	if state.Redirect != "" {
+		logger.Infow("successfully linked provider, redirecting", "redirect", state.Redirect)
+		return c.Redirect(http.StatusSeeOther, state.Redirect)
+	}
+
+	logger.Infow("successfully linked provider")
+	return a.ResponseWriter(c, http.StatusOK, CallbackHandlerResponseBody{
+		Message: "successfully linked provider",
+	})
+}
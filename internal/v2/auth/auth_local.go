@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/mailer"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/password"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Errors returned by local auth.
+var (
+	errInvalidCredentials = errors.New("invalid email or password")
+	errInvalidToken       = errors.New("invalid or expired token")
+)
+
+// normalizeEmail is used to compare emails case insensitively, local provider uid is normalized email.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// dummyHash is verified when account is not found so response time does not reveal registered emails.
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// verifyPassword is used to check password of local provider, nil provider is always rejected.
+func verifyPassword(authProvider *models.AuthProvider, plain string) (bool, error) {
+	if authProvider == nil || authProvider.PasswordHash == "" {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = password.Hash(uuid.New().String())
+		})
+		_, _ = password.Verify(plain, dummyHash)
+		return false, nil
+	}
+
+	return password.Verify(plain, authProvider.PasswordHash)
+}
+
+// findLocalProvider is used to get local provider by email, returns nil if email is not registered.
+func findLocalProvider(db *gorm.DB, email string) (*models.AuthProvider, error) {
+	var authProvider models.AuthProvider
+	err := db.Model(&models.AuthProvider{}).
+		Where(&models.AuthProvider{
+			ProviderUID:      normalizeEmail(email),
+			AuthProviderType: models.AuthProviderTypeLocal,
+		}).
+		First(&authProvider).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &authProvider, nil
+}
+
+// issueVerificationToken is used to store hash of new single use token of user, returns token.
+func issueVerificationToken(db *gorm.DB, userID uuid.UUID, purpose models.VerificationPurpose, ttl time.Duration) (string, error) {
+	token, hash, err := access.NewRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = db.Create(&models.VerificationToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(ttl),
+	}).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to create verification token: %s", err)
+	}
+
+	return token, nil
+}
+
+// consumeVerificationToken is used to mark token as used, expired, used or unknown tokens are rejected.
+func consumeVerificationToken(db *gorm.DB, purpose models.VerificationPurpose, token string) (*models.VerificationToken, error) {
+	var verification models.VerificationToken
+	err := db.Model(&models.VerificationToken{}).
+		Where(&models.VerificationToken{TokenHash: access.HashRefreshToken(token), Purpose: purpose}).
+		First(&verification).
+		Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if verification.UsedAt != nil || !time.Now().Before(verification.ExpiresAt) {
+		return nil, errInvalidToken
+	}
+
+	// guard against concurrent use of same token
+	res := db.Model(&verification).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, errInvalidToken
+	}
+
+	return &verification, nil
+}
+
+// sendVerificationEmail is used to send email verification token to user.
+func (a *Auth) sendVerificationEmail(ctx context.Context, user *models.User, token string) error {
+	return a.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"use following token to verify your email address:\n\n"+
+			"%s\n\n"+
+			"Token expires in %s. If you did not register, ignore this email.\n",
+			user.Username, token, a.Config.EmailVerificationTTL),
+	})
+}
+
+// sendPasswordResetEmail is used to send password reset token to user.
+func (a *Auth) sendPasswordResetEmail(ctx context.Context, user *models.User, token string) error {
+	return a.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"use following token to reset your password:\n\n"+
+			"%s\n\n"+
+			"Token expires in %s. If you did not request password reset, ignore this email.\n",
+			user.Username, token, a.Config.PasswordResetTTL),
+	})
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Tamplier2911/gorest/pkg/password"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/throttle"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input data of LocalLoginHandler
+type LocalLoginHandlerRequestBody struct {
+	Email    string `json:"email" form:"email" binding:"required" validate:"required,email"`
+	Password string `json:"password" form:"password" binding:"required" validate:"required,max=256"`
+} // @name LocalLoginRequest
+
+// Represent output data of LocalLoginHandler
+type LocalLoginHandlerResponseBody struct {
+	Token        *string `json:"token" xml:"token"`
+	RefreshToken *string `json:"refreshToken" xml:"refreshToken"`
+	Message      string  `json:"message" xml:"message"`
+} // @name LocalLoginResponse
+
+// LocalLoginHandler godoc
+//
+// @id				LocalLogin
+// @Summary 		Logins user with email and password.
+// @Description 	Verifies password of local account with verified email, signs JWT and responds with session tokens.
+// @Description 	Email and ip address are blocked for a while after too many failed attempts.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body LocalLoginHandlerRequestBody true "data"
+//
+// @Success 200 			{object} LocalLoginHandlerResponseBody
+// @Failure 400,401,403,429 {object} problem.Problem
+// @Failure 500 			{object} problem.Problem
+// @Failure default 		{object} problem.Problem
+//
+// @Router /auth/local/login [POST]
+func (a *Auth) LocalLoginHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("LocalLoginHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body LocalLoginHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+	email := normalizeEmail(body.Email)
+	ip := c.RealIP()
+	logger = logger.With("email", email, "ip", ip)
+
+	// reject attempts of blocked email or ip address
+	for _, check := range []struct {
+		throttle *throttle.Throttle
+		key      string
+	}{
+		{a.EmailThrottle, email},
+		{a.IPThrottle, ip},
+	} {
+		ok, retryAfter := check.throttle.Allow(check.key)
+		if !ok {
+			logger.Errorw("too many failed login attempts", "key", check.key, "retryAfter", retryAfter)
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return problem.TooManyRequests("too many failed login attempts, try again later")
+		}
+	}
+
+	// get local provider and verify password
+	logger.Infow("getting local auth provider from database")
+	authProvider, err := findLocalProvider(a.MySQL.WithContext(c.Request().Context()), email)
+	if err != nil {
+		logger.Errorw("failed to find local auth provider in database", "err", err)
+		return problem.Internal("failed to login user")
+	}
+
+	logger.Infow("verifying password")
+	ok, err := verifyPassword(authProvider, body.Password)
+	if err != nil {
+		logger.Errorw("failed to verify password", "err", err)
+		return problem.Internal("failed to login user")
+	}
+	if !ok {
+		logger.Errorw("invalid credentials")
+		a.EmailThrottle.Fail(email)
+		a.IPThrottle.Fail(ip)
+		return problem.Unauthorized(errInvalidCredentials.Error())
+	}
+	a.EmailThrottle.Reset(email)
+
+	// password is correct so revealing unverified email does not leak account existence
+	if authProvider.VerifiedAt == nil {
+		logger.Errorw("email is not verified")
+		return problem.Forbidden(errEmailNotVerified.Error())
+	}
+
+	// get user from database
+	logger.Infow("getting user from database")
+	user, err := a.Users.Get(c.Request().Context(), authProvider.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("user of local auth provider was deleted", "err", err)
+			return problem.Unauthorized(errInvalidCredentials.Error())
+		}
+		logger.Errorw("failed to find user in database", "err", err)
+		return problem.Internal("failed to login user")
+	}
+	logger = logger.With("user", user)
+
+	// upgrade hash created with outdated params, failure does not prevent login
+	if password.NeedsRehash(authProvider.PasswordHash) {
+		logger.Infow("rehashing password")
+		hash, err := password.Hash(body.Password)
+		if err == nil {
+			authProvider.PasswordHash = hash
+			err = a.AuthProviders.UpdatePassword(c.Request().Context(), authProvider)
+		}
+		if err != nil {
+			logger.Warnw("failed to rehash password", "err", err)
+		}
+	}
+
+	// start new session
+	logger.Infow("issuing session tokens")
+	accessToken, refreshToken, err := a.issueTokens(c, a.MySQL.WithContext(c.Request().Context()), user, uuid.Nil)
+	if err != nil {
+		logger.Errorw("failed to issue session tokens", "err", err)
+		return problem.Internal("failed to login user")
+	}
+
+	logger.Infow("successfully logged in")
+	return a.ResponseWriter(c, http.StatusOK, LocalLoginHandlerResponseBody{
+		Token:        &accessToken,
+		RefreshToken: &refreshToken,
+		Message:      "successfully logged in",
+	})
+}
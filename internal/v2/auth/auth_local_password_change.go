@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/password"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input data of ChangePasswordHandler
+type ChangePasswordHandlerRequestBody struct {
+	CurrentPassword string `json:"currentPassword" form:"currentPassword" validate:"max=256"`
+	NewPassword     string `json:"newPassword" form:"newPassword" binding:"required" validate:"required,min=8,max=256"`
+} // @name ChangePasswordRequest
+
+// Represent output data of ChangePasswordHandler
+type ChangePasswordHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name ChangePasswordResponse
+
+// ChangePasswordHandler godoc
+//
+// @id				ChangePassword
+// @Summary 		Changes password of current user.
+// @Description 	Changes password of local account, current password is required.
+// @Description 	User which logged in with provider only sets password without current one and can login with email afterwards.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body ChangePasswordHandlerRequestBody true "data"
+//
+// @Success 200 			{object} ChangePasswordHandlerResponseBody
+// @Failure 400,401,403,404 {object} problem.Problem
+// @Failure 500 			{object} problem.Problem
+// @Failure default 		{object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/local/password [PUT]
+func (a *Auth) ChangePasswordHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("ChangePasswordHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body ChangePasswordHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// get user from database
+	logger.Infow("getting user from database")
+	user, err := a.Users.Get(c.Request().Context(), token.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user with id from token in database", "err", err)
+			return problem.NotFound("failed to find user")
+		}
+		logger.Errorw("failed to find user in database", "err", err)
+		return problem.Internal("failed to change password")
+	}
+
+	// get local provider of user
+	logger.Infow("getting local auth provider from database")
+	authProvider, err := a.AuthProviders.FindByUser(c.Request().Context(), user.ID, models.AuthProviderTypeLocal)
+	if err != nil && err != repository.ErrNotFound {
+		logger.Errorw("failed to find local auth provider in database", "err", err)
+		return problem.Internal("failed to change password")
+	}
+	found := err == nil
+
+	// current password is required once password is set
+	if found {
+		logger.Infow("verifying current password")
+		ok, err := verifyPassword(authProvider, body.CurrentPassword)
+		if err != nil {
+			logger.Errorw("failed to verify password", "err", err)
+			return problem.Internal("failed to change password")
+		}
+		if !ok {
+			logger.Errorw("invalid current password")
+			return problem.Forbidden("invalid current password")
+		}
+	}
+
+	// hash password
+	logger.Infow("hashing password")
+	hash, err := password.Hash(body.NewPassword)
+	if err != nil {
+		logger.Errorw("failed to hash password", "err", err)
+		return problem.Internal("failed to change password")
+	}
+
+	// update password or add local provider, email of user was verified by provider on registration
+	if found {
+		logger.Infow("updating password in database")
+		authProvider.PasswordHash = hash
+		err = a.AuthProviders.UpdatePassword(c.Request().Context(), authProvider)
+	} else {
+		logger.Infow("creating local auth provider in database")
+		now := time.Now()
+		err = a.AuthProviders.Create(c.Request().Context(), &models.AuthProvider{
+			UserID:           user.ID,
+			ProviderUID:      normalizeEmail(user.Email),
+			AuthProviderType: models.AuthProviderTypeLocal,
+			PasswordHash:     hash,
+			VerifiedAt:       &now,
+		})
+	}
+	if err != nil {
+		logger.Errorw("failed to save password in database", "err", err)
+		return problem.Internal("failed to change password")
+	}
+
+	logger.Infow("successfully changed password")
+	return a.ResponseWriter(c, http.StatusOK, ChangePasswordHandlerResponseBody{
+		Message: "successfully changed password",
+	})
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/password"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent input data of ResetPasswordHandler
+type ResetPasswordHandlerRequestBody struct {
+	Token    string `json:"token" form:"token" binding:"required" validate:"required"`
+	Password string `json:"password" form:"password" binding:"required" validate:"required,min=8,max=256"`
+} // @name ResetPasswordRequest
+
+// Represent output data of ResetPasswordHandler
+type ResetPasswordHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name ResetPasswordResponse
+
+// ResetPasswordHandler godoc
+//
+// @id				ResetPassword
+// @Summary 		Resets password of local account.
+// @Description 	Consumes token sent by email and sets new password, email is considered verified
+// @Description 	and every session of user is logged out.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body ResetPasswordHandlerRequestBody true "data"
+//
+// @Success 200 	{object} ResetPasswordHandlerResponseBody
+// @Failure 400 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /auth/local/password/reset [POST]
+func (a *Auth) ResetPasswordHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("ResetPasswordHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body ResetPasswordHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// hash password
+	logger.Infow("hashing password")
+	hash, err := password.Hash(body.Password)
+	if err != nil {
+		logger.Errorw("failed to hash password", "err", err)
+		return problem.Internal("failed to reset password")
+	}
+
+	// consume token, set password and logout all sessions
+	logger.Infow("resetting password")
+	now := time.Now()
+	var userID uuid.UUID
+	var email string
+	var familyIDs []uuid.UUID
+	err = a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		verification, err := consumeVerificationToken(tx, models.VerificationPurposePasswordReset, body.Token)
+		if err != nil {
+			return err
+		}
+		userID = verification.UserID
+
+		var authProvider models.AuthProvider
+		err = tx.Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{UserID: userID, AuthProviderType: models.AuthProviderTypeLocal}).
+			First(&authProvider).
+			Error
+		if err == gorm.ErrRecordNotFound {
+			return errInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		email = authProvider.ProviderUID
+
+		// following emailed token proves ownership of email
+		updates := map[string]interface{}{"password_hash": hash}
+		if authProvider.VerifiedAt == nil {
+			updates["verified_at"] = now
+		}
+		err = tx.Model(&authProvider).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+
+		familyIDs, err = revokeUserSessions(tx, userID, now)
+		return err
+	})
+	if errors.Is(err, errInvalidToken) {
+		logger.Errorw("invalid password reset token")
+		return problem.BadRequest(err.Error())
+	}
+	if err != nil {
+		logger.Errorw("failed to reset password", "err", err)
+		return problem.Internal("failed to reset password")
+	}
+	logger = logger.With("userId", userID)
+
+	// reject access tokens issued so far and unblock login
+	logger.Infow("revoking user access tokens")
+	err = a.revokeAccessTokens(userID, familyIDs, now)
+	if err != nil {
+		logger.Errorw("failed to revoke user access tokens", "err", err)
+		return problem.Internal("failed to reset password")
+	}
+	a.EmailThrottle.Reset(email)
+
+	logger.Infow("successfully reset password")
+	return a.ResponseWriter(c, http.StatusOK, ResetPasswordHandlerResponseBody{
+		Message: "successfully reset password",
+	})
+}
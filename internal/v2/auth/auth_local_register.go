@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/password"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent input data of RegisterHandler
+type RegisterHandlerRequestBody struct {
+	Email    string `json:"email" form:"email" binding:"required" validate:"required,email,max=255"`
+	Username string `json:"username" form:"username" binding:"required" validate:"required,min=1,max=255"`
+	Password string `json:"password" form:"password" binding:"required" validate:"required,min=8,max=256"`
+} // @name RegisterRequest
+
+// Represent output data of RegisterHandler
+type RegisterHandlerResponseBody struct {
+	User    *models.User `json:"user,omitempty" xml:"user,omitempty"`
+	Message string       `json:"message" xml:"message"`
+} // @name RegisterResponse
+
+// RegisterHandler godoc
+//
+// @id				Register
+// @Summary 		Registers user with email and password.
+// @Description 	Creates user with local auth provider and sends email verification token,
+// @Description 	user can login once email is verified.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body RegisterHandlerRequestBody true "data"
+//
+// @Success 201 	{object} RegisterHandlerResponseBody
+// @Failure 400,409 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /auth/local/register [POST]
+func (a *Auth) RegisterHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("RegisterHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body RegisterHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+	email := normalizeEmail(body.Email)
+	logger = logger.With("email", email)
+
+	// hash password
+	logger.Infow("hashing password")
+	hash, err := password.Hash(body.Password)
+	if err != nil {
+		logger.Errorw("failed to hash password", "err", err)
+		return problem.Internal("failed to register user")
+	}
+
+	// create user with local provider and send verification email,
+	// user is not created if email can not be sent
+	var user models.User
+	logger.Infow("creating user in database")
+	err = a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&models.User{}).
+			Where(&models.User{Email: email}).
+			Count(&count).
+			Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return errAccountExists
+		}
+
+		user = models.User{
+			Email:    email,
+			Username: body.Username,
+			UserRole: models.UserRoleUser,
+		}
+		err = tx.Create(&user).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(&models.AuthProvider{
+			UserID:           user.ID,
+			ProviderUID:      email,
+			AuthProviderType: models.AuthProviderTypeLocal,
+			PasswordHash:     hash,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		token, err := issueVerificationToken(tx, user.ID, models.VerificationPurposeEmail, a.Config.EmailVerificationTTL)
+		if err != nil {
+			return err
+		}
+
+		return a.sendVerificationEmail(c.Request().Context(), &user, token)
+	})
+	if errors.Is(err, errAccountExists) {
+		logger.Errorw("user with email already exists")
+		return problem.Conflict("account with this email address already exists")
+	}
+	if err != nil {
+		logger.Errorw("failed to register user", "err", err)
+		return problem.Internal("failed to register user")
+	}
+	logger = logger.With("user", user)
+
+	logger.Infow("successfully registered user")
+	return a.ResponseWriter(c, http.StatusCreated, RegisterHandlerResponseBody{
+		User:    &user,
+		Message: "successfully registered user, verification email sent",
+	})
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent input data of VerifyEmailHandler
+type VerifyEmailHandlerRequestBody struct {
+	Token string `json:"token" form:"token" binding:"required" validate:"required"`
+} // @name VerifyEmailRequest
+
+// Represent output data of VerifyEmailHandler
+type VerifyEmailHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name VerifyEmailResponse
+
+// VerifyEmailHandler godoc
+//
+// @id				VerifyEmail
+// @Summary 		Verifies email of local account.
+// @Description 	Consumes token sent by email on registration and allows user to login with password.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body VerifyEmailHandlerRequestBody true "data"
+//
+// @Success 200 	{object} VerifyEmailHandlerResponseBody
+// @Failure 400 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /auth/local/verify [POST]
+func (a *Auth) VerifyEmailHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("VerifyEmailHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body VerifyEmailHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// consume token and mark local provider of user as verified
+	logger.Infow("verifying email")
+	err = a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		verification, err := consumeVerificationToken(tx, models.VerificationPurposeEmail, body.Token)
+		if err != nil {
+			return err
+		}
+		logger = logger.With("userId", verification.UserID)
+
+		return tx.Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{UserID: verification.UserID, AuthProviderType: models.AuthProviderTypeLocal}).
+			Where("verified_at IS NULL").
+			Update("verified_at", time.Now()).
+			Error
+	})
+	if errors.Is(err, errInvalidToken) {
+		logger.Errorw("invalid verification token")
+		return problem.BadRequest(err.Error())
+	}
+	if err != nil {
+		logger.Errorw("failed to verify email", "err", err)
+		return problem.Internal("failed to verify email")
+	}
+
+	logger.Infow("successfully verified email")
+	return a.ResponseWriter(c, http.StatusOK, VerifyEmailHandlerResponseBody{
+		Message: "successfully verified email",
+	})
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent input data of ResendVerificationHandler
+type ResendVerificationHandlerRequestBody struct {
+	Email string `json:"email" form:"email" binding:"required" validate:"required,email"`
+} // @name ResendVerificationRequest
+
+// Represent output data of ResendVerificationHandler
+type ResendVerificationHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name ResendVerificationResponse
+
+// ResendVerificationHandler godoc
+//
+// @id				ResendVerification
+// @Summary 		Resends email verification token.
+// @Description 	Sends new verification token if local account with email is not verified yet,
+// @Description 	response does not reveal if account exists.
+//
+// @Tags			Auth
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body ResendVerificationHandlerRequestBody true "data"
+//
+// @Success 202 	{object} ResendVerificationHandlerResponseBody
+// @Failure 400 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /auth/local/verify/resend [POST]
+func (a *Auth) ResendVerificationHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("ResendVerificationHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body ResendVerificationHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+	logger = logger.With("email", normalizeEmail(body.Email))
+
+	// send new token only to unverified local accounts
+	logger.Infow("getting local auth provider from database")
+	err = a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		authProvider, err := findLocalProvider(tx, body.Email)
+		if err != nil || authProvider == nil || authProvider.VerifiedAt != nil {
+			return err
+		}
+
+		var user models.User
+		err = tx.Model(&models.User{}).
+			Where(&models.User{Base: models.Base{ID: authProvider.UserID}}).
+			First(&user).
+			Error
+		if err != nil {
+			return err
+		}
+
+		token, err := issueVerificationToken(tx, user.ID, models.VerificationPurposeEmail, a.Config.EmailVerificationTTL)
+		if err != nil {
+			return err
+		}
+
+		logger.Infow("sending verification email")
+		return a.sendVerificationEmail(c.Request().Context(), &user, token)
+	})
+	if err != nil {
+		logger.Errorw("failed to resend verification email", "err", err)
+		return problem.Internal("failed to send verification email")
+	}
+
+	return a.ResponseWriter(c, http.StatusAccepted, ResendVerificationHandlerResponseBody{
+		Message: "verification email is sent if account exists and is not verified",
+	})
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// LoginHandler godoc
+//
+// @id				Login
+// @Summary 		Login with identity provider.
+// @Description 	Directs users to provider popup to grant access to user account.
+//
+// @Tags			Auth
+//
+// @Param provider path string true "Name of identity provider, google, facebook, github or configured OpenID Connect provider"
+// @Param redirect query string false "Post login redirect url, relative or on base url host"
+//
+// @Success 307 	{string} Url "url"
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+//
+// @Router /auth/{provider}/login [GET]
+func (a *Auth) LoginHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("LoginHandler"))
+
+	// get provider from path
+	name := c.Param("provider")
+	logger = logger.With("provider", name)
+	provider, ok := a.Providers[name]
+	if !ok {
+		logger.Errorw("unknown provider")
+		return problem.NotFound("unknown provider")
+	}
+
+	// get authorization grant
+	url, err := a.authCodeURL(c, name, provider, "")
+	if err != nil {
+		if err == errInvalidRedirect {
+			logger.Errorw("invalid redirect url", "redirect", c.QueryParam("redirect"))
+			return problem.BadRequest(err.Error())
+		}
+		logger.Errorw("failed to create redirect url", "err", err)
+		return problem.Internal("failed to login user")
+	}
+	logger = logger.With("url", url)
+
+	logger.Infow("successfully created redirect url")
+	return c.Redirect(http.StatusTemporaryRedirect, url)
+}
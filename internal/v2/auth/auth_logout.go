@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of LogoutHandler
+type LogoutHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name LogoutResponse
+
+// LogoutHandler godoc
+//
+// @id				Logout
+// @Summary 		Logs out current session.
+// @Description 	Revokes session family of access token, its refresh tokens and access tokens are no longer accepted.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Success 200 	{object} LogoutHandlerResponseBody
+// @Failure 400,401 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/logout [POST]
+func (a *Auth) LogoutHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("LogoutHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// check if token is bound to session
+	if token.SessionID == uuid.Nil {
+		logger.Errorw("token is not bound to session")
+		return problem.BadRequest("token is not bound to session")
+	}
+
+	// revoke session family
+	logger.Infow("revoking session family")
+	err := a.revokeFamily(a.MySQL.WithContext(c.Request().Context()), token.SessionID)
+	if err != nil {
+		logger.Errorw("failed to revoke session family", "err", err)
+		return problem.Internal("failed to logout")
+	}
+
+	logger.Infow("successfully logged out")
+	return a.ResponseWriter(c, http.StatusOK, LogoutHandlerResponseBody{
+		Message: "successfully logged out",
+	})
+}
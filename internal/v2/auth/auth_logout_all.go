@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of LogoutAllHandler
+type LogoutAllHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name LogoutAllResponse
+
+// LogoutAllHandler godoc
+//
+// @id				LogoutAll
+// @Summary 		Logs out all devices.
+// @Description 	Revokes every session of current user and rejects access tokens issued before request.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Success 200 	{object} LogoutAllHandlerResponseBody
+// @Failure 401 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/logout/all [POST]
+func (a *Auth) LogoutAllHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("LogoutAllHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// revoke all sessions of user
+	logger.Infow("revoking user sessions")
+	now := time.Now()
+	familyIDs, err := revokeUserSessions(a.MySQL.WithContext(c.Request().Context()), token.UserID, now)
+	if err != nil {
+		logger.Errorw("failed to revoke user sessions", "err", err)
+		return problem.Internal("failed to logout")
+	}
+
+	// reject access tokens issued so far
+	logger.Infow("revoking user access tokens")
+	err = a.revokeAccessTokens(token.UserID, familyIDs, now)
+	if err != nil {
+		logger.Errorw("failed to revoke user access tokens", "err", err)
+		return problem.Internal("failed to logout")
+	}
+
+	logger.Infow("successfully logged out all sessions")
+	return a.ResponseWriter(c, http.StatusOK, LogoutAllHandlerResponseBody{
+		Message: "successfully logged out all sessions",
+	})
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"golang.org/x/oauth2"
+)
+
+// errEmailNotVerified is returned when verified email address is required.
+var errEmailNotVerified = errors.New("verified email address is required")
+
+// Identity represent user identity resolved by provider,
+// only emails verified by provider are stored on new user or used to link provider by email.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	AvatarURL     string
+
+	// provider token stored for offline access to provider api
+	Token string
+}
+
+// Provider represent identity provider used for login with authorization code flow.
+type Provider interface {
+	// OAuthConfig is used to get oauth2 config, endpoints of discovered providers are resolved on first use.
+	OAuthConfig(ctx context.Context) (*oauth2.Config, error)
+	// AuthCodeOptions is used to get additional parameters of authorization url.
+	AuthCodeOptions(nonce string) []oauth2.AuthCodeOption
+	// Identity is used to resolve identity of user with exchanged token.
+	Identity(ctx context.Context, token *oauth2.Token, nonce string) (*Identity, error)
+}
+
+// providerName restricts provider names as they are used in routes and cookie names.
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// setupProviders is used to register built in providers and OpenID Connect providers from config.
+func (a *Auth) setupProviders() error {
+	a.Providers = map[string]Provider{
+		"google":   newGoogleProvider(a.Config, a.HTTPClient),
+		"facebook": newFacebookProvider(a.Config, a.HTTPClient),
+		"github":   newGithubProvider(a.Config),
+	}
+
+	for name, config := range a.Config.OIDCProviders {
+		if !providerName.MatchString(name) {
+			return fmt.Errorf("invalid provider name %q", name)
+		}
+		if _, ok := a.Providers[name]; ok {
+			return fmt.Errorf("provider %q is already registered", name)
+		}
+		if name == string(models.AuthProviderTypeLocal) {
+			return fmt.Errorf("provider name %q is reserved for password login", name)
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			return fmt.Errorf("issuer and client id of provider %q are required", name)
+		}
+		a.Providers[name] = newOIDCProvider(name, a.Config.BaseURL, config, a.HTTPClient)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/config"
+	"golang.org/x/net/context/ctxhttp"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/facebook"
+)
+
+// Represents user object returned from facebook resource server
+type FacebookUserData struct {
+	ID    string `json:"id" xml:"id"`
+	Email string `json:"email" xml:"email"`
+	Name  string `json:"name" xml:"name"`
+} // @name FacebookUserData
+
+// facebookProvider resolves identity with facebook graph api.
+type facebookProvider struct {
+	config *oauth2.Config
+	client *http.Client
+}
+
+func newFacebookProvider(cfg *config.Config, client *http.Client) *facebookProvider {
+	return &facebookProvider{
+		config: &oauth2.Config{
+			ClientID:     cfg.FacebookClientID,
+			ClientSecret: cfg.FacebookClientSecret,
+			RedirectURL:  cfg.FacebookRedirectURL,
+			Scopes:       []string{"public_profile", "email"},
+			Endpoint:     facebook.Endpoint,
+		},
+		client: client,
+	}
+}
+
+func (p *facebookProvider) OAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	return p.config, nil
+}
+
+// AuthCodeOptions forces dialog window.
+func (p *facebookProvider) AuthCodeOptions(nonce string) []oauth2.AuthCodeOption {
+	// reauthorize - always has for permissions
+	// rerequest - for declined/revoked permissions
+	// reauthenticate - always as user to confirm password
+	return []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("auth_type", "rerequest")}
+}
+
+func (p *facebookProvider) Identity(ctx context.Context, token *oauth2.Token, nonce string) (*Identity, error) {
+	// access resource server using auth token
+	res, err := ctxhttp.Get(ctx, p.client, "https://graph.facebook.com/me?fields=id,name,email&access_token="+token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d of facebook user endpoint", res.StatusCode)
+	}
+
+	// read resource server response
+	rd, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body: %s", err)
+	}
+
+	// unmarshal facebook user into a struct
+	var fu FacebookUserData
+	err = json.Unmarshal(rd, &fu)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body: %s", err)
+	}
+
+	if fu.ID == "" {
+		return nil, fmt.Errorf("facebook user id is missing in response")
+	}
+
+	// facebook does not guarantee that email is confirmed, so identity is never merged by email
+	// facebook does not issue refresh tokens, long living access token is stored instead
+	return &Identity{
+		Subject:       fu.ID,
+		Email:         fu.Email,
+		EmailVerified: false,
+		Username:      fu.Name,
+		Token:         token.AccessToken,
+	}, nil
+}
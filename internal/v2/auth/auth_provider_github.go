@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/config"
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+	githubendpoint "golang.org/x/oauth2/github"
+)
+
+// githubProvider resolves identity with github users api.
+type githubProvider struct {
+	config *oauth2.Config
+}
+
+func newGithubProvider(cfg *config.Config) *githubProvider {
+	return &githubProvider{
+		config: &oauth2.Config{
+			ClientID:     cfg.GithubClientID,
+			ClientSecret: cfg.GithubClientSecret,
+			RedirectURL:  cfg.GithubRedirectURL,
+			Scopes:       []string{"user:email"},
+			Endpoint:     githubendpoint.Endpoint,
+		},
+	}
+}
+
+func (p *githubProvider) OAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	return p.config, nil
+}
+
+func (p *githubProvider) AuthCodeOptions(nonce string) []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.ApprovalForce}
+}
+
+// Identity expects traced client in context under oauth2.HTTPClient key.
+func (p *githubProvider) Identity(ctx context.Context, token *oauth2.Token, nonce string) (*Identity, error) {
+	// create client request for github user data using authorization token
+	client := github.NewClient(p.config.Client(ctx, token))
+	ghu, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// email is verified only if github lists it as verified primary email of account
+	email, verified := ghu.GetEmail(), false
+	emails, _, err := client.Users.ListEmails(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range emails {
+		if e.GetPrimary() && e.GetVerified() {
+			email, verified = e.GetEmail(), true
+			break
+		}
+	}
+
+	return &Identity{
+		Subject:       fmt.Sprintf("%d", ghu.GetID()),
+		Email:         email,
+		EmailVerified: verified,
+		Username:      ghu.GetName(),
+		AvatarURL:     ghu.GetAvatarURL(),
+		Token:         token.AccessToken,
+	}, nil
+}
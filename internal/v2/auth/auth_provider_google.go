@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/config"
+	"golang.org/x/net/context/ctxhttp"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// Represents user object returned from google resource server
+type GoogleUserData struct {
+	ID            string `json:"id" xml:"id"`
+	Email         string `json:"email" xml:"email"`
+	EmailVerified bool   `json:"verified_email" xml:"verified_email"`
+	Picture       string `json:"picture" xml:"picture"`
+} // @name GoogleUserData
+
+// googleProvider resolves identity with google userinfo api.
+type googleProvider struct {
+	config *oauth2.Config
+	client *http.Client
+}
+
+func newGoogleProvider(cfg *config.Config, client *http.Client) *googleProvider {
+	return &googleProvider{
+		config: &oauth2.Config{
+			RedirectURL:  cfg.GoogleRedirectURL,
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email"},
+			Endpoint:     google.Endpoint,
+		},
+		client: client,
+	}
+}
+
+func (p *googleProvider) OAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	return p.config, nil
+}
+
+// AuthCodeOptions requests refresh token on every login.
+func (p *googleProvider) AuthCodeOptions(nonce string) []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.ApprovalForce}
+}
+
+func (p *googleProvider) Identity(ctx context.Context, token *oauth2.Token, nonce string) (*Identity, error) {
+	// access resource server using auth token
+	res, err := ctxhttp.Get(ctx, p.client, "https://www.googleapis.com/oauth2/v2/userinfo?access_token="+token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d of google user endpoint", res.StatusCode)
+	}
+
+	// read resource server response
+	rd, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body: %s", err)
+	}
+
+	// unmarshal google user into a struct
+	var gu GoogleUserData
+	err = json.Unmarshal(rd, &gu)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response body: %s", err)
+	}
+
+	if gu.ID == "" {
+		return nil, fmt.Errorf("google user id is missing in response")
+	}
+
+	// save refresh token in order if we want to request resource api when user is offline
+	return &Identity{
+		Subject:       gu.ID,
+		Email:         gu.Email,
+		EmailVerified: gu.EmailVerified,
+		AvatarURL:     gu.Picture,
+		Token:         token.RefreshToken,
+	}, nil
+}
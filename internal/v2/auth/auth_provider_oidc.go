@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Tamplier2911/gorest/pkg/config"
+	"github.com/Tamplier2911/gorest/pkg/oidc"
+	"golang.org/x/oauth2"
+)
+
+// oidcProvider resolves identity from verified ID token of OpenID Connect provider,
+// userinfo endpoint is requested only if ID token does not carry email.
+type oidcProvider struct {
+	provider *oidc.Provider
+	config   oauth2.Config
+}
+
+func newOIDCProvider(name string, baseURL string, cfg config.OIDCProvider, client *http.Client) *oidcProvider {
+	redirectURL := cfg.RedirectURL
+	if redirectURL == "" {
+		redirectURL = fmt.Sprintf("%s/api/v2/auth/%s/callback", strings.TrimSuffix(baseURL, "/"), name)
+	}
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &oidcProvider{
+		provider: oidc.New(cfg.Issuer, client),
+		config: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       scopes,
+		},
+	}
+}
+
+// OAuthConfig resolves endpoints with discovery.
+func (p *oidcProvider) OAuthConfig(ctx context.Context) (*oauth2.Config, error) {
+	endpoint, err := p.provider.Endpoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := p.config
+	config.Endpoint = endpoint
+	return &config, nil
+}
+
+// AuthCodeOptions binds ID token to login with nonce.
+func (p *oidcProvider) AuthCodeOptions(nonce string) []oauth2.AuthCodeOption {
+	return []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("nonce", nonce)}
+}
+
+func (p *oidcProvider) Identity(ctx context.Context, token *oauth2.Token, nonce string) (*Identity, error) {
+	raw, ok := token.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("id token is missing in token response")
+	}
+
+	claims, err := p.provider.Verify(ctx, raw, p.config.ClientID, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// complete claims from userinfo endpoint
+	if claims.Email == "" {
+		info, err := p.provider.UserInfo(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if info.Subject != claims.Subject {
+			return nil, fmt.Errorf("userinfo subject does not match id token")
+		}
+		claims.Email, claims.EmailVerified = info.Email, info.EmailVerified
+		if claims.Name == "" {
+			claims.Name = info.Name
+		}
+		if claims.PreferredUsername == "" {
+			claims.PreferredUsername = info.PreferredUsername
+		}
+		if claims.Picture == "" {
+			claims.Picture = info.Picture
+		}
+	}
+
+	username := claims.PreferredUsername
+	if username == "" {
+		username = claims.Name
+	}
+	stored := token.RefreshToken
+	if stored == "" {
+		stored = token.AccessToken
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified != nil && *claims.EmailVerified,
+		Username:      username,
+		AvatarURL:     claims.Picture,
+		Token:         stored,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent provider account linked to user
+type LinkedProvider struct {
+	Provider    string    `json:"provider" xml:"provider"`
+	ProviderUID string    `json:"providerUid" xml:"providerUid"`
+	LinkedAt    time.Time `json:"linkedAt" xml:"linkedAt"`
+} // @name LinkedProvider
+
+// Represent output data of GetLinkedProvidersHandler
+type GetLinkedProvidersHandlerResponseBody struct {
+	Providers *[]LinkedProvider `json:"providers" xml:"providers"`
+	Message   string            `json:"message" xml:"message"`
+} // @name GetLinkedProvidersResponse
+
+// GetLinkedProvidersHandler godoc
+//
+// @id				GetLinkedProviders
+// @Summary 		Lists identity providers linked to current user.
+// @Description 	Responds with provider accounts linked to current user in order of linking.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
+//
+// @Success 200 	{object} GetLinkedProvidersHandlerResponseBody
+// @Failure 401 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/providers [GET]
+func (a *Auth) GetLinkedProvidersHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("GetLinkedProvidersHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get linked providers from database
+	logger.Infow("getting auth providers from database")
+	authProviders, err := a.AuthProviders.ListByUser(c.Request().Context(), token.UserID)
+	if err != nil {
+		logger.Errorw("failed to get auth providers from database", "err", err)
+		return problem.Internal("failed to get linked providers")
+	}
+
+	// stored provider tokens are never exposed
+	providers := make([]LinkedProvider, 0, len(authProviders))
+	for _, authProvider := range authProviders {
+		providers = append(providers, LinkedProvider{
+			Provider:    string(authProvider.AuthProviderType),
+			ProviderUID: authProvider.ProviderUID,
+			LinkedAt:    authProvider.CreatedAt,
+		})
+	}
+
+	logger.Infow("successfully got linked providers")
+	return a.ResponseWriter(c, http.StatusOK, GetLinkedProvidersHandlerResponseBody{
+		Providers: &providers,
+		Message:   "successfully got linked providers",
+	})
+}
@@ -1,98 +1,169 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
-	"github.com/golang-jwt/jwt"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// errRefreshTokenReused is returned when session was rotated by concurrent request.
+var errRefreshTokenReused = errors.New("refresh token is already rotated")
+
+// Represent input data of RefreshTokenHandler
+type RefreshTokenHandlerRequestBody struct {
+	RefreshToken string `json:"refreshToken" form:"refreshToken" validate:"required,max=255"`
+} // @name RefreshTokenRequest
+
 // Represent output data of RefreshTokenHandler
 type RefreshTokenHandlerHandlerResponseBody struct {
-	Token   *string `json:"token" xml:"token"`
-	Message string  `json:"message" xml:"message"`
+	Token        *string `json:"token" xml:"token"`
+	RefreshToken *string `json:"refreshToken" xml:"refreshToken"`
+	Message      string  `json:"message" xml:"message"`
 } // @name RefreshTokenResponse
 
 // RefreshTokenHandler godoc
 //
 // @id				RefreshToken
-// @Summary 		Refreshes token.
-// @Description 	Validate user token and produce token with prolonged expire data.
+// @Summary 		Rotates refresh token.
+// @Description 	Exchanges refresh token for new access token and refresh token, reuse of rotated refresh token revokes whole session family.
 //
 // @Tags			Auth
 //
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
-// @Success 201 		{object} RefreshTokenHandlerHandlerResponseBody
-// @Failure 400,403,404 {object} RefreshTokenHandlerHandlerResponseBody
-// @Failure 500 		{object} RefreshTokenHandlerHandlerResponseBody
-// @Failure default 	{object} RefreshTokenHandlerHandlerResponseBody
+// @Param fields body RefreshTokenHandlerRequestBody true "data"
 //
-// @Security ApiKeyAuth
+// @Success 201 		{object} RefreshTokenHandlerHandlerResponseBody
+// @Failure 400,401 	{object} problem.Problem
+// @Failure 500 		{object} problem.Problem
+// @Failure default 	{object} problem.Problem
 //
 // @Router /auth/refresh [POST]
 func (a *Auth) RefreshTokenHandler(c echo.Context) error {
-	logger := a.Logger.Named("RefreshTokenHandler")
-
-	// get token from context
-	token := access.GetTokenFromContext(c)
-	logger = logger.With("token", token)
-
-	// get user from database
-	logger.Info("getting user from database")
-	var user models.User
-	err := a.MySQL.
-		Model(&models.User{}).
-		Where(&models.User{Base: models.Base{ID: token.UserID}}).
-		First(&user).
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("RefreshTokenHandler"))
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body RefreshTokenHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = a.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// get session from database
+	logger.Infow("getting session from database")
+	var session models.Session
+	err = a.MySQL.WithContext(c.Request().Context()).
+		Model(&models.Session{}).
+		Where(&models.Session{TokenHash: access.HashRefreshToken(body.RefreshToken)}).
+		First(&session).
 		Error
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			logger.Error("failed to find user with id from token", "tokenUserID", token.UserID)
-			return a.ResponseWriter(c, http.StatusNotFound, RefreshTokenHandlerHandlerResponseBody{
-				Message: "failed to find user",
-			})
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorw("failed to find session with provided refresh token")
+			return problem.Unauthorized("invalid refresh token")
+		}
+		logger.Errorw("failed to find session in database", "err", err)
+		return problem.Internal("failed to refresh token")
+	}
+	logger = logger.With("sessionID", session.ID, "familyID", session.FamilyID)
+
+	// check session state
+	logger.Infow("checking session state")
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		logger.Errorw("session is revoked or expired", "revokedAt", session.RevokedAt, "expiresAt", session.ExpiresAt)
+		return problem.Unauthorized("invalid refresh token")
+	}
+	if session.RotatedAt != nil {
+		return a.refreshTokenReused(c, logger, session)
+	}
+
+	// get user from database, role may have changed since login
+	logger.Infow("getting user from database")
+	user, err := a.Users.Get(c.Request().Context(), session.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user of session", "userID", session.UserID)
+			return problem.Unauthorized("invalid refresh token")
 		}
-		logger.Error("failed to find user in database", "tokenUserID", token.UserID)
-		return a.ResponseWriter(c, http.StatusInternalServerError, RefreshTokenHandlerHandlerResponseBody{
-			Message: "failed to refresh token",
-		})
+		logger.Errorw("failed to find user in database", "err", err)
+		return problem.Internal("failed to refresh token")
 	}
 	logger = logger.With("user", user)
 
-	// refresh user token
-	logger.Info("refreshing token")
-	refreshedToken, err := access.EncodeToken(&access.Token{
-		UserID:   user.ID,
-		UserRole: user.UserRole,
-		StandardClaims: jwt.StandardClaims{
-			Issuer:    "gorest-api",
-			IssuedAt:  time.Now().Unix(),
-			NotBefore: time.Now().Unix(),
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 14).Unix(),
-		},
-	}, a.Config.HMACSecret)
+	// rotate session, only one of concurrent requests may succeed
+	logger.Infow("rotating session")
+	var accessToken, refreshToken string
+	err = a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		rotated := tx.
+			Model(&models.Session{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", session.ID).
+			Update("rotated_at", time.Now())
+		if rotated.Error != nil {
+			return rotated.Error
+		}
+		if rotated.RowsAffected == 0 {
+			return errRefreshTokenReused
+		}
+
+		var err error
+		accessToken, refreshToken, err = a.issueTokens(c, tx, user, session.FamilyID)
+		return err
+	})
 	if err != nil {
-		logger.Errorw("failed to sign jwt token", "err", err)
-		return a.ResponseWriter(c, http.StatusInternalServerError, GoogleCallbackHandlerResponseBody{
-			Message: "failed to login user",
-		})
+		if err == errRefreshTokenReused {
+			return a.refreshTokenReused(c, logger, session)
+		}
+		logger.Errorw("failed to rotate session", "err", err)
+		return problem.Internal("failed to refresh token")
 	}
-	logger = logger.With("refreshedToken", refreshedToken)
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := RefreshTokenHandlerHandlerResponseBody{
-		Token:   &refreshedToken,
-		Message: "successfully refreshed token",
+		Token:        &accessToken,
+		RefreshToken: &refreshToken,
+		Message:      "successfully refreshed token",
 	}
-	logger = logger.With("res", res)
 
 	logger.Infow("successfully refreshed token")
 	return a.ResponseWriter(c, http.StatusCreated, res)
 }
+
+// refreshTokenReused is used to revoke session family once rotated refresh token is presented again,
+// either legitimate client or attacker holds stolen token so both must login again.
+func (a *Auth) refreshTokenReused(c echo.Context, logger *zap.SugaredLogger, session models.Session) error {
+	logger.Warnw("refresh token reuse detected, revoking session family")
+	err := a.revokeFamily(a.MySQL.WithContext(c.Request().Context()), session.FamilyID)
+	if err != nil {
+		logger.Errorw("failed to revoke session family", "err", err)
+		return problem.Internal("failed to refresh token")
+	}
+
+	return problem.Unauthorized("refresh token reuse detected")
+}
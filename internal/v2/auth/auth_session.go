@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// issueTokens is used to store new session of family and sign access token bound to it,
+// nil family id starts new family, returns access token and refresh token.
+func (a *Auth) issueTokens(c echo.Context, db *gorm.DB, user *models.User, familyID uuid.UUID) (string, string, error) {
+	now := time.Now()
+
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+
+	// generate opaque refresh token, only its hash is stored
+	refreshToken, hash, err := access.NewRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	session := models.Session{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		UserAgent: c.Request().UserAgent(),
+		IP:        c.RealIP(),
+		ExpiresAt: now.Add(a.Config.RefreshTokenTTL),
+	}
+	err = db.Create(&session).Error
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create session: %s", err)
+	}
+
+	// sign short lived access token
+	accessToken, err := a.Tokens.Encode(&access.Token{
+		UserID:    user.ID,
+		UserRole:  user.UserRole,
+		SessionID: familyID,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    "gorest-api",
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(a.Config.AccessTokenTTL).Unix(),
+		},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to sign jwt token: %s", err)
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// revokeFamily is used to revoke all sessions of family and reject its access tokens until they expire.
+func (a *Auth) revokeFamily(db *gorm.DB, familyID uuid.UUID) error {
+	now := time.Now()
+
+	err := db.
+		Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).
+		Error
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %s", err)
+	}
+
+	return a.Revocations.RevokeSession(familyID, now.Add(a.Config.AccessTokenTTL))
+}
+
+// revokeUserSessions is used to revoke all active sessions of user, returns families of revoked sessions
+// so their access tokens can be rejected with revokeAccessTokens once transaction commits.
+func revokeUserSessions(db *gorm.DB, userID uuid.UUID, now time.Time) ([]uuid.UUID, error) {
+	var familyIDs []uuid.UUID
+	err := db.
+		Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Distinct().
+		Pluck("family_id", &familyIDs).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %s", err)
+	}
+
+	err = db.
+		Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).
+		Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %s", err)
+	}
+
+	return familyIDs, nil
+}
+
+// revokeAccessTokens is used to reject access tokens of user issued before now until they expire,
+// tokens of revoked families are rejected as well since issue time of token has only second precision.
+func (a *Auth) revokeAccessTokens(userID uuid.UUID, familyIDs []uuid.UUID, now time.Time) error {
+	until := now.Add(a.Config.AccessTokenTTL)
+	for _, familyID := range familyIDs {
+		err := a.Revocations.RevokeSession(familyID, until)
+		if err != nil {
+			return err
+		}
+	}
+
+	return a.Revocations.RevokeUser(userID, now, until)
+}
@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/oauthstate"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/oauth2"
+)
+
+// errInvalidRedirect is returned when post login redirect is not allowed.
+var errInvalidRedirect = errors.New("invalid redirect url")
+
+// stateCookieName is used to name cookie binding state of provider to browser.
+func stateCookieName(provider string) string {
+	return "gorest_oauth_" + provider
+}
+
+// stateCookie is used to create cookie carrying state nonce and pkce verifier,
+// empty value with negative max age removes cookie.
+func (a *Auth) stateCookie(provider string, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     stateCookieName(provider),
+		Value:    value,
+		Path:     "/api/v2/auth/" + provider,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(a.Config.BaseURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// checkRedirect is used to allow only relative redirects or redirects to base url host.
+func (a *Auth) checkRedirect(redirect string) error {
+	if redirect == "" {
+		return nil
+	}
+
+	u, err := url.Parse(redirect)
+	if err != nil {
+		return errInvalidRedirect
+	}
+
+	// relative path, protocol relative urls are rejected
+	if u.Scheme == "" && u.Host == "" {
+		if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+			return errInvalidRedirect
+		}
+		return nil
+	}
+
+	base, err := url.Parse(a.Config.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() != base.Hostname() {
+		return errInvalidRedirect
+	}
+
+	return nil
+}
+
+// authCodeURL is used to start login with provider, signed state and pkce verifier are generated per request,
+// state nonce and verifier are stored in cookie which is checked by callback,
+// provider is linked to user with user id instead of login if id is not empty.
+func (a *Auth) authCodeURL(c echo.Context, name string, provider Provider, userID string) (string, error) {
+	redirect := c.QueryParam("redirect")
+	err := a.checkRedirect(redirect)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := provider.OAuthConfig(c.Request().Context())
+	if err != nil {
+		return "", err
+	}
+
+	state, err := oauthstate.New(name, redirect, a.Config.OAuthStateTTL)
+	if err != nil {
+		return "", err
+	}
+	state.UserID = userID
+	encoded, err := oauthstate.Encode(state, a.Config.HMACSecret)
+	if err != nil {
+		return "", err
+	}
+
+	verifier, err := oauthstate.NewVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	c.SetCookie(a.stateCookie(name, state.Nonce+"."+verifier, int(a.Config.OAuthStateTTL/time.Second)))
+
+	opts := append(provider.AuthCodeOptions(state.Nonce),
+		oauth2.SetAuthURLParam("code_challenge", oauthstate.Challenge(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	)
+	return config.AuthCodeURL(encoded, opts...), nil
+}
+
+// verifyState is used to validate state of callback against cookie set by login,
+// cookie is removed so state can be used once, returns state and pkce verifier.
+func (a *Auth) verifyState(c echo.Context, provider string) (*oauthstate.State, string, error) {
+	state, err := oauthstate.Decode(c.QueryParam("state"), a.Config.HMACSecret)
+	if err != nil {
+		return nil, "", err
+	}
+	if state.Provider != provider {
+		return nil, "", fmt.Errorf("state was issued for %s", state.Provider)
+	}
+
+	cookie, err := c.Cookie(stateCookieName(provider))
+	if err != nil {
+		return nil, "", fmt.Errorf("state cookie is missing")
+	}
+	c.SetCookie(a.stateCookie(provider, "", -1))
+
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	if len(parts) != 2 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state.Nonce)) != 1 {
+		return nil, "", fmt.Errorf("state does not match cookie")
+	}
+
+	return state, parts[1], nil
+}
+
+// codeVerifier is used to pass pkce verifier to code exchange.
+func codeVerifier(verifier string) oauth2.AuthCodeOption {
+	return oauth2.SetAuthURLParam("code_verifier", verifier)
+}
+
+// loginRedirect is used to redirect to post login url carried in state, tokens are passed in url fragment
+// so they never reach server logs of redirect target.
+func (a *Auth) loginRedirect(c echo.Context, redirect string, accessToken string, refreshToken string) error {
+	fragment := url.Values{}
+	fragment.Set("token", accessToken)
+	fragment.Set("refreshToken", refreshToken)
+
+	u, _ := url.Parse(redirect)
+	u.Fragment = ""
+	return c.Redirect(http.StatusSeeOther, u.String()+"#"+fragment.Encode())
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent output data of UnlinkProviderHandler
+type UnlinkProviderHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name UnlinkProviderResponse
+
+// UnlinkProviderHandler godoc
+//
+// @id				UnlinkProvider
+// @Summary 		Unlinks identity provider from current user.
+// @Description 	Removes provider account linked to current user, last linked provider can not be removed.
+//
+// @Tags			Auth
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param provider path string true "Name of identity provider"
+//
+// @Success 200 	{object} UnlinkProviderHandlerResponseBody
+// @Failure 401,404,409 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /auth/{provider}/link [DELETE]
+func (a *Auth) UnlinkProviderHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), a.Logger.Named("UnlinkProviderHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get provider from path
+	name := c.Param("provider")
+	logger = logger.With("provider", name)
+
+	// remove provider unless it is the last way to login
+	logger.Infow("unlinking provider")
+	err := a.MySQL.WithContext(c.Request().Context()).Transaction(func(tx *gorm.DB) error {
+		var linked []models.AuthProvider
+		err := tx.Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{UserID: token.UserID}).
+			Find(&linked).
+			Error
+		if err != nil {
+			return err
+		}
+
+		var found bool
+		for _, authProvider := range linked {
+			if authProvider.AuthProviderType == models.AuthProviderType(name) {
+				found = true
+			}
+		}
+		if !found {
+			return gorm.ErrRecordNotFound
+		}
+		if len(linked) == 1 {
+			return errLastProvider
+		}
+
+		// remove record so provider account can be linked again
+		return tx.Unscoped().
+			Where(&models.AuthProvider{UserID: token.UserID, AuthProviderType: models.AuthProviderType(name)}).
+			Delete(&models.AuthProvider{}).
+			Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		logger.Errorw("provider is not linked")
+		return problem.NotFound("provider is not linked")
+	}
+	if err == errLastProvider {
+		logger.Errorw("failed to unlink last provider")
+		return problem.Conflict(err.Error())
+	}
+	if err != nil {
+		logger.Errorw("failed to unlink provider", "err", err)
+		return problem.Internal("failed to unlink provider")
+	}
+
+	logger.Infow("successfully unlinked provider")
+	return a.ResponseWriter(c, http.StatusOK, UnlinkProviderHandlerResponseBody{
+		Message: "successfully unlinked provider",
+	})
+}
@@ -3,13 +3,16 @@ package tests
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
 	"github.com/google/uuid"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm/clause"
 )
@@ -19,8 +22,7 @@ func TestFacebookAuth(t *testing.T) {
 	teardown := StubServices()
 
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// get facebook fixtures
 	facebookFixtures := GetFacebookFixtures()
@@ -31,19 +33,38 @@ func TestFacebookAuth(t *testing.T) {
 
 	// test entity ids
 	var testUserId uuid.UUID
+	var otherUserIds []uuid.UUID
+
+	// stubFacebookUser is used to respond with other facebook account until returned restore is called
+	stubFacebookUser := func(user *auth.FacebookUserData) func() {
+		httpmock.RegisterResponder(http.MethodGet, "https://graph.facebook.com/me",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, user)
+			},
+		)
+		return func() {
+			httpmock.RegisterResponder(http.MethodGet, "https://graph.facebook.com/me",
+				func(req *http.Request) (*http.Response, error) {
+					return httpmock.NewJsonResponse(http.StatusOK, &facebookFixtures.FacebookUser)
+				},
+			)
+		}
+	}
 
 	defer func() {
 		// cleanup stubs
 		teardown()
 
-		// clean test user
-		err := a.MySQL.
-			Unscoped().
-			Where(&models.User{Base: models.Base{ID: testUserId}}).
-			Select(clause.Associations).
-			Delete(&models.User{}).
-			Error
-		require.NoError(t, err, "failed to delete test user")
+		// clean test users
+		for _, id := range append(otherUserIds, testUserId) {
+			err := a.MySQL.
+				Unscoped().
+				Where(&models.User{Base: models.Base{ID: id}}).
+				Select(clause.Associations).
+				Delete(&models.User{}).
+				Error
+			require.NoError(t, err, "failed to delete test user")
+		}
 	}()
 
 	t.Run("should redirect to facebook popup", func(t *testing.T) {
@@ -57,11 +78,59 @@ func TestFacebookAuth(t *testing.T) {
 		require.Equal(t, http.StatusTemporaryRedirect, res.Status, "unexpected response status")
 	})
 
+	t.Run("should not login with invalid facebook user response", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			status   int
+			response interface{}
+		}{
+			{name: "error status", status: http.StatusUnauthorized, response: map[string]string{"error": "invalid token"}},
+			{name: "empty subject", status: http.StatusOK, response: &auth.FacebookUserData{Email: facebookFixtures.FacebookUser.Email, Name: facebookFixtures.FacebookUser.Name}},
+		}
+
+		// restore stub of facebook user after test cases
+		defer httpmock.RegisterResponder(http.MethodGet, "https://graph.facebook.com/me",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, &facebookFixtures.FacebookUser)
+			},
+		)
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				httpmock.RegisterResponder(http.MethodGet, "https://graph.facebook.com/me",
+					func(req *http.Request) (*http.Response, error) {
+						return httpmock.NewJsonResponse(tt.status, tt.response)
+					},
+				)
+
+				state, cookies := StartLogin(t, &testClient, "facebook", "")
+				err := testClient.Request(&testclient.RequestOptions{
+					Method:  "GET",
+					URL:     CallbackURL("facebook", state),
+					Cookies: cookies,
+				})
+				require.Error(t, err, "user was logged in")
+				require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusUnauthorized), "unexpected response status")
+
+				var count int64
+				err = a.MySQL.
+					Model(&models.AuthProvider{}).
+					Where("provider_uid = ? AND auth_provider_type = ?", "", "facebook").
+					Count(&count).
+					Error
+				require.NoError(t, err, "failed to count auth providers")
+				require.Zero(t, count, "auth provider with empty subject was stored")
+			})
+		}
+	})
+
 	t.Run("should perform callback logic", func(t *testing.T) {
-		var res auth.FacebookCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "facebook", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/facebook/callback?state=%s", a.Config.FacebookClientState),
+			URL:      CallbackURL("facebook", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -69,16 +138,25 @@ func TestFacebookAuth(t *testing.T) {
 	})
 
 	t.Run("user should be created in database", func(t *testing.T) {
-		var user models.User
+		var provider models.AuthProvider
 		err := a.MySQL.
+			Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{ProviderUID: facebookFixtures.FacebookUser.ID}).
+			First(&provider).
+			Error
+		require.NoError(t, err, "failed to get auth provider")
+
+		var user models.User
+		err = a.MySQL.
 			Model(&models.User{}).
-			Where(&models.User{Email: facebookFixtures.FacebookUser.Email}).
+			Where(&models.User{Base: models.Base{ID: provider.UserID}}).
 			First(&user).
 			Error
 		require.NoError(t, err, "parsed invalid uuid")
 		require.Equal(t, user.UserRole, models.UserRoleUser, "unexpected user role")
 		require.Equal(t, user.Username, facebookFixtures.FacebookUser.Name, "unexpected user name")
-		require.Equal(t, user.Email, facebookFixtures.FacebookUser.Email, "unexpected email address")
+		// facebook does not verify emails, so email is not stored
+		require.Empty(t, user.Email, "unverified email address was stored")
 		testUserId = user.ID
 	})
 
@@ -96,10 +174,12 @@ func TestFacebookAuth(t *testing.T) {
 	})
 
 	t.Run("should login user without creating new one", func(t *testing.T) {
-		var res auth.FacebookCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "facebook", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/facebook/callback?state=%s", a.Config.FacebookClientState),
+			URL:      CallbackURL("facebook", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -110,10 +190,93 @@ func TestFacebookAuth(t *testing.T) {
 		var user []models.User
 		err := a.MySQL.
 			Model(&models.User{}).
-			Where(&models.User{Email: facebookFixtures.FacebookUser.Email}).
+			Where(&models.User{Username: facebookFixtures.FacebookUser.Name}).
 			Find(&user).
 			Error
 		require.NoError(t, err, "parsed invalid uuid")
-		require.Len(t, user, 1, "unexpected user role")
+		require.Len(t, user, 1, "unexpected number of users")
+	})
+
+	t.Run("should register user of facebook account without email", func(t *testing.T) {
+		restore := stubFacebookUser(&auth.FacebookUserData{ID: "Facebook#456", Name: "facebook without email"})
+		defer restore()
+
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "facebook", "")
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("facebook", state),
+			Cookies:  cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to register user without email")
+		require.NotEmpty(t, res.Token, "empty token field")
+
+		var provider models.AuthProvider
+		err = a.MySQL.
+			Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{ProviderUID: "Facebook#456"}).
+			First(&provider).
+			Error
+		require.NoError(t, err, "auth provider was not created")
+		require.NotEqual(t, testUserId, provider.UserID, "identity was linked to other user")
+		otherUserIds = append(otherUserIds, provider.UserID)
+	})
+
+	t.Run("should not merge accounts by facebook email", func(t *testing.T) {
+		// register user with email of facebook account which is not linked yet
+		user := models.User{
+			Username: facebookFixtures.FacebookUser.Name,
+			Email:    facebookFixtures.FacebookUser.Email,
+			UserRole: models.UserRoleUser,
+		}
+		err := a.MySQL.Create(&user).Error
+		require.NoError(t, err, "failed to create user")
+		otherUserIds = append(otherUserIds, user.ID)
+
+		restore := stubFacebookUser(&auth.FacebookUserData{ID: "Facebook#789", Email: facebookFixtures.FacebookUser.Email, Name: "facebook other"})
+		defer restore()
+
+		state, cookies := StartLogin(t, &testClient, "facebook", "")
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:  "GET",
+			URL:     CallbackURL("facebook", state),
+			Cookies: cookies,
+		})
+		require.Error(t, err, "accounts were merged")
+		require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusConflict), "unexpected response status")
+	})
+
+	t.Run("should link facebook to current user", func(t *testing.T) {
+		restore := stubFacebookUser(&auth.FacebookUserData{ID: "Facebook#789", Email: facebookFixtures.FacebookUser.Email, Name: "facebook other"})
+		defer restore()
+
+		userClient := testclient.TestClient{}
+		userClient.Setup(&testclient.Options{
+			Router: a.Echo,
+			Token:  access.MustEncodeToken(&access.Token{UserID: otherUserIds[len(otherUserIds)-1]}, a.Config.HMACSecret),
+		})
+
+		var link auth.LinkProviderHandlerResponseBody
+		var defaultRes testclient.DefaultResponse
+		err := userClient.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/facebook/link",
+			Response:        &link,
+			DefaultResponse: &defaultRes,
+		})
+		require.NoError(t, err, "failed to start linking")
+
+		location, err := url.Parse(link.URL)
+		require.NoError(t, err, "failed to parse provider url")
+
+		var res auth.CallbackHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("facebook", location.Query().Get("state")),
+			Cookies:  defaultRes.Cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to link provider")
 	})
 }
@@ -52,8 +52,9 @@ func GetFacebookFixtures() FacebookFixtures {
 }
 
 type GithubFixtures struct {
-	Token      *oauth2.Token
-	GithubUser *github.User
+	Token        *oauth2.Token
+	GithubUser   *github.User
+	GithubEmails []*github.UserEmail
 }
 
 func GetGithubFixtures() GithubFixtures {
@@ -61,6 +62,9 @@ func GetGithubFixtures() GithubFixtures {
 	githubEmail := "github_auth@test.com"
 	githubName := "facebook auth"
 	githubAvatar := "https://picsum.photos/300/300"
+	githubSecondaryEmail := "github_auth_secondary@test.com"
+	primary, verified := true, true
+	secondary, unverified := false, false
 
 	return GithubFixtures{
 		Token: &oauth2.Token{
@@ -75,5 +79,9 @@ func GetGithubFixtures() GithubFixtures {
 			Name:      &githubName,
 			AvatarURL: &githubAvatar,
 		},
+		GithubEmails: []*github.UserEmail{
+			{Email: &githubSecondaryEmail, Primary: &secondary, Verified: &unverified},
+			{Email: &githubEmail, Primary: &primary, Verified: &verified},
+		},
 	}
 }
@@ -5,11 +5,13 @@ import (
 	"net/http"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/auth"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/go-github/github"
 	"github.com/google/uuid"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm/clause"
 )
@@ -19,8 +21,7 @@ func TestGithubAuth(t *testing.T) {
 	teardown := StubServices()
 
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// get github fixtures
 	githubFixtures := GetGithubFixtures()
@@ -57,11 +58,52 @@ func TestGithubAuth(t *testing.T) {
 		require.Equal(t, http.StatusTemporaryRedirect, res.Status, "unexpected response status")
 	})
 
+	t.Run("should not merge accounts by unverified primary email", func(t *testing.T) {
+		// register user with email of github account
+		user := models.User{
+			Username: githubFixtures.GithubUser.GetName(),
+			Email:    githubFixtures.GithubUser.GetEmail(),
+			UserRole: models.UserRoleUser,
+		}
+		err := a.MySQL.Create(&user).Error
+		require.NoError(t, err, "failed to create user")
+		defer func() {
+			err := a.MySQL.Unscoped().Delete(&user).Error
+			require.NoError(t, err, "failed to delete user")
+		}()
+
+		// primary email is listed as unverified
+		primary, verified := true, false
+		httpmock.RegisterResponder(http.MethodGet, "https://api.github.com/user/emails",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, []*github.UserEmail{
+					{Email: githubFixtures.GithubUser.Email, Primary: &primary, Verified: &verified},
+				})
+			},
+		)
+		defer httpmock.RegisterResponder(http.MethodGet, "https://api.github.com/user/emails",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, &githubFixtures.GithubEmails)
+			},
+		)
+
+		state, cookies := StartLogin(t, &testClient, "github", "")
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:  "GET",
+			URL:     CallbackURL("github", state),
+			Cookies: cookies,
+		})
+		require.Error(t, err, "accounts were merged by unverified email")
+		require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusConflict), "unexpected response status")
+	})
+
 	t.Run("should perform callback logic", func(t *testing.T) {
-		var res auth.GithubCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "github", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/github/callback?state=%s", a.Config.GithubClientState),
+			URL:      CallbackURL("github", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -96,10 +138,12 @@ func TestGithubAuth(t *testing.T) {
 	})
 
 	t.Run("should login user without creating new one", func(t *testing.T) {
-		var res auth.GithubCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "github", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/github/callback?state=%s", a.Config.GithubClientState),
+			URL:      CallbackURL("github", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -5,11 +5,12 @@ import (
 	"net/http"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/auth"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
 	"github.com/google/uuid"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm/clause"
 )
@@ -19,8 +20,7 @@ func TestGoogleAuth(t *testing.T) {
 	teardown := StubServices()
 
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// get google fixtures
 	googleFixtures := GetGoogleFixtures()
@@ -57,11 +57,59 @@ func TestGoogleAuth(t *testing.T) {
 		require.Equal(t, http.StatusTemporaryRedirect, res.Status, "unexpected response status")
 	})
 
+	t.Run("should not login with invalid google user response", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			status   int
+			response interface{}
+		}{
+			{name: "error status", status: http.StatusUnauthorized, response: map[string]string{"error": "invalid token"}},
+			{name: "empty subject", status: http.StatusOK, response: &auth.GoogleUserData{Email: googleFixtures.GoogleUser.Email, EmailVerified: true}},
+		}
+
+		// restore stub of google user after test cases
+		defer httpmock.RegisterResponder(http.MethodGet, "https://www.googleapis.com/oauth2/v2/userinfo",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(http.StatusOK, &googleFixtures.GoogleUser)
+			},
+		)
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				httpmock.RegisterResponder(http.MethodGet, "https://www.googleapis.com/oauth2/v2/userinfo",
+					func(req *http.Request) (*http.Response, error) {
+						return httpmock.NewJsonResponse(tt.status, tt.response)
+					},
+				)
+
+				state, cookies := StartLogin(t, &testClient, "google", "")
+				err := testClient.Request(&testclient.RequestOptions{
+					Method:  "GET",
+					URL:     CallbackURL("google", state),
+					Cookies: cookies,
+				})
+				require.Error(t, err, "user was logged in")
+				require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusUnauthorized), "unexpected response status")
+
+				var count int64
+				err = a.MySQL.
+					Model(&models.AuthProvider{}).
+					Where("provider_uid = ? AND auth_provider_type = ?", "", "google").
+					Count(&count).
+					Error
+				require.NoError(t, err, "failed to count auth providers")
+				require.Zero(t, count, "auth provider with empty subject was stored")
+			})
+		}
+	})
+
 	t.Run("should perform callback logic", func(t *testing.T) {
-		var res auth.GoogleCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "google", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/google/callback?state=%s", a.Config.GoogleClientState),
+			URL:      CallbackURL("google", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -96,10 +144,12 @@ func TestGoogleAuth(t *testing.T) {
 	})
 
 	t.Run("should login user without creating new one", func(t *testing.T) {
-		var res auth.GoogleCallbackHandlerResponseBody
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "google", "")
 		err := testClient.Request(&testclient.RequestOptions{
 			Method:   "GET",
-			URL:      fmt.Sprintf("/api/v2/auth/google/callback?state=%s", a.Config.GoogleClientState),
+			URL:      CallbackURL("google", state),
+			Cookies:  cookies,
 			Response: &res,
 		})
 		require.NoError(t, err, "parsed invalid uuid")
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestAuthLink(t *testing.T) {
+	// setup stub
+	teardown := StubServices()
+
+	// init service
+	a := apptest.New()
+
+	// get fixtures
+	googleFixtures := GetGoogleFixtures()
+	githubFixtures := GetGithubFixtures()
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	defer func() {
+		// cleanup stubs
+		teardown()
+
+		// clean test users
+		for _, email := range []string{googleFixtures.GoogleUser.Email, *githubFixtures.GithubUser.Email} {
+			err := a.MySQL.
+				Unscoped().
+				Where(&models.User{Email: email}).
+				Select(clause.Associations).
+				Delete(&models.User{}).
+				Error
+			require.NoError(t, err, "failed to delete test user")
+		}
+	}()
+
+	// login with google
+	var login auth.CallbackHandlerResponseBody
+	state, cookies := StartLogin(t, &testClient, "google", "")
+	err := testClient.Request(&testclient.RequestOptions{
+		Method:   "GET",
+		URL:      CallbackURL("google", state),
+		Cookies:  cookies,
+		Response: &login,
+	})
+	require.NoError(t, err, "failed to login")
+
+	// init client of logged in user
+	userClient := testclient.TestClient{}
+	userClient.Setup(&testclient.Options{Router: a.Echo, Token: *login.Token})
+
+	// linkedProviders is used to list names of linked providers
+	linkedProviders := func(t *testing.T) []string {
+		var res auth.GetLinkedProvidersHandlerResponseBody
+		err := userClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/auth/providers",
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to get linked providers")
+
+		var names []string
+		for _, provider := range *res.Providers {
+			names = append(names, provider.Provider)
+		}
+		return names
+	}
+
+	t.Run("should list linked providers", func(t *testing.T) {
+		require.Equal(t, []string{"google"}, linkedProviders(t), "unexpected linked providers")
+	})
+
+	t.Run("should fail to unlink last provider", func(t *testing.T) {
+		err := userClient.Request(&testclient.RequestOptions{
+			Method: "DELETE",
+			URL:    "/api/v2/auth/google/link",
+		})
+		require.Error(t, err, "last provider was unlinked")
+	})
+
+	t.Run("should fail to link without token", func(t *testing.T) {
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/auth/github/link",
+		})
+		require.Error(t, err, "provider was linked without token")
+	})
+
+	t.Run("should link provider to current user", func(t *testing.T) {
+		var link auth.LinkProviderHandlerResponseBody
+		var defaultRes testclient.DefaultResponse
+		err := userClient.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/github/link",
+			Response:        &link,
+			DefaultResponse: &defaultRes,
+		})
+		require.NoError(t, err, "failed to start linking")
+
+		location, err := url.Parse(link.URL)
+		require.NoError(t, err, "failed to parse provider url")
+
+		var res auth.CallbackHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("github", location.Query().Get("state")),
+			Cookies:  defaultRes.Cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to link provider")
+		require.Nil(t, res.Token, "tokens were issued by linking")
+		require.Equal(t, []string{"google", "github"}, linkedProviders(t), "unexpected linked providers")
+	})
+
+	t.Run("should login linked user with linked provider", func(t *testing.T) {
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "github", "")
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("github", state),
+			Cookies:  cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to login")
+
+		var count int64
+		err = a.MySQL.
+			Model(&models.User{}).
+			Where(&models.User{Email: *githubFixtures.GithubUser.Email}).
+			Count(&count).
+			Error
+		require.NoError(t, err, "failed to count users")
+		require.Equal(t, int64(0), count, "new user was registered")
+	})
+
+	t.Run("should unlink provider", func(t *testing.T) {
+		err := userClient.Request(&testclient.RequestOptions{
+			Method:   "DELETE",
+			URL:      "/api/v2/auth/github/link",
+			Response: &auth.UnlinkProviderHandlerResponseBody{},
+		})
+		require.NoError(t, err, "failed to unlink provider")
+		require.Equal(t, []string{"google"}, linkedProviders(t), "unexpected linked providers")
+
+		err = userClient.Request(&testclient.RequestOptions{
+			Method: "DELETE",
+			URL:    "/api/v2/auth/github/link",
+		})
+		require.Error(t, err, "provider was unlinked twice")
+	})
+
+	t.Run("should not merge accounts by email if auto linking is disabled", func(t *testing.T) {
+		// register user with email of github account
+		user := models.User{Email: *githubFixtures.GithubUser.Email, UserRole: models.UserRoleUser}
+		err := a.MySQL.Create(&user).Error
+		require.NoError(t, err, "failed to create user")
+
+		a.Config.AuthAutoLink = false
+		defer func() { a.Config.AuthAutoLink = true }()
+
+		state, cookies := StartLogin(t, &testClient, "github", "")
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:  "GET",
+			URL:     CallbackURL("github", state),
+			Cookies: cookies,
+		})
+		require.Error(t, err, "accounts were merged")
+		require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusConflict), "unexpected response status")
+	})
+
+	t.Run("should merge accounts with verified email if auto linking is enabled", func(t *testing.T) {
+		var res auth.CallbackHandlerResponseBody
+		state, cookies := StartLogin(t, &testClient, "github", "")
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("github", state),
+			Cookies:  cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to login")
+
+		var user models.User
+		err = a.MySQL.
+			Model(&models.User{}).
+			Where(&models.User{Email: *githubFixtures.GithubUser.Email}).
+			Preload("AuthProvider").
+			First(&user).
+			Error
+		require.NoError(t, err, "failed to find user")
+		require.Len(t, user.AuthProvider, 1, "provider was not linked")
+	})
+}
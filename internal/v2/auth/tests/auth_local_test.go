@@ -0,0 +1,324 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/mailer"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+// mailedToken is used to get token from latest email sent to address by in-memory mailer.
+func mailedToken(t *testing.T, a *app.Application, email string) string {
+	memory, ok := a.Mailer.(*mailer.Memory)
+	require.True(t, ok, "mailer is not in-memory mailer")
+
+	message, ok := memory.Last(email)
+	require.True(t, ok, "email was not sent")
+
+	// token is separate paragraph following greeting
+	paragraphs := strings.Split(message.Body, "\n\n")
+	require.GreaterOrEqual(t, len(paragraphs), 3, "unexpected email body")
+	return strings.TrimSpace(paragraphs[2])
+}
+
+func TestAuthLocal(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	email := "local_auth@test.com"
+	oauthEmail := "local_auth_oauth@test.com"
+	pass := "correct horse battery staple"
+	newPass := "correct horse battery staple 2"
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	// user registered with provider only
+	oauthUser := models.User{
+		Username: "local_auth_oauth",
+		Email:    oauthEmail,
+		UserRole: models.UserRoleUser,
+	}
+	err := a.MySQL.Create(&oauthUser).Error
+	require.NoError(t, err, "failed to create test user")
+
+	defer func() {
+		// clean test users
+		for _, email := range []string{email, oauthEmail} {
+			err := a.MySQL.
+				Unscoped().
+				Where(&models.User{Email: email}).
+				Select(clause.Associations).
+				Delete(&models.User{}).
+				Error
+			require.NoError(t, err, "failed to delete test user")
+		}
+	}()
+
+	// login is used to login with email and password
+	login := func(email string, password string) (*auth.LocalLoginHandlerResponseBody, error) {
+		var res auth.LocalLoginHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/auth/local/login",
+			Body: &auth.LocalLoginHandlerRequestBody{
+				Email:    email,
+				Password: password,
+			},
+			Response: &res,
+		})
+		return &res, err
+	}
+
+	t.Run("should register user", func(t *testing.T) {
+		var res auth.RegisterHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/auth/local/register",
+			Body: &auth.RegisterHandlerRequestBody{
+				Email:    strings.ToUpper(email),
+				Username: "local_auth",
+				Password: pass,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to register")
+		require.Equal(t, email, res.User.Email, "email was not normalized")
+
+		var authProvider models.AuthProvider
+		err = a.MySQL.
+			Where(&models.AuthProvider{UserID: res.User.ID, AuthProviderType: models.AuthProviderTypeLocal}).
+			First(&authProvider).
+			Error
+		require.NoError(t, err, "failed to find local auth provider")
+		require.NotContains(t, authProvider.PasswordHash, pass, "password stored in plain text")
+		require.True(t, strings.HasPrefix(authProvider.PasswordHash, "$argon2id$"), "unexpected password hash")
+	})
+
+	t.Run("should not register same email twice", func(t *testing.T) {
+		var res auth.RegisterHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/auth/local/register",
+			Body: &auth.RegisterHandlerRequestBody{
+				Email:    email,
+				Username: "local_auth",
+				Password: pass,
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "registered same email twice")
+		require.Contains(t, err.Error(), fmt.Sprint(409), "unexpected status code")
+	})
+
+	t.Run("should not login before email is verified", func(t *testing.T) {
+		_, err := login(email, pass)
+		require.Error(t, err, "logged in with unverified email")
+		require.Contains(t, err.Error(), fmt.Sprint(403), "unexpected status code")
+	})
+
+	t.Run("should verify email", func(t *testing.T) {
+		token := mailedToken(t, a, email)
+
+		var res auth.VerifyEmailHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/local/verify",
+			Body:     &auth.VerifyEmailHandlerRequestBody{Token: token},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to verify email")
+
+		// token is single use
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/local/verify",
+			Body:     &auth.VerifyEmailHandlerRequestBody{Token: token},
+			Response: &res,
+		})
+		require.Error(t, err, "verification token used twice")
+	})
+
+	t.Run("should login with password", func(t *testing.T) {
+		_, err := login(email, "wrong password")
+		require.Error(t, err, "logged in with wrong password")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+
+		_, err = login("unknown_local_auth@test.com", pass)
+		require.Error(t, err, "logged in with unknown email")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+
+		res, err := login(email, pass)
+		require.NoError(t, err, "failed to login")
+		require.NotNil(t, res.Token, "access token is missing")
+		require.NotNil(t, res.RefreshToken, "refresh token is missing")
+
+		// local provider is listed with linked providers
+		client := testclient.TestClient{}
+		client.Setup(&testclient.Options{Router: a.Echo, Token: *res.Token})
+		var providers auth.GetLinkedProvidersHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/auth/providers",
+			Response: &providers,
+		})
+		require.NoError(t, err, "failed to get linked providers")
+		require.Len(t, *providers.Providers, 1, "unexpected amount of providers")
+		require.Equal(t, "local", (*providers.Providers)[0].Provider, "unexpected provider")
+	})
+
+	t.Run("should change password", func(t *testing.T) {
+		res, err := login(email, pass)
+		require.NoError(t, err, "failed to login")
+
+		client := testclient.TestClient{}
+		client.Setup(&testclient.Options{Router: a.Echo, Token: *res.Token})
+
+		var change auth.ChangePasswordHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    "/api/v2/auth/local/password",
+			Body: &auth.ChangePasswordHandlerRequestBody{
+				CurrentPassword: "wrong password",
+				NewPassword:     newPass,
+			},
+			Response: &change,
+		})
+		require.Error(t, err, "changed password without current password")
+		require.Contains(t, err.Error(), fmt.Sprint(403), "unexpected status code")
+
+		err = client.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    "/api/v2/auth/local/password",
+			Body: &auth.ChangePasswordHandlerRequestBody{
+				CurrentPassword: pass,
+				NewPassword:     newPass,
+			},
+			Response: &change,
+		})
+		require.NoError(t, err, "failed to change password")
+
+		_, err = login(email, newPass)
+		require.NoError(t, err, "failed to login with new password")
+	})
+
+	t.Run("should reset password", func(t *testing.T) {
+		// response is same for unknown email
+		var forgot auth.ForgotPasswordHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/local/password/forgot",
+			Body:     &auth.ForgotPasswordHandlerRequestBody{Email: "unknown_local_auth@test.com"},
+			Response: &forgot,
+		})
+		require.NoError(t, err, "failed to request password reset")
+
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/local/password/forgot",
+			Body:     &auth.ForgotPasswordHandlerRequestBody{Email: email},
+			Response: &forgot,
+		})
+		require.NoError(t, err, "failed to request password reset")
+
+		var reset auth.ResetPasswordHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/auth/local/password/reset",
+			Body: &auth.ResetPasswordHandlerRequestBody{
+				Token:    mailedToken(t, a, email),
+				Password: pass,
+			},
+			Response: &reset,
+		})
+		require.NoError(t, err, "failed to reset password")
+
+		_, err = login(email, newPass)
+		require.Error(t, err, "logged in with old password")
+
+		_, err = login(email, pass)
+		require.NoError(t, err, "failed to login with reset password")
+	})
+
+	t.Run("should throttle failed logins", func(t *testing.T) {
+		for i := 0; i < a.Config.LoginMaxAttempts; i++ {
+			_, err := login(email, "wrong password")
+			require.Error(t, err, "logged in with wrong password")
+			require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+		}
+
+		// correct password is rejected while email is blocked
+		_, err := login(email, pass)
+		require.Error(t, err, "login was not throttled")
+		require.Contains(t, err.Error(), fmt.Sprint(429), "unexpected status code")
+	})
+
+	t.Run("user registered with provider should set password", func(t *testing.T) {
+		client := testclient.TestClient{}
+		client.Setup(&testclient.Options{
+			Router: a.Echo,
+			Token: access.MustEncodeToken(&access.Token{
+				UserID: oauthUser.ID,
+			}, a.Config.HMACSecret),
+		})
+
+		var change auth.ChangePasswordHandlerResponseBody
+		err := client.Request(&testclient.RequestOptions{
+			Method:   "PUT",
+			URL:      "/api/v2/auth/local/password",
+			Body:     &auth.ChangePasswordHandlerRequestBody{NewPassword: pass},
+			Response: &change,
+		})
+		require.NoError(t, err, "failed to set password")
+
+		_, err = login(oauthEmail, pass)
+		require.NoError(t, err, "failed to login with password")
+	})
+
+	t.Run("should throttle failed logins by peer ip", func(t *testing.T) {
+		// spoofedLogin is used to login with forwarded ip header which is not trusted
+		spoofedLogin := func(email string, password string, ip string) error {
+			var res auth.LocalLoginHandlerResponseBody
+			return testClient.Request(&testclient.RequestOptions{
+				Method: "POST",
+				URL:    "/api/v2/auth/local/login",
+				Body: &auth.LocalLoginHandlerRequestBody{
+					Email:    email,
+					Password: password,
+				},
+				Headers:    map[string]string{"X-Forwarded-For": ip, "X-Real-IP": ip},
+				RemoteAddr: "10.3.0.1:4321",
+				Response:   &res,
+			})
+		}
+
+		// session stores peer ip instead of forwarded ip
+		err := spoofedLogin(oauthEmail, pass, "203.0.113.1")
+		require.NoError(t, err, "failed to login")
+		var session models.Session
+		err = a.MySQL.Where(&models.Session{UserID: oauthUser.ID}).Order("created_at desc").First(&session).Error
+		require.NoError(t, err, "failed to get session")
+		require.Equal(t, "10.3.0.1", session.IP, "session stored forwarded ip")
+
+		// rotating forwarded ip does not reset attempts of peer ip
+		for i := 0; i < a.Config.LoginIPMaxAttempts; i++ {
+			err := spoofedLogin(fmt.Sprintf("unknown_local_auth_%d@test.com", i), pass, fmt.Sprintf("203.0.113.%d", i))
+			require.Error(t, err, "logged in with unknown email")
+			require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+		}
+
+		err = spoofedLogin(oauthEmail, pass, "203.0.113.254")
+		require.Error(t, err, "login was not throttled")
+		require.Contains(t, err.Error(), fmt.Sprint(429), "unexpected status code")
+	})
+}
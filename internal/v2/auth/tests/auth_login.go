@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+)
+
+// StartLogin is used to request login with provider, returns state from provider url and cookies set by server.
+func StartLogin(t *testing.T, client *testclient.TestClient, provider string, redirect string) (string, []*http.Cookie) {
+	var res testclient.DefaultResponse
+	err := client.Request(&testclient.RequestOptions{
+		Method:          "GET",
+		URL:             fmt.Sprintf("/api/v2/auth/%s/login?redirect=%s", provider, url.QueryEscape(redirect)),
+		DefaultResponse: &res,
+	})
+	require.NoError(t, err, "failed to start login")
+	require.Equal(t, http.StatusTemporaryRedirect, res.Status, "unexpected response status")
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err, "failed to parse provider url")
+	require.Equal(t, "S256", location.Query().Get("code_challenge_method"), "unexpected code challenge method")
+	require.NotEmpty(t, location.Query().Get("code_challenge"), "empty code challenge")
+	require.NotEmpty(t, location.Query().Get("state"), "empty state")
+	require.NotEmpty(t, res.Cookies, "state cookie was not set")
+
+	return location.Query().Get("state"), res.Cookies
+}
+
+// CallbackURL is used to build callback url of provider with state.
+func CallbackURL(provider string, state string) string {
+	return fmt.Sprintf("/api/v2/auth/%s/callback?code=code&state=%s", provider, url.QueryEscape(state))
+}
+
+// requireCodeVerifier is used to reject token exchange without pkce verifier in stubs.
+func requireCodeVerifier(req *http.Request) bool {
+	err := req.ParseForm()
+	return err == nil && req.PostForm.Get("code_verifier") != ""
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestLogoutHandlers(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// create test user
+	user := models.User{
+		Username: "logout",
+		Email:    "logout@test.com",
+		UserRole: models.UserRoleUser,
+	}
+	err := a.MySQL.Create(&user).Error
+	require.NoError(t, err, "failed to create test user")
+
+	defer func() {
+		// clean test user
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.User{Base: models.Base{ID: user.ID}}).
+			Select(clause.Associations).
+			Delete(&models.User{}).
+			Error
+		require.NoError(t, err, "failed to delete test user")
+	}()
+
+	client := testclient.TestClient{}
+	client.Setup(&testclient.Options{Router: a.Echo})
+
+	// login is used to start session and return its tokens
+	login := func() auth.RefreshTokenHandlerHandlerResponseBody {
+		refreshToken, hash, err := access.NewRefreshToken()
+		require.NoError(t, err, "failed to generate refresh token")
+		err = a.MySQL.Create(&models.Session{
+			UserID:    user.ID,
+			FamilyID:  uuid.New(),
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}).Error
+		require.NoError(t, err, "failed to create test session")
+
+		var res auth.RefreshTokenHandlerHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/refresh",
+			Body:     auth.RefreshTokenHandlerRequestBody{RefreshToken: refreshToken},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to start session")
+
+		return res
+	}
+
+	// authorized is used to create client with access token
+	authorized := func(token string) *testclient.TestClient {
+		c := testclient.TestClient{}
+		c.Setup(&testclient.Options{Router: a.Echo, Token: token})
+		return &c
+	}
+
+	t.Run("should fail to logout without auth token", func(t *testing.T) {
+		var res testclient.DefaultResponse
+		err := client.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/logout",
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail to logout if token is not bound to session", func(t *testing.T) {
+		var res testclient.DefaultResponse
+		err := authorized(access.MustEncodeToken(&access.Token{UserID: user.ID, UserRole: user.UserRole}, a.Config.HMACSecret)).
+			Request(&testclient.RequestOptions{
+				Method:          "POST",
+				URL:             "/api/v2/auth/logout",
+				DefaultResponse: &res,
+			})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, 400, res.Status, "unexpected response status")
+	})
+
+	t.Run("should logout current session", func(t *testing.T) {
+		current := login()
+		other := login()
+
+		var res auth.LogoutHandlerResponseBody
+		err := authorized(*current.Token).Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/logout",
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		// current session is revoked
+		var def testclient.DefaultResponse
+		err = authorized(*current.Token).Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             "/api/v2/users/me",
+			DefaultResponse: &def,
+		})
+		require.Error(t, err, "unexpected response")
+		err = client.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: *current.RefreshToken},
+			DefaultResponse: &def,
+		})
+		require.Error(t, err, "unexpected response")
+
+		// other session is untouched
+		err = authorized(*other.Token).Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             "/api/v2/users/me",
+			DefaultResponse: &def,
+		})
+		require.NoError(t, err, "unexpected response")
+	})
+
+	t.Run("should logout all sessions", func(t *testing.T) {
+		first := login()
+		second := login()
+
+		var res auth.LogoutAllHandlerResponseBody
+		err := authorized(*first.Token).Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/logout/all",
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		var count int64
+		err = a.MySQL.
+			Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Count(&count).
+			Error
+		require.NoError(t, err, "failed to count sessions")
+		require.Zero(t, count, "sessions are not revoked")
+
+		var def testclient.DefaultResponse
+		for _, session := range []auth.RefreshTokenHandlerHandlerResponseBody{first, second} {
+			err = authorized(*session.Token).Request(&testclient.RequestOptions{
+				Method:          "GET",
+				URL:             "/api/v2/users/me",
+				DefaultResponse: &def,
+			})
+			require.Error(t, err, "unexpected response")
+			err = client.Request(&testclient.RequestOptions{
+				Method:          "POST",
+				URL:             "/api/v2/auth/refresh",
+				Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: *session.RefreshToken},
+				DefaultResponse: &def,
+			})
+			require.Error(t, err, "unexpected response")
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/oauthstate"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/golang-jwt/jwt"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestOIDCAuth(t *testing.T) {
+	issuer := "https://sso.example.com/realms/gorest"
+	email := "oidc.user@example.com"
+
+	// register provider from config
+	os.Setenv("GOREST_OIDC_PROVIDERS", `{"keycloak":{"issuer":"`+issuer+`","client_id":"gorest","client_secret":"secret"}}`)
+	defer os.Unsetenv("GOREST_OIDC_PROVIDERS")
+
+	// signing key of provider
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+
+	// claims of next issued ID token
+	var nonce, audience string
+	var emailVerified bool
+
+	// setup stub
+	teardown := StubServices()
+	httpmock.RegisterResponder(http.MethodGet, issuer+"/.well-known/openid-configuration",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": issuer + "/protocol/openid-connect/auth",
+				"token_endpoint":         issuer + "/protocol/openid-connect/token",
+				"userinfo_endpoint":      issuer + "/protocol/openid-connect/userinfo",
+				"jwks_uri":               issuer + "/protocol/openid-connect/certs",
+			})
+		})
+	httpmock.RegisterResponder(http.MethodGet, issuer+"/protocol/openid-connect/certs",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"kid": "test",
+					"use": "sig",
+					"alg": "RS256",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			})
+		})
+	httpmock.RegisterResponder(http.MethodPost, issuer+"/protocol/openid-connect/token",
+		func(req *http.Request) (*http.Response, error) {
+			if !requireCodeVerifier(req) {
+				return httpmock.NewStringResponse(http.StatusBadRequest, "missing code verifier"), nil
+			}
+
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"iss":            issuer,
+				"sub":            "f1d3a7c2-oidc-subject",
+				"aud":            audience,
+				"exp":            time.Now().Add(time.Minute).Unix(),
+				"iat":            time.Now().Unix(),
+				"nonce":          nonce,
+				"email":          email,
+				"email_verified": emailVerified,
+				"name":           "Oidc User",
+			})
+			token.Header["kid"] = "test"
+			idToken, err := token.SignedString(key)
+			if err != nil {
+				return nil, err
+			}
+
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"access_token":  "oidc-access-token",
+				"refresh_token": "oidc-refresh-token",
+				"token_type":    "Bearer",
+				"expires_in":    300,
+				"id_token":      idToken,
+			})
+		})
+
+	// init service
+	a := apptest.New()
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	defer func() {
+		// cleanup stubs
+		teardown()
+
+		// clean test user
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.User{Email: email}).
+			Select(clause.Associations).
+			Delete(&models.User{}).
+			Error
+		require.NoError(t, err, "failed to delete test user")
+	}()
+
+	// startLogin is used to start login and remember nonce bound to state
+	startLogin := func(t *testing.T) (string, []*http.Cookie) {
+		state, cookies := StartLogin(t, &testClient, "keycloak", "")
+		decoded, err := oauthstate.Decode(state, a.Config.HMACSecret)
+		require.NoError(t, err, "failed to decode state")
+		nonce = decoded.Nonce
+		return state, cookies
+	}
+
+	t.Run("should redirect to discovered authorization endpoint", func(t *testing.T) {
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             "/api/v2/auth/keycloak/login",
+			DefaultResponse: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, http.StatusTemporaryRedirect, res.Status, "unexpected response status")
+		require.Contains(t, res.Header.Get("Location"), issuer+"/protocol/openid-connect/auth?", "unexpected provider url")
+		require.Contains(t, res.Header.Get("Location"), "nonce=", "nonce is missing")
+	})
+
+	t.Run("should fail for unknown provider", func(t *testing.T) {
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             "/api/v2/auth/unknown/login",
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should not merge accounts by unverified email", func(t *testing.T) {
+		// register user with email of provider account
+		user := models.User{Username: "oidc user", Email: email, UserRole: models.UserRoleUser}
+		err := a.MySQL.Create(&user).Error
+		require.NoError(t, err, "failed to create user")
+		defer func() {
+			err := a.MySQL.Unscoped().Delete(&user).Error
+			require.NoError(t, err, "failed to delete user")
+		}()
+
+		audience, emailVerified = "gorest", false
+		state, cookies := startLogin(t)
+
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:  "GET",
+			URL:     CallbackURL("keycloak", state),
+			Cookies: cookies,
+		})
+		require.Error(t, err, "accounts were merged by unverified email")
+		require.Contains(t, err.Error(), fmt.Sprintf("(%d)", http.StatusConflict), "unexpected response status")
+	})
+
+	t.Run("should fail if ID token was issued for other client", func(t *testing.T) {
+		audience, emailVerified = "other", true
+		state, cookies := startLogin(t)
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("keycloak", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail if nonce does not match", func(t *testing.T) {
+		audience, emailVerified = "gorest", true
+		state, cookies := startLogin(t)
+		nonce = "replayed"
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("keycloak", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should perform callback logic", func(t *testing.T) {
+		audience, emailVerified = "gorest", true
+		state, cookies := startLogin(t)
+
+		var res auth.CallbackHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("keycloak", state),
+			Cookies:  cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.NotEmpty(t, res.Token, "empty token field")
+	})
+
+	t.Run("user and auth provider should be created in database", func(t *testing.T) {
+		var user models.User
+		err := a.MySQL.
+			Model(&models.User{}).
+			Where(&models.User{Email: email}).
+			First(&user).
+			Error
+		require.NoError(t, err, "failed to find user")
+
+		var authProvider models.AuthProvider
+		err = a.MySQL.
+			Model(&models.AuthProvider{}).
+			Where(&models.AuthProvider{UserID: user.ID, AuthProviderType: "keycloak"}).
+			First(&authProvider).
+			Error
+		require.NoError(t, err, "failed to find auth provider")
+		require.Equal(t, "f1d3a7c2-oidc-subject", authProvider.ProviderUID, "unexpected provider uid")
+		require.Equal(t, "oidc-refresh-token", authProvider.RefreshToken, "unexpected stored token")
+	})
+}
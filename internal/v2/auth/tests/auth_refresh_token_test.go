@@ -1,15 +1,15 @@
 package tests
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/auth"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
-	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm/clause"
@@ -20,8 +20,7 @@ func TestRefreshTokenHandler(t *testing.T) {
 	teardown := StubServices()
 
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// create test user
 	user := models.User{
@@ -32,29 +31,25 @@ func TestRefreshTokenHandler(t *testing.T) {
 	err := a.MySQL.Create(&user).Error
 	require.NoError(t, err, "failed to create test user")
 
-	// init test clients
-	noTokenClient := testclient.TestClient{}
-	noTokenClient.Setup(&testclient.Options{Router: a.Echo})
+	// newSession is used to store session of new family and return its refresh token
+	newSession := func(expiresAt time.Time) string {
+		refreshToken, hash, err := access.NewRefreshToken()
+		require.NoError(t, err, "failed to generate refresh token")
 
-	noUserInDbClient := testclient.TestClient{}
-	noUserInDbClient.Setup(&testclient.Options{
-		Router: a.Echo,
-		Token:  access.MustEncodeToken(&access.Token{UserID: uuid.New(), UserRole: user.UserRole}, a.Config.HMACSecret),
-	})
+		err = a.MySQL.Create(&models.Session{
+			UserID:    user.ID,
+			FamilyID:  uuid.New(),
+			TokenHash: hash,
+			ExpiresAt: expiresAt,
+		}).Error
+		require.NoError(t, err, "failed to create test session")
 
-	yesterday := time.Now().Add(-24 * time.Hour)
-	oldToken := access.MustEncodeToken(&access.Token{
-		UserID: user.ID, UserRole: user.UserRole,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: yesterday.Add(time.Hour * 24 * 7).Unix(),
-		},
-	}, a.Config.HMACSecret)
-
-	authorizedClient := testclient.TestClient{}
-	authorizedClient.Setup(&testclient.Options{
-		Router: a.Echo,
-		Token:  oldToken,
-	})
+		return refreshToken
+	}
+
+	// init test clients
+	client := testclient.TestClient{}
+	client.Setup(&testclient.Options{Router: a.Echo})
 
 	defer func() {
 		// cleanup stubs
@@ -70,40 +65,115 @@ func TestRefreshTokenHandler(t *testing.T) {
 		require.NoError(t, err, "failed to delete test user")
 	}()
 
-	t.Run("should fail if no auth token provided", func(t *testing.T) {
+	t.Run("should fail if no refresh token provided", func(t *testing.T) {
 		var res testclient.DefaultResponse
-		err := noTokenClient.Request(&testclient.RequestOptions{
-			Method:          "GET",
+		err := client.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{},
+			DefaultResponse: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, 400, res.Status, "unexpected response status")
+	})
+
+	t.Run("should fail if refresh token is unknown", func(t *testing.T) {
+		var res testclient.DefaultResponse
+		err := client.Request(&testclient.RequestOptions{
+			Method:          "POST",
 			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: "unknown"},
 			DefaultResponse: &res,
 		})
 		require.Error(t, err, "unexpected response")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
 	})
 
-	t.Run("should fail if no user with provided id found in database", func(t *testing.T) {
+	t.Run("should fail if session is expired", func(t *testing.T) {
 		var res testclient.DefaultResponse
-		err := noUserInDbClient.Request(&testclient.RequestOptions{
-			Method:          "GET",
+		err := client.Request(&testclient.RequestOptions{
+			Method:          "POST",
 			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: newSession(time.Now().Add(-time.Hour))},
 			DefaultResponse: &res,
 		})
 		require.Error(t, err, "unexpected response")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
 	})
 
-	t.Run("should refresh auth token", func(t *testing.T) {
+	t.Run("should rotate refresh token", func(t *testing.T) {
+		oldRefreshToken := newSession(time.Now().Add(time.Hour))
+
 		var res auth.RefreshTokenHandlerHandlerResponseBody
-		err := authorizedClient.Request(&testclient.RequestOptions{
-			Method:   "GET",
+		err := client.Request(&testclient.RequestOptions{
+			Method:   "POST",
 			URL:      "/api/v2/auth/refresh",
+			Body:     auth.RefreshTokenHandlerRequestBody{RefreshToken: oldRefreshToken},
 			Response: &res,
 		})
 		require.NoError(t, err, "unexpected response")
 		require.NotEmpty(t, res.Token, "token empty in response")
+		require.NotEmpty(t, res.RefreshToken, "refresh token empty in response")
+		require.NotEqual(t, oldRefreshToken, *res.RefreshToken, "refresh token was not rotated")
+
+		var oldSession, newSession models.Session
+		err = a.MySQL.Where(&models.Session{TokenHash: access.HashRefreshToken(oldRefreshToken)}).First(&oldSession).Error
+		require.NoError(t, err, "failed to find old session")
+		require.NotNil(t, oldSession.RotatedAt, "old session was not rotated")
+		err = a.MySQL.Where(&models.Session{TokenHash: access.HashRefreshToken(*res.RefreshToken)}).First(&newSession).Error
+		require.NoError(t, err, "failed to find new session")
+		require.Equal(t, oldSession.FamilyID, newSession.FamilyID, "new session is not in same family")
+
+		token, err := access.DecodeToken(*res.Token, a.Config.HMACSecret)
+		require.NoError(t, err, "failed to decode token")
+		require.Equal(t, user.ID, token.UserID, "unexpected token user id")
+		require.Equal(t, user.UserRole, token.UserRole, "unexpected token user role")
+		require.Equal(t, newSession.FamilyID, token.SessionID, "unexpected token session id")
+		require.LessOrEqual(t, token.ExpiresAt, time.Now().Add(a.Config.AccessTokenTTL).Unix(), "access token is not short lived")
+	})
+
+	t.Run("should revoke session family on refresh token reuse", func(t *testing.T) {
+		oldRefreshToken := newSession(time.Now().Add(time.Hour))
 
-		oldToken, _ := access.DecodeToken(oldToken, a.Config.HMACSecret)
-		newToken, _ := access.DecodeToken(*res.Token, a.Config.HMACSecret)
-		require.Greater(t, newToken.ExpiresAt, oldToken.ExpiresAt, "unexpected expiry date")
-		require.NotEmpty(t, newToken.UserID, "token does not have user id")
-		require.NotEmpty(t, newToken.UserRole, "token does not have user role")
+		var rotated auth.RefreshTokenHandlerHandlerResponseBody
+		err := client.Request(&testclient.RequestOptions{
+			Method:   "POST",
+			URL:      "/api/v2/auth/refresh",
+			Body:     auth.RefreshTokenHandlerRequestBody{RefreshToken: oldRefreshToken},
+			Response: &rotated,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		// present rotated token again
+		var res testclient.DefaultResponse
+		err = client.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: oldRefreshToken},
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+
+		// latest refresh token of family is revoked as well
+		err = client.Request(&testclient.RequestOptions{
+			Method:          "POST",
+			URL:             "/api/v2/auth/refresh",
+			Body:            auth.RefreshTokenHandlerRequestBody{RefreshToken: *rotated.RefreshToken},
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+
+		// access token of family is rejected
+		authorizedClient := testclient.TestClient{}
+		authorizedClient.Setup(&testclient.Options{Router: a.Echo, Token: *rotated.Token})
+		err = authorizedClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             "/api/v2/users/me",
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
 	})
 }
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/oauthstate"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/clause"
+)
+
+func TestOAuthState(t *testing.T) {
+	// setup stub
+	teardown := StubServices()
+
+	// init service
+	a := apptest.New()
+
+	// get google fixtures
+	googleFixtures := GetGoogleFixtures()
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	defer func() {
+		// cleanup stubs
+		teardown()
+
+		// clean test user
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.User{Email: googleFixtures.GoogleUser.Email}).
+			Select(clause.Associations).
+			Delete(&models.User{}).
+			Error
+		require.NoError(t, err, "failed to delete test user")
+	}()
+
+	t.Run("should issue different state per login", func(t *testing.T) {
+		first, _ := StartLogin(t, &testClient, "google", "")
+		second, _ := StartLogin(t, &testClient, "google", "")
+		require.NotEqual(t, first, second, "state is reused")
+	})
+
+	t.Run("should reject foreign post login redirect", func(t *testing.T) {
+		for _, redirect := range []string{"https://evil.example.com/", "//evil.example.com/", "javascript:alert(1)"} {
+			var res testclient.DefaultResponse
+			err := testClient.Request(&testclient.RequestOptions{
+				Method:          "GET",
+				URL:             "/api/v2/auth/google/login?redirect=" + url.QueryEscape(redirect),
+				DefaultResponse: &res,
+			})
+			require.NoError(t, err, "unexpected response")
+			require.Equal(t, http.StatusBadRequest, res.Status, "redirect %s was accepted", redirect)
+		}
+	})
+
+	t.Run("should fail without state cookie", func(t *testing.T) {
+		state, _ := StartLogin(t, &testClient, "google", "")
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail if state does not match cookie", func(t *testing.T) {
+		_, cookies := StartLogin(t, &testClient, "google", "")
+		state, _ := StartLogin(t, &testClient, "google", "")
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail if state is tampered", func(t *testing.T) {
+		state, cookies := StartLogin(t, &testClient, "google", "")
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", "x"+state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail if state was issued for other provider", func(t *testing.T) {
+		state, cookies := StartLogin(t, &testClient, "github", "")
+		for _, cookie := range cookies {
+			cookie.Name = "gorest_oauth_google"
+		}
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should fail if state is expired", func(t *testing.T) {
+		expired := &oauthstate.State{Nonce: "nonce", Provider: "google", ExpiresAt: time.Now().Add(-time.Minute).Unix()}
+		state, err := oauthstate.Encode(expired, a.Config.HMACSecret)
+		require.NoError(t, err, "failed to encode state")
+
+		var res testclient.DefaultResponse
+		err = testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			Cookies:         []*http.Cookie{{Name: "gorest_oauth_google", Value: "nonce.verifier"}},
+			DefaultResponse: &res,
+		})
+		require.Error(t, err, "unexpected response")
+	})
+
+	t.Run("should redirect to post login url with tokens", func(t *testing.T) {
+		state, cookies := StartLogin(t, &testClient, "google", "/welcome?tab=posts")
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, http.StatusSeeOther, res.Status, "unexpected response status")
+
+		location, err := url.Parse(res.Header.Get("Location"))
+		require.NoError(t, err, "failed to parse redirect url")
+		require.Equal(t, "/welcome", location.Path, "unexpected redirect path")
+		require.Equal(t, "posts", location.Query().Get("tab"), "redirect query was lost")
+
+		fragment, err := url.ParseQuery(location.Fragment)
+		require.NoError(t, err, "failed to parse redirect fragment")
+		require.NotEmpty(t, fragment.Get("token"), "empty token in fragment")
+		require.NotEmpty(t, fragment.Get("refreshToken"), "empty refresh token in fragment")
+	})
+
+	t.Run("should not accept state twice", func(t *testing.T) {
+		state, cookies := StartLogin(t, &testClient, "google", "/welcome")
+
+		var res testclient.DefaultResponse
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:          "GET",
+			URL:             CallbackURL("google", state),
+			Cookies:         cookies,
+			DefaultResponse: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		// browser drops cookie removed by first callback
+		var removed bool
+		for _, cookie := range res.Cookies {
+			if cookie.Name == "gorest_oauth_google" && cookie.MaxAge < 0 {
+				removed = true
+			}
+		}
+		require.True(t, removed, "state cookie was not removed")
+	})
+}
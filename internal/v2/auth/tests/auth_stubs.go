@@ -21,6 +21,9 @@ func StubServices() func() {
 					Method: http.MethodPost,
 					URL:    "https://oauth2.googleapis.com/token",
 					Handler: func(req *http.Request) (*http.Response, error) {
+						if !requireCodeVerifier(req) {
+							return httpmock.NewStringResponse(http.StatusBadRequest, "missing code verifier"), nil
+						}
 						return httpmock.NewJsonResponse(http.StatusOK, &googleFixtures.Token)
 					},
 				},
@@ -40,6 +43,9 @@ func StubServices() func() {
 					Method: http.MethodPost,
 					URL:    "https://graph.facebook.com/v3.2/oauth/access_token",
 					Handler: func(req *http.Request) (*http.Response, error) {
+						if !requireCodeVerifier(req) {
+							return httpmock.NewStringResponse(http.StatusBadRequest, "missing code verifier"), nil
+						}
 						return httpmock.NewJsonResponse(http.StatusOK, &facebookFixtures.Token)
 					},
 				},
@@ -59,6 +65,9 @@ func StubServices() func() {
 					Method: http.MethodPost,
 					URL:    "https://github.com/login/oauth/access_token",
 					Handler: func(req *http.Request) (*http.Response, error) {
+						if !requireCodeVerifier(req) {
+							return httpmock.NewStringResponse(http.StatusBadRequest, "missing code verifier"), nil
+						}
 						return httpmock.NewJsonResponse(http.StatusOK, &githubFixtures.Token)
 					},
 				},
@@ -69,6 +78,13 @@ func StubServices() func() {
 						return httpmock.NewJsonResponse(http.StatusOK, &githubFixtures.GithubUser)
 					},
 				},
+				{
+					Method: http.MethodGet,
+					URL:    "https://api.github.com/user/emails",
+					Handler: func(req *http.Request) (*http.Response, error) {
+						return httpmock.NewJsonResponse(http.StatusOK, &githubFixtures.GithubEmails)
+					},
+				},
 			},
 		},
 	})
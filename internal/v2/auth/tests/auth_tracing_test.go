@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/auth"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm/clause"
+)
+
+func TestAuthCallbackTracing(t *testing.T) {
+	// setup stub
+	teardown := StubServices()
+
+	// export spans to memory
+	os.Setenv("GOREST_TRACING_EXPORTER", "memory")
+	defer os.Unsetenv("GOREST_TRACING_EXPORTER")
+
+	// init service
+	a := apptest.New()
+
+	// get google fixtures
+	googleFixtures := GetGoogleFixtures()
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	defer func() {
+		// cleanup stubs
+		teardown()
+
+		// clean test user
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.User{Email: googleFixtures.GoogleUser.Email}).
+			Select(clause.Associations).
+			Delete(&models.User{}).
+			Error
+		require.NoError(t, err, "failed to delete test user")
+	}()
+
+	t.Run("should trace callback with outbound calls and queries", func(t *testing.T) {
+		state, cookies := StartLogin(t, &testClient, "google", "")
+		a.Tracing.Memory.Reset()
+
+		var res auth.CallbackHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      CallbackURL("google", state),
+			Cookies:  cookies,
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		spans := a.Tracing.Memory.GetSpans()
+		require.NotEmpty(t, spans, "no spans were exported")
+
+		// find server span
+		var server trace.SpanContext
+		for _, span := range spans {
+			if span.SpanKind == trace.SpanKindServer {
+				server = span.SpanContext
+			}
+		}
+		require.True(t, server.IsValid(), "server span was not exported")
+
+		// every other span belongs to the same trace
+		var outbound, queries int
+		for _, span := range spans {
+			require.Equal(t, server.TraceID(), span.SpanContext.TraceID(), "span %s belongs to other trace", span.Name)
+			if span.SpanKind != trace.SpanKindClient {
+				continue
+			}
+			if span.Name == "gorm.query" || span.Name == "gorm.create" {
+				queries++
+			} else {
+				outbound++
+			}
+		}
+		require.Equal(t, 2, outbound, "token exchange and user info calls were not traced")
+		require.NotZero(t, queries, "queries were not traced")
+	})
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -12,9 +14,9 @@ import (
 
 // Represent input data of CreateCommentHandler
 type CreateCommentHandlerRequestBody struct {
-	PostID string `json:"postId" form:"postId" binding:"required" validate:"required"`
+	PostID string `json:"postId" form:"postId" binding:"required" validate:"required,post"`
 	Name   string `json:"name" form:"name" binding:"required" validate:"required"`
-	Body   string `json:"body" form:"body" binding:"required" validate:"required"`
+	Body   string `json:"body" form:"body" binding:"required" validate:"required,nohtml"`
 } // @name CreateCommentRequest
 
 // Represent output data of CreateCommentHandler
@@ -32,22 +34,26 @@ type CreateCommentHandlerResponseBody struct {
 // @Tags			Comments
 //
 // @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Param fields body CreateCommentHandlerRequestBody true "data"
 //
 // @Success 201 	{object} CreateCommentHandlerResponseBody
-// @Failure 400,404 {object} CreateCommentHandlerResponseBody
-// @Failure 500 	{object} CreateCommentHandlerResponseBody
-// @Failure default {object} CreateCommentHandlerResponseBody
+// @Failure 400 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /comments [POST]
 func (cm *Comments) CreateCommentHandler(c echo.Context) error {
-	logger := cm.Logger.Named("CreateCommentHandler")
+	logger := tracing.Logger(c.Request().Context(), cm.Logger.Named("CreateCommentHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -59,20 +65,16 @@ func (cm *Comments) CreateCommentHandler(c echo.Context) error {
 	err := c.Bind(&body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, CreateCommentHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("body", body)
 
 	// validate body data
 	logger.Infow("validating request body")
-	err = cm.Validator.Struct(&body)
+	err = cm.Validator.StructCtx(c.Request().Context(), &body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, CreateCommentHandlerResponseBody{
-			Message: "failed to validate body",
-		})
+		return problem.Validation("failed to validate body", err)
 	}
 
 	// parse uuids id
@@ -80,9 +82,7 @@ func (cm *Comments) CreateCommentHandler(c echo.Context) error {
 	postUuid, err := uuid.Parse(body.PostID)
 	if err != nil {
 		logger.Errorw("failed to parse uuids from body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, CreateCommentHandlerResponseBody{
-			Message: "failed to parse uuids from body",
-		})
+		return problem.BadRequest("failed to parse uuids from body")
 	}
 	logger = logger.With("postUuid", postUuid)
 
@@ -94,15 +94,10 @@ func (cm *Comments) CreateCommentHandler(c echo.Context) error {
 		Name:   body.Name,
 		Body:   body.Body,
 	}
-	err = cm.MySQL.
-		Model(&models.Comment{}).
-		Create(&comment).
-		Error
+	err = cm.Comments.Create(c.Request().Context(), &comment)
 	if err != nil {
 		logger.Errorw("failed to save comment in database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, CreateCommentHandlerResponseBody{
-			Message: "failed to save comment",
-		})
+		return problem.Internal("failed to save comment")
 	}
 
 	// assemble response body
@@ -4,10 +4,12 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
-	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // Represent output data of DeleteCommentHandler
@@ -25,17 +27,19 @@ type DeleteCommentHandlerResponseBody struct {
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Success 204 	{object} DeleteCommentHandlerResponseBody
-// @Failure 400,404 {object} DeleteCommentHandlerResponseBody
-// @Failure 500 	{object} DeleteCommentHandlerResponseBody
-// @Failure default {object} DeleteCommentHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /comments/{id} [DELETE]
 func (cm *Comments) DeleteCommentHandler(c echo.Context) error {
-	logger := cm.Logger.Named("DeleteCommentHandler")
+	logger := tracing.Logger(c.Request().Context(), cm.Logger.Named("DeleteCommentHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -51,51 +55,37 @@ func (cm *Comments) DeleteCommentHandler(c echo.Context) error {
 	commentId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, DeleteCommentHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("commentId", commentId)
 
 	// getting comment from database
-	var comment models.Comment
 	logger.Infow("getting comment from database")
-	err = cm.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: commentId}}).
-		First(&comment).
-		Error
+	comment, err := cm.Comments.Get(c.Request().Context(), commentId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment record in database with provided id", "err", err)
-			return cm.ResponseWriter(c, http.StatusNotFound, DeleteCommentHandlerResponseBody{
-				Message: "failed to find record with provided id",
-			})
+			return problem.NotFound("failed to find record with provided id")
 		}
 		logger.Errorw("failed to find comment record in database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, DeleteCommentHandlerResponseBody{
-			Message: "failed to update comment",
-		})
+		return problem.Internal("failed to update comment")
 	}
 	logger = logger.With("comment", comment)
 
-	// check if user is comment author
-	logger.Infow("checking if user is author a comment")
-	if token.UserID != comment.UserID {
-		logger.Errorw("user is not author of current comment", "err", err)
-		return cm.ResponseWriter(c, http.StatusForbidden, DeleteCommentHandlerResponseBody{
-			Message: "only author can change comment content",
-		})
+	// check if user is allowed to delete comment
+	logger.Infow("checking if user is allowed to delete comment")
+	err = policy.Authorize(token, policy.ResourceComment, policy.ActionDelete, comment.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to delete current comment", "err", err)
+		return problem.Forbidden("only author or moderator can delete comment")
 	}
 
 	// delete comment from database
 	logger.Infow("deleting comment from database")
-	err = cm.MySQL.Delete(&comment).Error
+	err = cm.Comments.Delete(c.Request().Context(), comment)
 	if err != nil {
 		logger.Errorw("failed to delete comment with provided id from database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, DeleteCommentHandlerResponseBody{
-			Message: "failed to delete comment",
-		})
+		return problem.Internal("failed to delete comment")
 	}
 
 	// assemble response body
@@ -4,9 +4,11 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // Represent output data of GetCommentHandler
@@ -25,15 +27,17 @@ type GetCommentHandlerResponseBody struct {
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Success 200 	{object} GetCommentHandlerResponseBody
-// @Failure 400,404 {object} GetCommentHandlerResponseBody
-// @Failure 500 	{object} GetCommentHandlerResponseBody
-// @Failure default {object} GetCommentHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Router /comments/{id} [GET]
 func (cm *Comments) GetCommentHandler(c echo.Context) error {
-	logger := cm.Logger.Named("GetCommentHandler")
+	logger := tracing.Logger(c.Request().Context(), cm.Logger.Named("GetCommentHandler"))
 
 	// get id from path param
 	logger.Infow("getting id from path params")
@@ -45,39 +49,28 @@ func (cm *Comments) GetCommentHandler(c echo.Context) error {
 	commentId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, GetCommentHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("commentId", commentId)
 
 	// retreive comment from database
 	logger.Infow("getting comment from database")
-	var comment models.Comment
-	err = cm.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: commentId}}).
-		First(&comment).
-		Error
+	comment, err := cm.Comments.Get(c.Request().Context(), commentId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment with provided id in database", "err", err)
-			return cm.ResponseWriter(c, http.StatusNotFound, GetCommentHandlerResponseBody{
-				Message: "failed to find comment with provided id",
-			})
+			return problem.NotFound("failed to find comment with provided id")
 		}
 
 		logger.Errorw("failed to get comment from database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, GetCommentHandlerResponseBody{
-			Message: "failed to get comment",
-		})
+		return problem.Internal("failed to get comment")
 	}
 	logger = logger.With("comment", comment)
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetCommentHandlerResponseBody{
-		Comment: &comment,
+		Comment: comment,
 		Message: "successfully retrieved comment",
 	}
 	logger = logger.With("res", res)
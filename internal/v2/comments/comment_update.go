@@ -5,15 +5,18 @@ import (
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // Represent input data of UpdateCommentHandler
 type UpdateCommentHandlerRequestBody struct {
 	Name string `json:"name" form:"name" binding:"required" validate:"required"`
-	Body string `json:"body" form:"body" binding:"required" validate:"required"`
+	Body string `json:"body" form:"body" binding:"required" validate:"required,nohtml"`
 } // @name UpdateCommentRequest
 
 // Represent output data of UpdateCommentHandler
@@ -31,22 +34,26 @@ type UpdateCommentHandlerResponseBody struct {
 // @Tags			Comments
 //
 // @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Param fields body UpdateCommentHandlerRequestBody true "data"
 //
 // @Success 200 	{object} UpdateCommentHandlerResponseBody
-// @Failure 400,404 {object} UpdateCommentHandlerResponseBody
-// @Failure 500 	{object} UpdateCommentHandlerResponseBody
-// @Failure default {object} UpdateCommentHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /comments/{id} [PUT]
 func (cm *Comments) UpdateCommentHandler(c echo.Context) error {
-	logger := cm.Logger.Named("UpdateCommentHandler")
+	logger := tracing.Logger(c.Request().Context(), cm.Logger.Named("UpdateCommentHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -62,9 +69,7 @@ func (cm *Comments) UpdateCommentHandler(c echo.Context) error {
 	commentId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, UpdateCommentHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("commentId", commentId)
 
@@ -74,9 +79,7 @@ func (cm *Comments) UpdateCommentHandler(c echo.Context) error {
 	err = c.Bind(&body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, UpdateCommentHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("body", body)
 
@@ -85,59 +88,44 @@ func (cm *Comments) UpdateCommentHandler(c echo.Context) error {
 	err = cm.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, UpdateCommentHandlerResponseBody{
-			Message: "failed to validate body",
-		})
+		return problem.Validation("failed to validate body", err)
 	}
 
 	// getting comment from database
-	var comment models.Comment
 	logger.Infow("getting comment from database")
-	err = cm.MySQL.
-		Model(&models.Comment{}).
-		Where(&models.Comment{Base: models.Base{ID: commentId}}).
-		First(&comment).
-		Error
+	comment, err := cm.Comments.Get(c.Request().Context(), commentId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find comment record in database with provided id", "err", err)
-			return cm.ResponseWriter(c, http.StatusNotFound, UpdateCommentHandlerResponseBody{
-				Message: "failed to find record with provided id",
-			})
+			return problem.NotFound("failed to find record with provided id")
 		}
 		logger.Errorw("failed to find comment record in database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, UpdateCommentHandlerResponseBody{
-			Message: "failed to update comment",
-		})
+		return problem.Internal("failed to update comment")
 	}
 	logger = logger.With("comment", comment)
 
-	// check if user is comment author
-	logger.Infow("checking if user is author a comment")
-	if token.UserID != comment.UserID {
-		logger.Errorw("user is not author of current comment", "err", err)
-		return cm.ResponseWriter(c, http.StatusForbidden, UpdateCommentHandlerResponseBody{
-			Message: "only author can change comment content",
-		})
+	// check if user is allowed to update comment
+	logger.Infow("checking if user is allowed to update comment")
+	err = policy.Authorize(token, policy.ResourceComment, policy.ActionUpdate, comment.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to update current comment", "err", err)
+		return problem.Forbidden("only author or moderator can change comment content")
 	}
 
 	// update comment in database
 	logger.Infow("updating comment in database")
-	err = cm.MySQL.
-		Model(&comment).
-		Updates(&models.Comment{Name: body.Name, Body: body.Body}).
-		Error
+	comment.Name = body.Name
+	comment.Body = body.Body
+	err = cm.Comments.Update(c.Request().Context(), comment)
 	if err != nil {
 		logger.Errorw("failed to update comment in database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, UpdateCommentHandlerResponseBody{
-			Message: "failed to update comment",
-		})
+		return problem.Internal("failed to update comment")
 	}
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := UpdateCommentHandlerResponseBody{
-		Comment: &comment,
+		Comment: comment,
 		Message: "successfully updated comment",
 	}
 	logger = logger.With("res", res)
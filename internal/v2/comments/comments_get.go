@@ -4,124 +4,139 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm/clause"
 )
 
 // Represent intput data of GetCommentsHandler
 type GetCommentsHandlerRequestQuery struct {
-	Limit  int    `query:"limit"`
-	Offset int    `query:"offset"`
-	UserID string `query:"userId"`
-	PostID string `query:"postId"`
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+	Cursor    string `query:"cursor"`
+	SkipTotal bool   `query:"skipTotal"`
+	UserID    string `query:"userId"`
+	PostID    string `query:"postId"`
 } // @name GetCommentsRequest
 
 // Represent output data of GetCommentsHandler
 type GetCommentsHandlerResponseBody struct {
-	Comments *[]models.Comment `json:"comments" xml:"comments"`
-	Total    int64             `json:"total" xml:"total"`
-	Message  string            `json:"message" xml:"message"`
+	Comments   *[]models.Comment `json:"comments" xml:"comments"`
+	Total      int64             `json:"total" xml:"total"`
+	NextCursor string            `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string            `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Message    string            `json:"message" xml:"message"`
 } // @name GetCommentsResponse
 
 // GetCommentsHandler godoc
 //
 // @id				GetComments
 // @Summary 		Gets comment records.
-// @Description 	Gets comment records from database using provided query.
+// @Description 	Gets comment records from database using provided query, supports offset and cursor pagination.
 //
 // @Tags			Comments
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
 //
 // @Param fields query GetCommentsHandlerRequestQuery true "data"
 //
 // @Success 200 	{object} GetCommentsHandlerResponseBody
-// @Failure 400,404 {object} GetCommentsHandlerResponseBody
-// @Failure 500 	{object} GetCommentsHandlerResponseBody
-// @Failure default {object} GetCommentsHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Router /comments [GET]
 func (cm *Comments) GetCommentsHandler(c echo.Context) error {
-	logger := cm.Logger.Named("GetCommentsHandler")
+	logger := tracing.Logger(c.Request().Context(), cm.Logger.Named("GetCommentsHandler"))
 
 	logger.Infow("parsing request query params")
 	var query GetCommentsHandlerRequestQuery
 	err := c.Bind(&query)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return cm.ResponseWriter(c, http.StatusBadRequest, GetCommentsHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("query", query)
 
-	stmt := cm.MySQL.Model(&models.Comment{})
+	// parse cursor
+	logger.Infow("parsing cursor")
+	cursor, err := pagination.Decode(query.Cursor)
+	if err != nil {
+		logger.Errorw("failed to parse cursor", "err", err)
+		return problem.BadRequest("failed to parse cursor")
+	}
+
+	var filter repository.CommentFilter
 
-	// append post id to where clause
+	// append post id to filter
 	if query.PostID != "" {
 		logger.Infow("parsing uuid form query")
 		postUuid, err := uuid.Parse(query.PostID)
 		if err != nil {
 			logger.Errorw("failed to parse uuids from body", "err", err)
-			return cm.ResponseWriter(c, http.StatusBadRequest, GetCommentsHandlerResponseBody{
-				Message: "failed to parse uuids from body",
-			})
+			return problem.BadRequest("failed to parse uuids from body")
 		}
 		logger = logger.With("postUuid", postUuid)
 
-		// add clause to statement
-		stmt.Where(&models.Comment{PostID: postUuid})
+		// add condition to filter
+		filter.PostID = postUuid
 	}
 
-	// append user id to where clause
+	// append user id to filter
 	if query.UserID != "" {
 		logger.Infow("parsing uuid form query")
 		userUuid, err := uuid.Parse(query.UserID)
 		if err != nil {
 			logger.Errorw("failed to parse uuids from body", "err", err)
-			return cm.ResponseWriter(c, http.StatusBadRequest, GetCommentsHandlerResponseBody{
-				Message: "failed to parse uuids from body",
-			})
+			return problem.BadRequest("failed to parse uuids from body")
 		}
 		logger = logger.With("userUuid", userUuid)
 
-		// add clause to statement
-		stmt.Where(&models.Comment{UserID: userUuid})
+		// add condition to filter
+		filter.UserID = userUuid
 	}
 
-	// set default limit to 10
-	limit := 10
-	if query.Limit != 0 {
-		limit = query.Limit
-	}
+	// apply default limit and cap it
+	limit := pagination.Limit(query.Limit)
 
-	// retreive comments from database
+	// retreive comments from database, counting them unless client opted out
 	logger.Infow("getting comments from database")
-	var total int64
-	var comments []models.Comment
-	err = stmt.
-		Count(&total).
-		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}).
-		Limit(limit).
-		Offset(query.Offset).
-		Find(&comments).
-		Error
+	comments, list, err := cm.Comments.List(c.Request().Context(), filter, repository.ListOptions{
+		Cursor:    cursor,
+		Limit:     limit,
+		Offset:    query.Offset,
+		SkipTotal: query.SkipTotal,
+	})
 	if err != nil {
 		logger.Errorw("failed to get comments from database", "err", err)
-		return cm.ResponseWriter(c, http.StatusInternalServerError, GetCommentsHandlerResponseBody{
-			Message: "failed to get comments",
-		})
+		return problem.Internal("failed to get comments")
+	}
+
+	// build page cursors
+	var page pagination.Page
+	if len(comments) > 0 {
+		page = pagination.NewPage(cursor, query.Offset, list.More,
+			pagination.Key{CreatedAt: comments[0].CreatedAt, ID: comments[0].ID},
+			pagination.Key{CreatedAt: comments[len(comments)-1].CreatedAt, ID: comments[len(comments)-1].ID},
+		)
 	}
 	logger = logger.With("comments", comments)
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetCommentsHandlerResponseBody{
-		Comments: &comments,
-		Total:    total,
-		Message:  "successfully retrieved comments",
+		Comments:   &comments,
+		Total:      list.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Message:    "successfully retrieved comments",
 	}
 	logger = logger.With("res", res)
 
@@ -3,7 +3,7 @@ package tests
 import (
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/comments"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
@@ -14,8 +14,7 @@ import (
 
 func TestCreateCommentHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -75,6 +74,21 @@ func TestCreateCommentHandler(t *testing.T) {
 		require.Error(t, err, "parsed invalid uuid")
 	})
 
+	t.Run("should error if post does not exist", func(t *testing.T) {
+		var res comments.CreateCommentHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/comments",
+			Body: &comments.CreateCommentHandlerRequestBody{
+				PostID: uuid.New().String(),
+				Name:   "test comment name",
+				Body:   "test comment body",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created orphan comment")
+	})
+
 	t.Run("post id field should be required", func(t *testing.T) {
 		var res comments.CreateCommentHandlerResponseBody
 		err := testClient.Request(&testclient.RequestOptions{
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
@@ -15,8 +15,7 @@ import (
 
 func TestDeleteCommentHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -40,6 +39,15 @@ func TestDeleteCommentHandler(t *testing.T) {
 		}, a.Config.HMACSecret),
 	})
 
+	moderatorClient := testclient.TestClient{}
+	moderatorClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   uuid.New(),
+			UserRole: models.UserRoleModerator,
+		}, a.Config.HMACSecret),
+	})
+
 	defer func() {
 		// cleanup test data
 		err := fixture.Teardown()
@@ -54,6 +62,14 @@ func TestDeleteCommentHandler(t *testing.T) {
 		require.Error(t, err, "random user can delete comment")
 	})
 
+	t.Run("moderator can delete any comment", func(t *testing.T) {
+		err := moderatorClient.Request(&testclient.RequestOptions{
+			Method: "DELETE",
+			URL:    fmt.Sprintf("/api/v2/comments/%s", testData.TestUserTwoCommentOneID),
+		})
+		require.NoError(t, err, "moderator failed to delete comment")
+	})
+
 	t.Run("should error if passing invalid comment id", func(t *testing.T) {
 		err := authorClient.Request(&testclient.RequestOptions{
 			Method: "DELETE",
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/comments"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
@@ -15,8 +15,7 @@ import (
 
 func TestUpdateCommentHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -40,6 +39,15 @@ func TestUpdateCommentHandler(t *testing.T) {
 		}, a.Config.HMACSecret),
 	})
 
+	moderatorClient := testclient.TestClient{}
+	moderatorClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   uuid.New(),
+			UserRole: models.UserRoleModerator,
+		}, a.Config.HMACSecret),
+	})
+
 	defer func() {
 		// cleanup test data
 		err := fixture.Teardown()
@@ -62,6 +70,21 @@ func TestUpdateCommentHandler(t *testing.T) {
 		require.Error(t, err, "random user could update comment")
 	})
 
+	t.Run("moderator can update any comment", func(t *testing.T) {
+		var res comments.UpdateCommentHandlerResponseBody
+		err := moderatorClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/comments/%s", testData.TestUserTwoCommentOneID),
+			Body: &comments.UpdateCommentHandlerRequestBody{
+				Name: updatedCommentName,
+				Body: updatedCommentBody,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "moderator failed to update comment")
+		require.Equal(t, updatedCommentName, res.Comment.Name, "unexpected name value")
+	})
+
 	t.Run("should error if passing invalid post uuid", func(t *testing.T) {
 		var res comments.UpdateCommentHandlerResponseBody
 		err := authorClient.Request(&testclient.RequestOptions{
@@ -3,9 +3,10 @@ package tests
 import (
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/comments"
 	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -13,8 +14,7 @@ import (
 
 func TestGetCommentsHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := CommentsTestFixtures()
@@ -150,4 +150,93 @@ func TestGetCommentsHandler(t *testing.T) {
 			require.NotEqual(t, prevCommentId, c.ID, "got same comment with different offset")
 		}
 	})
+
+	t.Run("cursor pagination should work", func(t *testing.T) {
+		var first comments.GetCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Limit: 2,
+			},
+			Response: &first,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *first.Comments, 2, "invalid response length")
+		require.NotEmpty(t, first.NextCursor, "next cursor was empty")
+		require.Empty(t, first.PrevCursor, "prev cursor on first page")
+
+		var second comments.GetCommentsHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Limit:  2,
+				Cursor: first.NextCursor,
+			},
+			Response: &second,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.NotEmpty(t, second.PrevCursor, "prev cursor was empty")
+		for _, n := range *second.Comments {
+			for _, p := range *first.Comments {
+				require.NotEqual(t, p.ID, n.ID, "got same record on next page")
+			}
+		}
+
+		var back comments.GetCommentsHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Limit:  2,
+				Cursor: second.PrevCursor,
+			},
+			Response: &back,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, *first.Comments, *back.Comments, "prev page differs from first page")
+	})
+
+	t.Run("should error if passing malformed cursor", func(t *testing.T) {
+		var res comments.GetCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Cursor: "malformed cursor",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "parsed malformed cursor")
+	})
+
+	t.Run("should skip total", func(t *testing.T) {
+		var res comments.GetCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Limit:     1,
+				SkipTotal: true,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Zero(t, res.Total, "total was counted")
+	})
+
+	t.Run("limit should be capped", func(t *testing.T) {
+		var res comments.GetCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/comments",
+			Query: &comments.GetCommentsHandlerRequestQuery{
+				Limit: pagination.MaxLimit + 1,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.LessOrEqual(t, len(*res.Comments), pagination.MaxLimit, "limit was not capped")
+	})
 }
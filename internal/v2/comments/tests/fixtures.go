@@ -1,7 +1,7 @@
 package tests
 
 import (
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/google/uuid"
 )
@@ -31,8 +31,7 @@ type TestFixturesData struct {
 // CommentsTestFixtures return instance of fixture.
 func CommentsTestFixtures() Fixture {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// test users
 	var testUsers []models.User
@@ -0,0 +1,129 @@
+package posts
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input data of CreatePostCommentHandler
+type CreatePostCommentHandlerRequestBody struct {
+	Name string `json:"name" form:"name" binding:"required" validate:"required"`
+	Body string `json:"body" form:"body" binding:"required" validate:"required,nohtml"`
+} // @name CreatePostCommentRequest
+
+// Represent output data of CreatePostCommentHandler
+type CreatePostCommentHandlerResponseBody struct {
+	Comment *models.Comment `json:"comment" xml:"comment"`
+	Message string          `json:"message" xml:"message"`
+} // @name CreatePostCommentResponse
+
+// CreatePostCommentHandler godoc
+//
+// @id				CreatePostComment
+// @Summary 		Creates comment record in post.
+// @Description 	Creates comment record that belongs to post with provided id using provided data.
+//
+// @Tags			Posts
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body CreatePostCommentHandlerRequestBody true "data"
+//
+// @Success 201 	{object} CreatePostCommentHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /posts/{id}/comments [POST]
+func (p *Posts) CreatePostCommentHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("CreatePostCommentHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid
+	logger.Infow("parsing uuid from path")
+	postId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+	logger = logger.With("postId", postId)
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body CreatePostCommentHandlerRequestBody
+	err = c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+	logger = logger.With("body", body)
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = p.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// check if post exists
+	logger.Infow("getting post from database")
+	post, err := p.Posts.Get(c.Request().Context(), postId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find post with provided id in database", "err", err)
+			return problem.NotFound("failed to find post with provided id")
+		}
+		logger.Errorw("failed to get post from database", "err", err)
+		return problem.Internal("failed to save comment")
+	}
+
+	// save instance of comment in database
+	logger.Infow("saving comment to database")
+	comment := models.Comment{
+		UserID: token.UserID,
+		PostID: post.ID,
+		Name:   body.Name,
+		Body:   body.Body,
+	}
+	err = p.Comments.Create(c.Request().Context(), &comment)
+	if err != nil {
+		logger.Errorw("failed to save comment in database", "err", err)
+		return problem.Internal("failed to save comment")
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := CreatePostCommentHandlerResponseBody{
+		Comment: &comment,
+		Message: "successfully created comment",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully created post comment record in database")
+	return p.ResponseWriter(c, http.StatusCreated, res)
+}
@@ -0,0 +1,85 @@
+package posts
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of CountPostCommentsHandler
+type CountPostCommentsHandlerResponseBody struct {
+	Count   int64  `json:"count" xml:"count"`
+	Message string `json:"message" xml:"message"`
+} // @name CountPostCommentsResponse
+
+// CountPostCommentsHandler godoc
+//
+// @id				CountPostComments
+// @Summary 		Counts comment records of post.
+// @Description 	Counts comment records that belong to post with provided id.
+//
+// @Tags			Posts
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Success 200 	{object} CountPostCommentsHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /posts/{id}/comments/count [GET]
+func (p *Posts) CountPostCommentsHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("CountPostCommentsHandler"))
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid
+	logger.Infow("parsing uuid from path")
+	postId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+	logger = logger.With("postId", postId)
+
+	// check if post exists
+	logger.Infow("getting post from database")
+	post, err := p.Posts.Get(c.Request().Context(), postId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find post with provided id in database", "err", err)
+			return problem.NotFound("failed to find post with provided id")
+		}
+		logger.Errorw("failed to get post from database", "err", err)
+		return problem.Internal("failed to count comments")
+	}
+
+	// count comments in database
+	logger.Infow("counting comments in database")
+	count, err := p.Comments.Count(c.Request().Context(), repository.CommentFilter{PostID: post.ID})
+	if err != nil {
+		logger.Errorw("failed to count comments in database", "err", err)
+		return problem.Internal("failed to count comments")
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := CountPostCommentsHandlerResponseBody{
+		Count:   count,
+		Message: "successfully counted comments",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully counted post comments in database")
+	return p.ResponseWriter(c, http.StatusOK, res)
+}
@@ -0,0 +1,139 @@
+package posts
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input query of GetPostCommentsHandler
+type GetPostCommentsHandlerRequestQuery struct {
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+	Cursor    string `query:"cursor"`
+	SkipTotal bool   `query:"skipTotal"`
+} // @name GetPostCommentsRequest
+
+// Represent output data of GetPostCommentsHandler
+type GetPostCommentsHandlerResponseBody struct {
+	Comments   *[]models.Comment `json:"comments" xml:"comments"`
+	Total      int64             `json:"total" xml:"total"`
+	NextCursor string            `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string            `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Message    string            `json:"message" xml:"message"`
+} // @name GetPostCommentsResponse
+
+// GetPostCommentsHandler godoc
+//
+// @id				GetPostComments
+// @Summary 		Gets comment records of post.
+// @Description 	Gets comment records that belong to post with provided id using provided query, supports offset and cursor pagination.
+//
+// @Tags			Posts
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
+//
+// @Param fields query GetPostCommentsHandlerRequestQuery true "data"
+//
+// @Success 200 	{object} GetPostCommentsHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /posts/{id}/comments [GET]
+func (p *Posts) GetPostCommentsHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("GetPostCommentsHandler"))
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid
+	logger.Infow("parsing uuid from path")
+	postId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+	logger = logger.With("postId", postId)
+
+	logger.Infow("parsing request query params")
+	var query GetPostCommentsHandlerRequestQuery
+	err = c.Bind(&query)
+	if err != nil {
+		logger.Errorw("failed to parse request query", "err", err)
+		return problem.BadRequest("failed to parse request query")
+	}
+	logger = logger.With("query", query)
+
+	// parse cursor
+	logger.Infow("parsing cursor")
+	cursor, err := pagination.Decode(query.Cursor)
+	if err != nil {
+		logger.Errorw("failed to parse cursor", "err", err)
+		return problem.BadRequest("failed to parse cursor")
+	}
+
+	// check if post exists
+	logger.Infow("getting post from database")
+	post, err := p.Posts.Get(c.Request().Context(), postId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find post with provided id in database", "err", err)
+			return problem.NotFound("failed to find post with provided id")
+		}
+		logger.Errorw("failed to get post from database", "err", err)
+		return problem.Internal("failed to get comments")
+	}
+
+	// apply default limit and cap it
+	limit := pagination.Limit(query.Limit)
+
+	// retreive comments of post from database, counting them unless client opted out
+	logger.Infow("getting comments from database")
+	comments, list, err := p.Comments.List(c.Request().Context(), repository.CommentFilter{PostID: post.ID}, repository.ListOptions{
+		Cursor:    cursor,
+		Limit:     limit,
+		Offset:    query.Offset,
+		SkipTotal: query.SkipTotal,
+	})
+	if err != nil {
+		logger.Errorw("failed to get comments from database", "err", err)
+		return problem.Internal("failed to get comments")
+	}
+
+	// build page cursors
+	var page pagination.Page
+	if len(comments) > 0 {
+		page = pagination.NewPage(cursor, query.Offset, list.More,
+			pagination.Key{CreatedAt: comments[0].CreatedAt, ID: comments[0].ID},
+			pagination.Key{CreatedAt: comments[len(comments)-1].CreatedAt, ID: comments[len(comments)-1].ID},
+		)
+	}
+	logger = logger.With("comments", comments)
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := GetPostCommentsHandlerResponseBody{
+		Comments:   &comments,
+		Total:      list.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Message:    "successfully retrieved comments",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully retrieved post comments from database")
+	return p.ResponseWriter(c, http.StatusOK, res)
+}
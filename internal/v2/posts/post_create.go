@@ -5,13 +5,15 @@ import (
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/labstack/echo/v4"
 )
 
 // Represent input data of CreatePostHandler
 type CreatePostHandlerRequestBody struct {
-	Title string `json:"title" form:"title" binding:"required" validate:"required"`
-	Body  string `json:"body" form:"body" binding:"required" validate:"required"`
+	Title string `json:"title" form:"title" binding:"required" validate:"required,title"`
+	Body  string `json:"body" form:"body" binding:"required" validate:"required,nohtml"`
 } // @name CreatePostRequest
 
 // Represent output data of CreatePostHandler
@@ -29,22 +31,26 @@ type CreatePostHandlerResponseBody struct {
 // @Tags			Posts
 //
 // @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Param fields body CreatePostHandlerRequestBody true "data"
 //
 // @Success 201 	{object} CreatePostHandlerResponseBody
-// @Failure 400,404 {object} CreatePostHandlerResponseBody
-// @Failure 500 	{object} CreatePostHandlerResponseBody
-// @Failure default {object} CreatePostHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /posts [POST]
 func (p *Posts) CreatePostHandler(c echo.Context) error {
-	logger := p.Logger.Named("CreatePostHandler")
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("CreatePostHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -56,9 +62,7 @@ func (p *Posts) CreatePostHandler(c echo.Context) error {
 	err := c.Bind(&body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, CreatePostHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("body", body)
 
@@ -67,9 +71,7 @@ func (p *Posts) CreatePostHandler(c echo.Context) error {
 	err = p.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, CreatePostHandlerResponseBody{
-			Message: "failed to validate body",
-		})
+		return problem.Validation("failed to validate body", err)
 	}
 
 	// save instance of post in database
@@ -79,12 +81,10 @@ func (p *Posts) CreatePostHandler(c echo.Context) error {
 		Title:  body.Title,
 		Body:   body.Body,
 	}
-	err = p.MySQL.Model(&models.Post{}).Create(&post).Error
+	err = p.Posts.Create(c.Request().Context(), &post)
 	if err != nil {
 		logger.Errorw("failed to save post in database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, CreatePostHandlerResponseBody{
-			Message: "failed to create post",
-		})
+		return problem.Internal("failed to create post")
 	}
 
 	// assemble response body
@@ -4,11 +4,12 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
-	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // Represent output data of DeletePostHandler
@@ -26,17 +27,19 @@ type DeletePostHandlerResponseBody struct {
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Success 204 	{object} DeletePostHandlerResponseBody
-// @Failure 400,404 {object} DeletePostHandlerResponseBody
-// @Failure 500 	{object} DeletePostHandlerResponseBody
-// @Failure default {object} DeletePostHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /posts/{id} [DELETE]
 func (p *Posts) DeletePostHandler(c echo.Context) error {
-	logger := p.Logger.Named("DeletePostsHandler")
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("DeletePostsHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -52,54 +55,37 @@ func (p *Posts) DeletePostHandler(c echo.Context) error {
 	postId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, DeletePostHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("postId", postId)
 
 	// get post from database
-	var post models.Post
 	logger.Infow("getting post from database")
-	err = p.MySQL.
-		Model(&models.Post{}).
-		Where(&models.Post{Base: models.Base{ID: postId}}).
-		First(&post).
-		Error
+	post, err := p.Posts.Get(c.Request().Context(), postId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post record in database with provided id", "err", err)
-			return p.ResponseWriter(c, http.StatusNotFound, DeletePostHandlerResponseBody{
-				Message: "failed to find record with provided id",
-			})
+			return problem.NotFound("failed to find record with provided id")
 		}
 		logger.Errorw("failed to find post record in database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, DeletePostHandlerResponseBody{
-			Message: "failed to delete post",
-		})
+		return problem.Internal("failed to delete post")
 	}
 	logger = logger.With("post", post)
 
-	// check if user is post author
-	logger.Infow("checking if user is author a post")
-	if token.UserID != post.UserID {
-		logger.Errorw("user is not author of current post", "err", err)
-		return p.ResponseWriter(c, http.StatusForbidden, DeletePostHandlerResponseBody{
-			Message: "only author can delete post",
-		})
+	// check if user is allowed to delete post
+	logger.Infow("checking if user is allowed to delete post")
+	err = policy.Authorize(token, policy.ResourcePost, policy.ActionDelete, post.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to delete current post", "err", err)
+		return problem.Forbidden("only author or admin can delete post")
 	}
 
 	// delete post from database
 	logger.Infow("deleting post from database")
-	err = p.MySQL.
-		Select(clause.Associations).
-		Delete(&post).
-		Error
+	err = p.Posts.Delete(c.Request().Context(), post)
 	if err != nil {
 		logger.Errorw("failed to delete post record from database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, DeletePostHandlerResponseBody{
-			Message: "failed to delete post",
-		})
+		return problem.Internal("failed to delete post")
 	}
 
 	// assemble response body
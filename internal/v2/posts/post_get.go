@@ -4,9 +4,11 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // Represent output data of GetPostHandler
@@ -25,15 +27,17 @@ type GetPostHandlerResponseBody struct {
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Success 200 	{object} GetPostHandlerResponseBody
-// @Failure 400,404 {object} GetPostHandlerResponseBody
-// @Failure 500 	{object} GetPostHandlerResponseBody
-// @Failure default {object} GetPostHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Router /posts/{id} [GET]
 func (p *Posts) GetPostHandler(c echo.Context) error {
-	logger := p.Logger.Named("GetPostHandler")
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("GetPostHandler"))
 
 	// get id from path param
 	logger.Infow("getting id from path params")
@@ -45,38 +49,28 @@ func (p *Posts) GetPostHandler(c echo.Context) error {
 	postId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, GetPostHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("postId", postId)
 
 	// retreive post from database
 	logger.Infow("getting post from database")
-	var post models.Post
-	err = p.MySQL.Model(&models.Post{}).
-		Where(&models.Post{Base: models.Base{ID: postId}}).
-		First(&post).
-		Error
+	post, err := p.Posts.Get(c.Request().Context(), postId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post with provided id in database", "err", err)
-			return p.ResponseWriter(c, http.StatusNotFound, GetPostHandlerResponseBody{
-				Message: "failed to find post with provided id",
-			})
+			return problem.NotFound("failed to find post with provided id")
 		}
 
 		logger.Errorw("failed to get posts from database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, GetPostHandlerResponseBody{
-			Message: "failed to get posts by provided id",
-		})
+		return problem.Internal("failed to get posts by provided id")
 	}
 	logger = logger.With("post", post)
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetPostHandlerResponseBody{
-		Post:    &post,
+		Post:    post,
 		Message: "successfully retrieved post",
 	}
 	logger = logger.With("res", res)
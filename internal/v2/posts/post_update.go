@@ -5,15 +5,18 @@ import (
 
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm"
 )
 
 // Represent input data of UpdatePostHandler
 type UpdatePostHandlerRequestBody struct {
-	Title string `json:"title" form:"title" binding:"required" validate:"required"`
-	Body  string `json:"body" form:"body" binding:"required" validate:"required"`
+	Title string `json:"title" form:"title" binding:"required" validate:"required,title"`
+	Body  string `json:"body" form:"body" binding:"required" validate:"required,nohtml"`
 } // @name UpdatePostRequest
 
 // Represent output data of UpdatePostHandler
@@ -31,22 +34,26 @@ type UpdatePostHandlerResponseBody struct {
 // @Tags			Posts
 //
 // @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
 //
 // @Param fields body UpdatePostHandlerRequestBody true "data"
 //
 // @Success 200 	{object} UpdatePostHandlerResponseBody
-// @Failure 400,404 {object} UpdatePostHandlerResponseBody
-// @Failure 500 	{object} UpdatePostHandlerResponseBody
-// @Failure default {object} UpdatePostHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Security ApiKeyAuth
 //
 // @Router /posts/{id} [PUT]
 func (p *Posts) UpdatePostHandler(c echo.Context) error {
-	logger := p.Logger.Named("UpdatePostHandler")
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("UpdatePostHandler"))
 
 	// get token from context
 	token := access.GetTokenFromContext(c)
@@ -62,9 +69,7 @@ func (p *Posts) UpdatePostHandler(c echo.Context) error {
 	postId, err := uuid.Parse(id)
 	if err != nil {
 		logger.Errorw("failed to parse uuid", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, UpdatePostHandlerResponseBody{
-			Message: "failed to parse uuid",
-		})
+		return problem.BadRequest("failed to parse uuid")
 	}
 	logger = logger.With("postId", postId)
 
@@ -74,9 +79,7 @@ func (p *Posts) UpdatePostHandler(c echo.Context) error {
 	err = c.Bind(&body)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, UpdatePostHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("body", body)
 
@@ -85,59 +88,44 @@ func (p *Posts) UpdatePostHandler(c echo.Context) error {
 	err = p.Validator.Struct(&body)
 	if err != nil {
 		logger.Errorw("failed to validate body", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, CreatePostHandlerResponseBody{
-			Message: "failed to validate body",
-		})
+		return problem.Validation("failed to validate body", err)
 	}
 
 	// get post from database
-	var post models.Post
 	logger.Infow("getting post from database")
-	err = p.MySQL.
-		Model(&models.Post{}).
-		Where(&models.Post{Base: models.Base{ID: postId}}).
-		First(&post).
-		Error
+	post, err := p.Posts.Get(c.Request().Context(), postId)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
+		if err == repository.ErrNotFound {
 			logger.Errorw("failed to find post record in database with provided id", "err", err)
-			return p.ResponseWriter(c, http.StatusNotFound, UpdatePostHandlerResponseBody{
-				Message: "failed to find record with provided id",
-			})
+			return problem.NotFound("failed to find record with provided id")
 		}
 		logger.Errorw("failed to find post record in database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, UpdatePostHandlerResponseBody{
-			Message: "failed to update post",
-		})
+		return problem.Internal("failed to update post")
 	}
 	logger = logger.With("post", post)
 
-	// check if user is post author
-	logger.Infow("checking if user is author a post")
-	if token.UserID != post.UserID {
-		logger.Errorw("user is not author of current post", "err", err)
-		return p.ResponseWriter(c, http.StatusForbidden, UpdatePostHandlerResponseBody{
-			Message: "only author can change post content",
-		})
+	// check if user is allowed to update post
+	logger.Infow("checking if user is allowed to update post")
+	err = policy.Authorize(token, policy.ResourcePost, policy.ActionUpdate, post.UserID)
+	if err != nil {
+		logger.Errorw("user is not allowed to update current post", "err", err)
+		return problem.Forbidden("only author or admin can change post content")
 	}
 
 	// update post in database
 	logger.Infow("updating post in database")
-	err = p.MySQL.
-		Model(&post).
-		Updates(&models.Post{Title: body.Title, Body: body.Body}).
-		Error
+	post.Title = body.Title
+	post.Body = body.Body
+	err = p.Posts.Update(c.Request().Context(), post)
 	if err != nil {
 		logger.Errorw("failed to update post in database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, UpdatePostHandlerResponseBody{
-			Message: "failed to update post",
-		})
+		return problem.Internal("failed to update post")
 	}
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := UpdatePostHandlerResponseBody{
-		Post:    &post,
+		Post:    post,
 		Message: "successfully updated post",
 	}
 	logger = logger.With("res", res)
@@ -4,87 +4,106 @@ import (
 	"net/http"
 
 	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/labstack/echo/v4"
-	"gorm.io/gorm/clause"
 )
 
 // Represent input query of GetPostHandler
 type GetPostsHandlerRequestQuery struct {
-	Limit  int `query:"limit"`
-	Offset int `query:"offset"`
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+	Cursor    string `query:"cursor"`
+	SkipTotal bool   `query:"skipTotal"`
 } // @name GetPostRequest
 
 // Represent output data of GetPostsHandler
 type GetPostsHandlerResponseBody struct {
-	Posts   *[]models.Post `json:"posts" xml:"posts"`
-	Total   int64          `json:"total" xml:"total"`
-	Message string         `json:"message" xml:"message"`
+	Posts      *[]models.Post `json:"posts" xml:"posts"`
+	Total      int64          `json:"total" xml:"total"`
+	NextCursor string         `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string         `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Message    string         `json:"message" xml:"message"`
 } // @name GetPostResponse
 
 // GetPostsHandler godoc
 //
 // @id				GetPosts
 // @Summary 		Gets post records.
-// @Description 	Gets post records from database using provided query.
+// @Description 	Gets post records from database using provided query, supports offset and cursor pagination.
 //
 // @Tags			Posts
 //
 // @Produce json
 // @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
 //
 // @Param fields query GetPostsHandlerRequestQuery true "data"
 //
 // @Success 200 	{object} GetPostsHandlerResponseBody
-// @Failure 400,404 {object} GetPostsHandlerResponseBody
-// @Failure 500 	{object} GetPostsHandlerResponseBody
-// @Failure default {object} GetPostsHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
 //
 // @Router /posts [GET]
 func (p *Posts) GetPostsHandler(c echo.Context) error {
-	logger := p.Logger.Named("GetPostsHandler")
+	logger := tracing.Logger(c.Request().Context(), p.Logger.Named("GetPostsHandler"))
 
 	logger.Infow("parsing request query params")
 	var query GetPostsHandlerRequestQuery
 	err := c.Bind(&query)
 	if err != nil {
 		logger.Errorw("failed to parse request body", "err", err)
-		return p.ResponseWriter(c, http.StatusBadRequest, GetPostsHandlerResponseBody{
-			Message: "failed to parse request body",
-		})
+		return problem.BadRequest("failed to parse request body")
 	}
 	logger = logger.With("query", query)
 
-	// set default limit to 10
-	limit := 10
-	if query.Limit != 0 {
-		limit = query.Limit
+	// parse cursor
+	logger.Infow("parsing cursor")
+	cursor, err := pagination.Decode(query.Cursor)
+	if err != nil {
+		logger.Errorw("failed to parse cursor", "err", err)
+		return problem.BadRequest("failed to parse cursor")
 	}
 
-	// retreive posts from database
+	// apply default limit and cap it
+	limit := pagination.Limit(query.Limit)
+
+	// retreive posts from database, counting them unless client opted out
 	logger.Infow("getting posts from database")
-	var total int64
-	var posts []models.Post
-	err = p.MySQL.Model(&models.Post{}).
-		Count(&total).
-		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}).
-		Limit(limit).
-		Offset(query.Offset).
-		Find(&posts).
-		Error
+	posts, list, err := p.Posts.List(c.Request().Context(), repository.ListOptions{
+		Cursor:    cursor,
+		Limit:     limit,
+		Offset:    query.Offset,
+		SkipTotal: query.SkipTotal,
+	})
 	if err != nil {
 		logger.Errorw("failed to get posts from database", "err", err)
-		return p.ResponseWriter(c, http.StatusInternalServerError, GetPostsHandlerResponseBody{
-			Message: "failed to get posts",
-		})
+		return problem.Internal("failed to get posts")
+	}
+
+	// build page cursors
+	var page pagination.Page
+	if len(posts) > 0 {
+		page = pagination.NewPage(cursor, query.Offset, list.More,
+			pagination.Key{CreatedAt: posts[0].CreatedAt, ID: posts[0].ID},
+			pagination.Key{CreatedAt: posts[len(posts)-1].CreatedAt, ID: posts[len(posts)-1].ID},
+		)
 	}
 	logger = logger.With("posts", posts)
 
 	// assemble response body
 	logger.Infow("assembling response body")
 	res := GetPostsHandlerResponseBody{
-		Posts:   &posts,
-		Total:   total,
-		Message: "successfully retrieved posts",
+		Posts:      &posts,
+		Total:      list.Total,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Message:    "successfully retrieved posts",
 	}
 	logger = logger.With("res", res)
 
@@ -1,7 +1,7 @@
 package tests
 
 import (
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/google/uuid"
 )
@@ -25,8 +25,7 @@ type TestFixturesData struct {
 // PostsTestFixtures return instance of fixture.
 func PostsTestFixtures() Fixture {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// test users
 	var testUsers []models.User
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePostCommentHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test clients
+	noTokenClient := testclient.TestClient{}
+	noTokenClient.Setup(&testclient.Options{Router: a.Echo})
+
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserTwoID,
+		}, a.Config.HMACSecret),
+	})
+
+	var newCommentID uuid.UUID
+	defer func() {
+		// cleanup created comment
+		err := a.MySQL.Unscoped().Delete(&models.Comment{Base: models.Base{ID: newCommentID}}).Error
+		require.NoError(t, err, "failed to clean up test comment")
+
+		// cleanup test data
+		err = fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("only authenticated user can create a comment", func(t *testing.T) {
+		var res posts.CreatePostCommentHandlerResponseBody
+		err := noTokenClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", testData.TestPostOneUserOneID),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+				Body: "test comment body",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created comment without token")
+	})
+
+	t.Run("should error if passing invalid uuid", func(t *testing.T) {
+		var res posts.CreatePostCommentHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", "invalid uuid"),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+				Body: "test comment body",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "parsed invalid uuid")
+	})
+
+	t.Run("should error if post does not exist", func(t *testing.T) {
+		var res posts.CreatePostCommentHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", uuid.New()),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+				Body: "test comment body",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created orphan comment")
+	})
+
+	t.Run("body field should be required", func(t *testing.T) {
+		var res posts.CreatePostCommentHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", testData.TestPostOneUserOneID),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "passed through required check")
+	})
+
+	t.Run("comment should be created", func(t *testing.T) {
+		var res posts.CreatePostCommentHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", testData.TestPostOneUserOneID),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+				Body: "test comment body",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to create comment")
+		require.NotEmpty(t, res.Comment.ID, "id field was empty")
+		require.Equal(t, testData.TestPostOneUserOneID, res.Comment.PostID, "unexpected post id")
+		require.Equal(t, testData.TestUserTwoID, res.Comment.UserID, "unexpected user id")
+		newCommentID = res.Comment.ID
+	})
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountPostCommentsHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// create test comments
+	testComments := []models.Comment{
+		{PostID: testData.TestPostOneUserOneID, UserID: testData.TestUserOneID, Name: "comment 1", Body: "comment 1"},
+		{PostID: testData.TestPostOneUserOneID, UserID: testData.TestUserTwoID, Name: "comment 2", Body: "comment 2"},
+		{PostID: testData.TestPostOneUserTwoID, UserID: testData.TestUserTwoID, Name: "comment 3", Body: "comment 3"},
+	}
+	err = a.MySQL.Create(&testComments).Error
+	require.NoError(t, err, "failed to create test comments")
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{Router: a.Echo})
+
+	defer func() {
+		// cleanup test comments
+		err := a.MySQL.Unscoped().Delete(&testComments).Error
+		require.NoError(t, err, "failed to clean up test comments")
+
+		// cleanup test data
+		err = fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("should error if post does not exist", func(t *testing.T) {
+		var res posts.CountPostCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s/comments/count", uuid.New()),
+			Response: &res,
+		})
+		require.Error(t, err, "counted comments of not existing post")
+	})
+
+	t.Run("should count comments of post", func(t *testing.T) {
+		var res posts.CountPostCommentsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s/comments/count", testData.TestPostOneUserOneID),
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, 2, int(res.Count), "unexpected comments count")
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
@@ -15,8 +15,7 @@ import (
 
 func TestDeletePostHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := PostsTestFixtures()
@@ -40,6 +39,24 @@ func TestDeletePostHandler(t *testing.T) {
 		}, a.Config.HMACSecret),
 	})
 
+	moderatorClient := testclient.TestClient{}
+	moderatorClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   uuid.New(),
+			UserRole: models.UserRoleModerator,
+		}, a.Config.HMACSecret),
+	})
+
+	adminClient := testclient.TestClient{}
+	adminClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   uuid.New(),
+			UserRole: models.UserRoleAdmin,
+		}, a.Config.HMACSecret),
+	})
+
 	defer func() {
 		// cleanup test data
 		err := fixture.Teardown()
@@ -54,6 +71,22 @@ func TestDeletePostHandler(t *testing.T) {
 		require.Error(t, err, "random user can delete post")
 	})
 
+	t.Run("moderator can not delete post", func(t *testing.T) {
+		err := moderatorClient.Request(&testclient.RequestOptions{
+			Method: "DELETE",
+			URL:    fmt.Sprintf("/api/v2/posts/%s", testData.TestPostOneUserTwoID),
+		})
+		require.Error(t, err, "moderator can delete post")
+	})
+
+	t.Run("admin can delete any post", func(t *testing.T) {
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "DELETE",
+			URL:    fmt.Sprintf("/api/v2/posts/%s", testData.TestPostOneUserTwoID),
+		})
+		require.NoError(t, err, "admin failed to delete post")
+	})
+
 	t.Run("should error if passing invalid post id", func(t *testing.T) {
 		err := authorClient.Request(&testclient.RequestOptions{
 			Method: "DELETE",
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPostContentNegotiation(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	token := access.MustEncodeToken(&access.Token{
+		UserID: testData.TestUserOneID,
+	}, a.Config.HMACSecret)
+
+	defer func() {
+		// cleanup posts created by test and test data
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.Post{UserID: testData.TestUserOneID}).
+			Delete(&models.Post{}).
+			Error
+		require.NoError(t, err, "failed to clean up created posts")
+
+		err = fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	// request is used to send request with accept and content type headers
+	request := func(router http.Handler, method string, url string, headers map[string]string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	postURL := fmt.Sprintf("/api/v2/posts/%s", testData.TestPostOneUserOneID)
+
+	t.Run("should respect quality of accepted types", func(t *testing.T) {
+		res := request(a.Echo, "GET", postURL, map[string]string{
+			"Accept": "text/html, application/xml;q=0.9, application/json;q=0.5",
+		}, nil)
+		require.Equal(t, http.StatusOK, res.Code, "unexpected status code")
+		require.True(t, strings.HasPrefix(res.Header().Get("Content-Type"), "application/xml"), "response is not xml")
+
+		var body posts.GetPostHandlerResponseBody
+		err := xml.Unmarshal(res.Body.Bytes(), &body)
+		require.NoError(t, err, "failed to decode xml response")
+		require.Equal(t, testData.TestPostOneUserOneID, body.Post.ID, "invalid post")
+	})
+
+	t.Run("should prefer specific type over wildcard", func(t *testing.T) {
+		res := request(a.Echo, "GET", postURL, map[string]string{
+			"Accept": "*/*;q=0.8, application/yaml",
+		}, nil)
+		require.Equal(t, http.StatusOK, res.Code, "unexpected status code")
+		require.True(t, strings.HasPrefix(res.Header().Get("Content-Type"), "application/yaml"), "response is not yaml")
+
+		var body map[string]interface{}
+		err := yaml.Unmarshal(res.Body.Bytes(), &body)
+		require.NoError(t, err, "failed to decode yaml response")
+		require.Equal(t, "successfully retrieved post", body["message"], "fields are not named as in json")
+	})
+
+	t.Run("should respond with msgpack", func(t *testing.T) {
+		res := request(a.Echo, "GET", postURL, map[string]string{
+			"Accept": "application/msgpack",
+		}, nil)
+		require.Equal(t, http.StatusOK, res.Code, "unexpected status code")
+
+		var body map[string]interface{}
+		err := msgpack.Unmarshal(res.Body.Bytes(), &body)
+		require.NoError(t, err, "failed to decode msgpack response")
+		post, ok := body["post"].(map[string]interface{})
+		require.True(t, ok, "post is missing")
+		require.Equal(t, testData.TestPostOneUserOneID.String(), post["id"], "invalid post")
+	})
+
+	t.Run("should write list as csv", func(t *testing.T) {
+		res := request(a.Echo, "GET", "/api/v2/posts?limit=100", map[string]string{
+			"Accept": "text/csv",
+		}, nil)
+		require.Equal(t, http.StatusOK, res.Code, "unexpected status code")
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		require.NoError(t, err, "failed to decode csv response")
+		require.GreaterOrEqual(t, len(records), testData.TotalPosts+1, "rows are missing")
+		require.Contains(t, records[0], "id", "header is missing")
+		require.Contains(t, records[0], "title", "header is missing")
+	})
+
+	t.Run("should not accept unsupported types", func(t *testing.T) {
+		res := request(a.Echo, "GET", postURL, map[string]string{
+			"Accept": "text/html, application/json;q=0",
+		}, nil)
+		require.Equal(t, http.StatusNotAcceptable, res.Code, "unexpected status code")
+
+		// csv is acceptable only for lists
+		res = request(a.Echo, "GET", postURL, map[string]string{
+			"Accept": "text/csv",
+		}, nil)
+		require.Equal(t, http.StatusNotAcceptable, res.Code, "unexpected status code")
+
+		// errors are not hidden by negotiation
+		res = request(a.Echo, "GET", fmt.Sprintf("/api/v2/posts/%s", uuid.New()), map[string]string{
+			"Accept": "text/html",
+		}, nil)
+		require.Equal(t, http.StatusNotFound, res.Code, "unexpected status code")
+		require.True(t, strings.HasPrefix(res.Header().Get("Content-Type"), problem.MimeTypeJSON), "error is not json problem")
+	})
+
+	t.Run("should decode body by content type", func(t *testing.T) {
+		yamlBody := []byte("title: yaml post\nbody: yaml body\n")
+		msgpackBody, err := msgpack.Marshal(map[string]string{"title": "msgpack post", "body": "msgpack body"})
+		require.NoError(t, err, "failed to encode msgpack body")
+
+		for contentType, body := range map[string][]byte{
+			"application/yaml":    yamlBody,
+			"application/msgpack": msgpackBody,
+		} {
+			res := request(a.Echo, "POST", "/api/v2/posts", map[string]string{
+				"Content-Type": contentType,
+			}, body)
+			require.Equal(t, http.StatusCreated, res.Code, "failed to create post from %s body", contentType)
+
+			var created posts.CreatePostHandlerResponseBody
+			err := json.Unmarshal(res.Body.Bytes(), &created)
+			require.NoError(t, err, "failed to decode response")
+			require.Contains(t, created.Post.Title, "post", "invalid title")
+		}
+
+		res := request(a.Echo, "POST", "/api/v2/posts", map[string]string{
+			"Content-Type": "text/plain",
+		}, []byte("title"))
+		require.Equal(t, http.StatusBadRequest, res.Code, "unsupported body was parsed")
+	})
+
+	t.Run("should negotiate v1 responses", func(t *testing.T) {
+		res := request(a.Router, "GET", fmt.Sprintf("/api/v1/posts/%s", testData.TestPostOneUserOneID), map[string]string{
+			"Accept": "text/html, application/xml;q=0.9",
+		}, nil)
+		require.Equal(t, http.StatusOK, res.Code, "unexpected status code")
+		require.True(t, strings.HasPrefix(res.Header().Get("Content-Type"), "application/xml"), "response is not xml")
+
+		res = request(a.Router, "POST", "/api/v1/posts", map[string]string{
+			"Content-Type": "application/yaml",
+		}, []byte("title: v1 yaml post\nbody: v1 yaml body\n"))
+		require.Equal(t, http.StatusCreated, res.Code, "failed to create post from yaml body")
+	})
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostProblemDetails(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	token := access.MustEncodeToken(&access.Token{
+		UserID: testData.TestUserOneID,
+	}, a.Config.HMACSecret)
+
+	defer func() {
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	// request is used to send request with headers and decode problem from response
+	request := func(router http.Handler, method string, url string, headers map[string]string, body []byte) (*httptest.ResponseRecorder, problem.Problem) {
+		req := httptest.NewRequest(method, url, bytes.NewReader(body))
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		var p problem.Problem
+		if strings.HasPrefix(recorder.Header().Get("Content-Type"), problem.MimeTypeXML) {
+			err := xml.Unmarshal(recorder.Body.Bytes(), &p)
+			require.NoError(t, err, "failed to decode xml problem")
+		} else {
+			err := json.Unmarshal(recorder.Body.Bytes(), &p)
+			require.NoError(t, err, "failed to decode json problem")
+		}
+		return recorder, p
+	}
+
+	t.Run("should describe missing resource", func(t *testing.T) {
+		url := fmt.Sprintf("/api/v2/posts/%s", uuid.New())
+		res, p := request(a.Echo, "GET", url, nil, nil)
+		require.Equal(t, http.StatusNotFound, res.Code, "unexpected status code")
+		require.Equal(t, problem.MimeTypeJSON, res.Header().Get("Content-Type"), "invalid content type")
+		require.Equal(t, problem.CodeNotFound, p.Code, "invalid code")
+		require.Equal(t, problem.TypePrefix+string(problem.CodeNotFound), p.Type, "invalid type")
+		require.Equal(t, http.StatusNotFound, p.Status, "invalid status")
+		require.Equal(t, url, p.Instance, "invalid instance")
+		require.NotEmpty(t, p.Title, "title is missing")
+	})
+
+	t.Run("should describe invalid fields", func(t *testing.T) {
+		res, p := request(a.Echo, "POST", "/api/v2/posts", map[string]string{
+			"Authorization": "Bearer " + token,
+			"Content-Type":  "application/json",
+		}, []byte(`{"title":""}`))
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, problem.CodeValidationFailed, p.Code, "invalid code")
+
+		fields := map[string]string{}
+		for _, field := range p.Errors {
+			fields[field.Field] = field.Rule
+		}
+		require.Equal(t, map[string]string{"title": "required", "body": "required"}, fields, "invalid fields")
+	})
+
+	t.Run("should write xml problem", func(t *testing.T) {
+		res, p := request(a.Echo, "DELETE", fmt.Sprintf("/api/v2/posts/%s", testData.TestPostOneUserOneID), map[string]string{
+			"Accept": "application/xml",
+		}, nil)
+		require.Equal(t, http.StatusUnauthorized, res.Code, "unexpected status code")
+		require.Equal(t, problem.MimeTypeXML, res.Header().Get("Content-Type"), "invalid content type")
+		require.Equal(t, problem.CodeUnauthorized, p.Code, "invalid code")
+	})
+
+	t.Run("should describe errors of unknown routes", func(t *testing.T) {
+		res, p := request(a.Echo, "GET", "/api/v2/unknown", nil, nil)
+		require.Equal(t, http.StatusNotFound, res.Code, "unexpected status code")
+		require.Equal(t, problem.CodeNotFound, p.Code, "invalid code")
+	})
+
+	t.Run("should describe v1 errors", func(t *testing.T) {
+		res, p := request(a.Router, "GET", "/api/v1/posts/invalid", nil, nil)
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, problem.MimeTypeJSON, res.Header().Get("Content-Type"), "invalid content type")
+		require.Equal(t, problem.CodeBadRequest, p.Code, "invalid code")
+
+		res, p = request(a.Router, "PUT", fmt.Sprintf("/api/v1/posts/%s", testData.TestPostOneUserOneID), map[string]string{
+			"Authorization": "Bearer " + token,
+		}, []byte(`{"title":""}`))
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, problem.CodeValidationFailed, p.Code, "invalid code")
+		require.NotEmpty(t, p.Errors, "invalid fields are missing")
+	})
+}
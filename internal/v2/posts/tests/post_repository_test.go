@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostHandlersWithMemoryRepositories(t *testing.T) {
+	// init service without database
+	s := service.Service{}
+	s.Initialize(&service.InitializeOptions{
+		Echo:      true,
+		Validator: true,
+	})
+	s.Repositories = repository.NewMemory()
+	posts.Posts{}.Setup(&s)
+
+	// init test client
+	userID := uuid.New()
+	client := testclient.TestClient{}
+	client.Setup(&testclient.Options{
+		Router: s.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: userID,
+		}, s.Config.HMACSecret),
+	})
+
+	// create posts
+	var created []uuid.UUID
+	for i := 0; i < 3; i++ {
+		var res posts.CreatePostHandlerResponseBody
+		err := client.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/posts",
+			Body: &posts.CreatePostHandlerRequestBody{
+				Title: fmt.Sprintf("test post title %d", i),
+				Body:  "test post body",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to create post")
+		require.Equal(t, userID, res.Post.UserID, "invalid author")
+		created = append(created, res.Post.ID)
+	}
+
+	t.Run("should get posts page by page", func(t *testing.T) {
+		var first posts.GetPostsHandlerResponseBody
+		err := client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/posts",
+			Query:    &posts.GetPostsHandlerRequestQuery{Limit: 2},
+			Response: &first,
+		})
+		require.NoError(t, err, "failed to get posts")
+		require.Equal(t, int64(3), first.Total, "invalid total")
+		require.Len(t, *first.Posts, 2, "invalid page size")
+		require.Equal(t, created[2], (*first.Posts)[0].ID, "newest post is not first")
+		require.NotEmpty(t, first.NextCursor, "missing next cursor")
+
+		var second posts.GetPostsHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/posts",
+			Query:    &posts.GetPostsHandlerRequestQuery{Limit: 2, Cursor: first.NextCursor},
+			Response: &second,
+		})
+		require.NoError(t, err, "failed to get posts")
+		require.Len(t, *second.Posts, 1, "invalid page size")
+		require.Equal(t, created[0], (*second.Posts)[0].ID, "oldest post is not last")
+		require.Empty(t, second.NextCursor, "unexpected next cursor")
+	})
+
+	t.Run("should update post", func(t *testing.T) {
+		var res posts.UpdatePostHandlerResponseBody
+		err := client.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/posts/%s", created[0]),
+			Body: &posts.UpdatePostHandlerRequestBody{
+				Title: "updated test post title",
+				Body:  "updated test post body",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to update post")
+
+		var got posts.GetPostHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s", created[0]),
+			Response: &got,
+		})
+		require.NoError(t, err, "failed to get post")
+		require.Equal(t, "updated test post title", got.Post.Title, "title was not updated")
+	})
+
+	t.Run("should delete post with comments", func(t *testing.T) {
+		err := client.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    fmt.Sprintf("/api/v2/posts/%s/comments", created[1]),
+			Body: &posts.CreatePostCommentHandlerRequestBody{
+				Name: "test comment name",
+				Body: "test comment body",
+			},
+			Response: &posts.CreatePostCommentHandlerResponseBody{},
+		})
+		require.NoError(t, err, "failed to create comment")
+
+		var count posts.CountPostCommentsHandlerResponseBody
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s/comments/count", created[1]),
+			Response: &count,
+		})
+		require.NoError(t, err, "failed to count comments")
+		require.Equal(t, int64(1), count.Count, "invalid comments count")
+
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "DELETE",
+			URL:      fmt.Sprintf("/api/v2/posts/%s", created[1]),
+			Response: &posts.DeletePostHandlerResponseBody{},
+		})
+		require.NoError(t, err, "failed to delete post")
+
+		left, err := s.Comments.Count(context.Background(), repository.CommentFilter{PostID: created[1]})
+		require.NoError(t, err, "failed to count comments")
+		require.Zero(t, left, "comments of deleted post were kept")
+
+		err = client.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/posts/%s", created[1]),
+			Response: &posts.GetPostHandlerResponseBody{},
+		})
+		require.Error(t, err, "deleted post was found")
+	})
+}
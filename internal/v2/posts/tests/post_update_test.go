@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/posts"
 	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/models"
@@ -15,8 +15,7 @@ import (
 
 func TestUpdatePostHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := PostsTestFixtures()
@@ -40,6 +39,15 @@ func TestUpdatePostHandler(t *testing.T) {
 		}, a.Config.HMACSecret),
 	})
 
+	adminClient := testclient.TestClient{}
+	adminClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   uuid.New(),
+			UserRole: models.UserRoleAdmin,
+		}, a.Config.HMACSecret),
+	})
+
 	defer func() {
 		// cleanup test data
 		err := fixture.Teardown()
@@ -62,6 +70,21 @@ func TestUpdatePostHandler(t *testing.T) {
 		require.Error(t, err, "random user could update comment")
 	})
 
+	t.Run("admin can update any post", func(t *testing.T) {
+		var res posts.UpdatePostHandlerResponseBody
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/posts/%s", testData.TestPostOneUserTwoID),
+			Body: &posts.UpdatePostHandlerRequestBody{
+				Title: updatedPostTitle,
+				Body:  updatedPostBody,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "admin failed to update post")
+		require.Equal(t, updatedPostTitle, res.Post.Title, "unexpected title value")
+	})
+
 	t.Run("should error if passing invalid post uuid", func(t *testing.T) {
 		var res posts.UpdatePostHandlerResponseBody
 		err := authorClient.Request(&testclient.RequestOptions{
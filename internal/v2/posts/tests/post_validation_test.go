@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostFieldValidation(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := PostsTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	token := access.MustEncodeToken(&access.Token{
+		UserID: testData.TestUserOneID,
+	}, a.Config.HMACSecret)
+
+	defer func() {
+		// cleanup comments created by test and test data
+		err := a.MySQL.
+			Unscoped().
+			Where(&models.Comment{UserID: testData.TestUserOneID}).
+			Delete(&models.Comment{}).
+			Error
+		require.NoError(t, err, "failed to clean up created comments")
+
+		err = fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	// request is used to send json body in language and get invalid fields by name
+	request := func(method string, url string, lang string, body interface{}) (*httptest.ResponseRecorder, map[string]problem.FieldError) {
+		b, err := json.Marshal(body)
+		require.NoError(t, err, "failed to encode body")
+
+		req := httptest.NewRequest(method, url, bytes.NewReader(b))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		recorder := httptest.NewRecorder()
+		a.Echo.ServeHTTP(recorder, req)
+
+		fields := map[string]problem.FieldError{}
+		if recorder.Code == http.StatusBadRequest {
+			var p problem.Problem
+			err := json.Unmarshal(recorder.Body.Bytes(), &p)
+			require.NoError(t, err, "failed to decode problem")
+			require.Equal(t, problem.CodeValidationFailed, p.Code, "invalid code")
+			for _, field := range p.Errors {
+				fields[field.Field] = field
+			}
+		}
+		return recorder, fields
+	}
+
+	t.Run("should reject too long title", func(t *testing.T) {
+		res, fields := request("POST", "/api/v2/posts", "", map[string]string{
+			"title": strings.Repeat("т", a.Config.PostTitleMaxLength+1),
+			"body":  "test post body",
+		})
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, "title", fields["title"].Rule, "invalid rule")
+
+		// length is counted in characters
+		res, _ = request("POST", "/api/v2/posts", "", map[string]string{
+			"title": strings.Repeat("т", a.Config.PostTitleMaxLength),
+			"body":  "test post body",
+		})
+		require.Equal(t, http.StatusCreated, res.Code, "unexpected status code")
+	})
+
+	t.Run("should reject html in body", func(t *testing.T) {
+		res, fields := request("POST", "/api/v2/posts", "", map[string]string{
+			"title": "test post title",
+			"body":  "test <script>alert(1)</script> body",
+		})
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, "nohtml", fields["body"].Rule, "invalid rule")
+		require.Equal(t, "must not contain html", fields["body"].Message, "invalid message")
+
+		res, _ = request("POST", "/api/v2/posts", "", map[string]string{
+			"title": "test post title",
+			"body":  "1 < 2 and 3 > 2",
+		})
+		require.Equal(t, http.StatusCreated, res.Code, "comparison was treated as html")
+	})
+
+	t.Run("should reject comment of missing post", func(t *testing.T) {
+		res, fields := request("POST", "/api/v2/comments", "", map[string]string{
+			"postId": uuid.New().String(),
+			"name":   "test comment name",
+			"body":   "test comment body",
+		})
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, "post", fields["postId"].Rule, "invalid rule")
+
+		res, _ = request("POST", "/api/v2/comments", "", map[string]string{
+			"postId": testData.TestPostOneUserOneID.String(),
+			"name":   "test comment name",
+			"body":   "test comment body",
+		})
+		require.Equal(t, http.StatusCreated, res.Code, "unexpected status code")
+	})
+
+	t.Run("should translate messages to accepted language", func(t *testing.T) {
+		body := map[string]string{"title": "test post title"}
+
+		res, fields := request("POST", "/api/v2/posts", "uk-UA, en;q=0.8", body)
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, problem.Ukrainian, res.Header().Get("Content-Language"), "invalid language")
+		require.Equal(t, "є обов'язковим", fields["body"].Message, "message is not translated")
+
+		res, fields = request("POST", "/api/v2/posts", "de, en;q=0.5", body)
+		require.Equal(t, http.StatusBadRequest, res.Code, "unexpected status code")
+		require.Equal(t, problem.English, res.Header().Get("Content-Language"), "invalid language")
+		require.Equal(t, "is required", fields["body"].Message, "invalid message")
+	})
+}
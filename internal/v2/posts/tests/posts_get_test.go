@@ -3,9 +3,10 @@ package tests
 import (
 	"testing"
 
-	app "github.com/Tamplier2911/gorest/internal"
+	"github.com/Tamplier2911/gorest/internal/apptest"
 	"github.com/Tamplier2911/gorest/internal/v2/posts"
 	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
 	"github.com/Tamplier2911/gorest/pkg/testclient"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -13,8 +14,7 @@ import (
 
 func TestGetPostsHandler(t *testing.T) {
 	// init service
-	a := app.Application{}
-	a.Setup()
+	a := apptest.New()
 
 	// init test fixtures
 	fixture := PostsTestFixtures()
@@ -88,4 +88,93 @@ func TestGetPostsHandler(t *testing.T) {
 			require.NotEqual(t, prevPostId, c.ID, "got same post with different offset")
 		}
 	})
+
+	t.Run("cursor pagination should work", func(t *testing.T) {
+		var first posts.GetPostsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Limit: 2,
+			},
+			Response: &first,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *first.Posts, 2, "invalid response length")
+		require.NotEmpty(t, first.NextCursor, "next cursor was empty")
+		require.Empty(t, first.PrevCursor, "prev cursor on first page")
+
+		var second posts.GetPostsHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Limit:  2,
+				Cursor: first.NextCursor,
+			},
+			Response: &second,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.NotEmpty(t, second.PrevCursor, "prev cursor was empty")
+		for _, n := range *second.Posts {
+			for _, p := range *first.Posts {
+				require.NotEqual(t, p.ID, n.ID, "got same record on next page")
+			}
+		}
+
+		var back posts.GetPostsHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Limit:  2,
+				Cursor: second.PrevCursor,
+			},
+			Response: &back,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, *first.Posts, *back.Posts, "prev page differs from first page")
+	})
+
+	t.Run("should error if passing malformed cursor", func(t *testing.T) {
+		var res posts.GetPostsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Cursor: "malformed cursor",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "parsed malformed cursor")
+	})
+
+	t.Run("should skip total", func(t *testing.T) {
+		var res posts.GetPostsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Limit:     1,
+				SkipTotal: true,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Zero(t, res.Total, "total was counted")
+	})
+
+	t.Run("limit should be capped", func(t *testing.T) {
+		var res posts.GetPostsHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/posts",
+			Query: &posts.GetPostsHandlerRequestQuery{
+				Limit: pagination.MaxLimit + 1,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.LessOrEqual(t, len(*res.Posts), pagination.MaxLimit, "limit was not capped")
+	})
 }
@@ -0,0 +1,24 @@
+package search
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+type Search struct {
+	*service.Service
+}
+
+func (s Search) Setup(svc *service.Service) {
+	s.Service = svc
+
+	// limit requests of route group, authenticated requests are counted by user
+	limit := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return service.RateLimitMiddleware(s.Logger, s.RateLimiter, "search", next)
+	}
+
+	// configure router
+	SearchRouter := s.Echo.Group("/api/v2/search")
+
+	SearchRouter.GET("", limit(s.SearchHandler))
+}
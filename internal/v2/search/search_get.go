@@ -0,0 +1,131 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/fulltext"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input query of SearchHandler
+type SearchHandlerRequestQuery struct {
+	Q         string `query:"q" validate:"required,max=255"`
+	Type      string `query:"type" validate:"required,oneof=posts comments"`
+	Limit     int    `query:"limit"`
+	Offset    int    `query:"offset"`
+	Cursor    string `query:"cursor"`
+	SkipTotal bool   `query:"skipTotal"`
+} // @name SearchRequest
+
+// Represent output data of SearchHandler
+type SearchHandlerResponseBody struct {
+	Hits       *[]fulltext.Hit `json:"hits" xml:"hits"`
+	Total      int64           `json:"total" xml:"total"`
+	NextCursor string          `json:"nextCursor,omitempty" xml:"nextCursor,omitempty"`
+	PrevCursor string          `json:"prevCursor,omitempty" xml:"prevCursor,omitempty"`
+	Message    string          `json:"message" xml:"message"`
+} // @name SearchResponse
+
+// SearchHandler godoc
+//
+// @id				Search
+// @Summary 		Searches posts or comments.
+// @Description 	Searches post titles and bodies or comment names and bodies, results are ranked and highlighted, supports offset and cursor pagination.
+//
+// @Tags			Search
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
+//
+// @Param fields query SearchHandlerRequestQuery true "data"
+//
+// @Success 200 	{object} SearchHandlerResponseBody
+// @Failure 400 	{object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Router /search [GET]
+func (s *Search) SearchHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), s.Logger.Named("SearchHandler"))
+
+	logger.Infow("parsing request query params")
+	var query SearchHandlerRequestQuery
+	err := c.Bind(&query)
+	if err != nil {
+		logger.Errorw("failed to parse request query", "err", err)
+		return problem.BadRequest("failed to parse request query")
+	}
+	logger = logger.With("query", query)
+
+	// validate query data
+	logger.Infow("validating request query")
+	err = s.Validator.Struct(&query)
+	if err != nil {
+		logger.Errorw("failed to validate query", "err", err)
+		return problem.Validation("failed to validate query", err)
+	}
+
+	// cursor takes precedence over offset
+	offset := query.Offset
+	if query.Cursor != "" {
+		logger.Infow("parsing cursor")
+		offset, err = fulltext.DecodeCursor(query.Cursor)
+		if err != nil {
+			logger.Errorw("failed to parse cursor", "err", err)
+			return problem.BadRequest("failed to parse cursor")
+		}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	// apply default limit and cap it
+	limit := pagination.Limit(query.Limit)
+
+	// search documents
+	logger.Infow("searching documents")
+	result, err := s.Search.Search(fulltext.Query{
+		Type:      fulltext.Type(query.Type),
+		Text:      query.Q,
+		Limit:     limit,
+		Offset:    offset,
+		SkipTotal: query.SkipTotal,
+	})
+	if err != nil {
+		logger.Errorw("failed to search documents", "err", err)
+		return problem.Internal("failed to search")
+	}
+
+	// build page cursors
+	var nextCursor, prevCursor string
+	if result.More {
+		nextCursor = fulltext.EncodeCursor(offset + limit)
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		prevCursor = fulltext.EncodeCursor(prev)
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := SearchHandlerResponseBody{
+		Hits:       &result.Hits,
+		Total:      result.Total,
+		NextCursor: nextCursor,
+		PrevCursor: prevCursor,
+		Message:    "successfully retrieved search results",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully searched documents")
+	return s.ResponseWriter(c, http.StatusOK, res)
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Fixtures represent test fixture.
+type Fixture struct {
+	Setup    func() (TestFixturesData, error)
+	Teardown func() error
+}
+
+// TestFixtureData represent set of test fixture data.
+type TestFixturesData struct {
+	TestUserOneID         uuid.UUID
+	TestPostTitleMatchID  uuid.UUID
+	TestPostBodyMatchID   uuid.UUID
+	TestCommentMatchID    uuid.UUID
+	TestCommentMatchPost  uuid.UUID
+	TotalPostMatches      int
+	TotalCommentMatches   int
+	TestSearchTerm        string
+	TestHighlightFragment string
+}
+
+// SearchTestFixtures return instance of fixture.
+func SearchTestFixtures() Fixture {
+	// init service
+	a := apptest.New()
+
+	// test users
+	var testUsers []models.User
+	// test posts
+	var testPosts []models.Post
+	// test comments
+	var testComments []models.Comment
+
+	setup := func() (TestFixturesData, error) {
+		// create test users
+		testUsers = []models.User{
+			{
+				Username: "test_user_one_search",
+				Email:    "test_user_one_search@test.com",
+				UserRole: models.UserRoleUser,
+			},
+		}
+		err := a.MySQL.Create(&testUsers).Error
+		if err != nil {
+			return TestFixturesData{}, err
+		}
+
+		// create test posts
+		testPosts = []models.Post{
+			{
+				UserID: testUsers[0].ID,
+				Title:  "quokka quokka sightings",
+				Body:   "quokka spotted near the island",
+			},
+			{
+				UserID: testUsers[0].ID,
+				Title:  "island travel notes",
+				Body:   "we met a friendly quokka",
+			},
+			{
+				UserID: testUsers[0].ID,
+				Title:  "unrelated post",
+				Body:   "nothing to see here",
+			},
+		}
+		err = a.MySQL.Create(&testPosts).Error
+		if err != nil {
+			return TestFixturesData{}, err
+		}
+
+		// create test comments
+		testComments = []models.Comment{
+			{
+				PostID: testPosts[2].ID,
+				UserID: testUsers[0].ID,
+				Name:   "quokka fan",
+				Body:   "more pictures please",
+			},
+			{
+				PostID: testPosts[2].ID,
+				UserID: testUsers[0].ID,
+				Name:   "unrelated comment",
+				Body:   "nice post",
+			},
+		}
+		err = a.MySQL.Create(&testComments).Error
+		if err != nil {
+			return TestFixturesData{}, err
+		}
+
+		return TestFixturesData{
+			TestUserOneID:         testUsers[0].ID,
+			TestPostTitleMatchID:  testPosts[0].ID,
+			TestPostBodyMatchID:   testPosts[1].ID,
+			TestCommentMatchID:    testComments[0].ID,
+			TestCommentMatchPost:  testPosts[2].ID,
+			TotalPostMatches:      2,
+			TotalCommentMatches:   1,
+			TestSearchTerm:        "quokka",
+			TestHighlightFragment: "we met a friendly <mark>quokka</mark>",
+		}, nil
+	}
+
+	teardown := func() error {
+		// clean up test comments
+		err := a.MySQL.Unscoped().Delete(&testComments).Error
+		if err != nil {
+			return err
+		}
+
+		// clean up test posts
+		err = a.MySQL.Unscoped().Delete(&testPosts).Error
+		if err != nil {
+			return err
+		}
+
+		// clean up test users
+		err = a.MySQL.Unscoped().Delete(&testUsers).Error
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	return Fixture{
+		Setup:    setup,
+		Teardown: teardown,
+	}
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/search"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHandler(t *testing.T) {
+	// init test fixtures before service so in-memory backend loads them
+	fixture := SearchTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	// init service
+	a := apptest.New()
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+	})
+
+	t.Run("should rank and highlight posts", func(t *testing.T) {
+		var res search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:    testData.TestSearchTerm,
+				Type: "posts",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Equal(t, testData.TotalPostMatches, int(res.Total), "invalid total")
+		require.Len(t, *res.Hits, testData.TotalPostMatches, "invalid response length")
+
+		hits := *res.Hits
+		require.Equal(t, testData.TestPostTitleMatchID, hits[0].ID, "best match was not ranked first")
+		require.Equal(t, testData.TestPostBodyMatchID, hits[1].ID, "weaker match was not ranked second")
+		require.GreaterOrEqual(t, hits[0].Score, hits[1].Score, "hits are not ordered by score")
+		require.Len(t, hits[1].Highlights, 1, "only body should be highlighted")
+		require.Equal(t, "body", hits[1].Highlights[0].Field, "invalid highlighted field")
+		require.Equal(t, testData.TestHighlightFragment, hits[1].Highlights[0].Fragment, "invalid highlight")
+	})
+
+	t.Run("should search comments", func(t *testing.T) {
+		var res search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:    testData.TestSearchTerm,
+				Type: "comments",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *res.Hits, testData.TotalCommentMatches, "invalid response length")
+
+		hit := (*res.Hits)[0]
+		require.Equal(t, testData.TestCommentMatchID, hit.ID, "invalid comment")
+		require.NotNil(t, hit.PostID, "post id was not provided")
+		require.Equal(t, testData.TestCommentMatchPost, *hit.PostID, "invalid post id")
+	})
+
+	t.Run("cursor pagination should work", func(t *testing.T) {
+		var first search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:     testData.TestSearchTerm,
+				Type:  "posts",
+				Limit: 1,
+			},
+			Response: &first,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *first.Hits, 1, "invalid response length")
+		require.NotEmpty(t, first.NextCursor, "next cursor was empty")
+
+		var second search.SearchHandlerResponseBody
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:         testData.TestSearchTerm,
+				Type:      "posts",
+				Limit:     1,
+				Cursor:    first.NextCursor,
+				SkipTotal: true,
+			},
+			Response: &second,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *second.Hits, 1, "invalid response length")
+		require.Equal(t, testData.TestPostBodyMatchID, (*second.Hits)[0].ID, "invalid second page")
+		require.Empty(t, second.NextCursor, "next cursor on last page")
+		require.NotEmpty(t, second.PrevCursor, "prev cursor was empty")
+		require.Zero(t, second.Total, "total was counted")
+	})
+
+	t.Run("should error if query is missing", func(t *testing.T) {
+		var res search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Type: "posts",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "searched without query")
+	})
+
+	t.Run("should error if type is invalid", func(t *testing.T) {
+		var res search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:    testData.TestSearchTerm,
+				Type: "users",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "searched unknown type")
+	})
+
+	t.Run("should error if passing malformed cursor", func(t *testing.T) {
+		var res search.SearchHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/search",
+			Query: &search.SearchHandlerRequestQuery{
+				Q:      testData.TestSearchTerm,
+				Type:   "posts",
+				Cursor: "malformed cursor",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "parsed malformed cursor")
+	})
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Fixtures represent test fixture.
+type Fixture struct {
+	Setup    func() (TestFixturesData, error)
+	Teardown func() error
+}
+
+// TestFixtureData represent set of test fixture data.
+type TestFixturesData struct {
+	TestUserOneID uuid.UUID
+	TestUserTwoID uuid.UUID
+}
+
+// TokensTestFixtures return instance of fixture.
+func TokensTestFixtures() Fixture {
+	// init service
+	a := apptest.New()
+
+	// test users
+	var testUsers []models.User
+
+	setup := func() (TestFixturesData, error) {
+		// create test users
+		testUsers = []models.User{
+			{
+				Username: "test_user_one_tokens",
+				Email:    "test_user_one_tokens@test.com",
+				UserRole: models.UserRoleUser,
+			},
+			{
+				Username: "test_user_two_tokens",
+				Email:    "test_user_two_tokens@test.com",
+				UserRole: models.UserRoleUser,
+			},
+		}
+		err := a.MySQL.Create(&testUsers).Error
+		if err != nil {
+			return TestFixturesData{}, err
+		}
+
+		return TestFixturesData{
+			TestUserOneID: testUsers[0].ID,
+			TestUserTwoID: testUsers[1].ID,
+		}, nil
+	}
+
+	teardown := func() error {
+		// clean up test users, tokens and posts are removed by cascade
+		err := a.MySQL.Unscoped().Delete(&testUsers).Error
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	return Fixture{
+		Setup:    setup,
+		Teardown: teardown,
+	}
+}
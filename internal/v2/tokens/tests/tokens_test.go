@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/posts"
+	"github.com/Tamplier2911/gorest/internal/v2/tokens"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalAccessTokens(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := TokensTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test client authenticated with session token
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserOneID,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	// patClient is used to create client authenticated with personal access token
+	patClient := func(token string) *testclient.TestClient {
+		client := testclient.TestClient{}
+		client.Setup(&testclient.Options{
+			Router: a.Echo,
+			Token:  token,
+		})
+		return &client
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	var writer tokens.CreateTokenHandlerResponseBody
+	var reader tokens.CreateTokenHandlerResponseBody
+
+	t.Run("should not create token with unknown scope", func(t *testing.T) {
+		var res tokens.CreateTokenHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/tokens",
+			Body: &tokens.CreateTokenHandlerRequestBody{
+				Name:      "unknown",
+				Scopes:    []string{"posts:admin"},
+				ExpiresAt: expiresAt,
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created token with unknown scope")
+	})
+
+	t.Run("should not create token expiring after maximum lifetime", func(t *testing.T) {
+		var res tokens.CreateTokenHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/tokens",
+			Body: &tokens.CreateTokenHandlerRequestBody{
+				Name:      "forever",
+				Scopes:    []string{policy.ScopePostsRead},
+				ExpiresAt: time.Now().Add(a.Config.PersonalAccessTokenMaxTTL + time.Hour),
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created token exceeding maximum lifetime")
+	})
+
+	t.Run("should create token with session token", func(t *testing.T) {
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/tokens",
+			Body: &tokens.CreateTokenHandlerRequestBody{
+				Name:      "ci writer",
+				Scopes:    []string{policy.ScopePostsWrite, policy.ScopeTokensRead, policy.ScopeTokensWrite},
+				ExpiresAt: expiresAt,
+			},
+			Response: &writer,
+		})
+		require.NoError(t, err, "failed to create token")
+		require.True(t, access.IsPersonalAccessToken(writer.Token), "unexpected token format")
+		require.Equal(t, writer.Token[:len(writer.PersonalAccessToken.Prefix)], writer.PersonalAccessToken.Prefix, "unexpected token prefix")
+
+		err = testClient.Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/tokens",
+			Body: &tokens.CreateTokenHandlerRequestBody{
+				Name:      "ci reader",
+				Scopes:    []string{policy.ScopePostsRead},
+				ExpiresAt: expiresAt,
+			},
+			Response: &reader,
+		})
+		require.NoError(t, err, "failed to create token")
+	})
+
+	t.Run("token stored hashed", func(t *testing.T) {
+		var pat models.PersonalAccessToken
+		err := a.MySQL.
+			Model(&models.PersonalAccessToken{}).
+			Where(&models.PersonalAccessToken{Base: models.Base{ID: writer.PersonalAccessToken.ID}}).
+			First(&pat).
+			Error
+		require.NoError(t, err, "failed to find token in database")
+		require.Equal(t, access.HashPersonalAccessToken(writer.Token), pat.TokenHash, "unexpected token hash")
+		require.ElementsMatch(t, writer.PersonalAccessToken.Scopes, pat.Scopes, "unexpected token scopes")
+	})
+
+	t.Run("should create post with token holding scope", func(t *testing.T) {
+		var res posts.CreatePostHandlerResponseBody
+		err := patClient(writer.Token).Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/posts",
+			Body: &posts.CreatePostHandlerRequestBody{
+				Title: "created with personal access token",
+				Body:  "created with personal access token",
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to create post")
+		require.Equal(t, testData.TestUserOneID, res.Post.UserID, "unexpected post owner")
+	})
+
+	t.Run("should not create post with token missing scope", func(t *testing.T) {
+		var res posts.CreatePostHandlerResponseBody
+		err := patClient(reader.Token).Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/posts",
+			Body: &posts.CreatePostHandlerRequestBody{
+				Title: "created with personal access token",
+				Body:  "created with personal access token",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "created post without scope")
+		require.Contains(t, err.Error(), fmt.Sprint(403), "unexpected status code")
+	})
+
+	t.Run("token should not grant scopes it does not hold", func(t *testing.T) {
+		var res tokens.CreateTokenHandlerResponseBody
+		err := patClient(writer.Token).Request(&testclient.RequestOptions{
+			Method: "POST",
+			URL:    "/api/v2/tokens",
+			Body: &tokens.CreateTokenHandlerRequestBody{
+				Name:      "escalated",
+				Scopes:    []string{policy.ScopeUsersWrite},
+				ExpiresAt: expiresAt,
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "granted scope not held by token")
+		require.Contains(t, err.Error(), fmt.Sprint(403), "unexpected status code")
+	})
+
+	t.Run("should list tokens of user", func(t *testing.T) {
+		var res tokens.GetTokensHandlerResponseBody
+		err := patClient(writer.Token).Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/tokens",
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to get tokens")
+		require.Len(t, *res.PersonalAccessTokens, 2, "unexpected amount of tokens")
+		for _, pat := range *res.PersonalAccessTokens {
+			require.Equal(t, testData.TestUserOneID, pat.UserID, "unexpected token owner")
+		}
+	})
+
+	t.Run("should not revoke token of other user", func(t *testing.T) {
+		other := testclient.TestClient{}
+		other.Setup(&testclient.Options{
+			Router: a.Echo,
+			Token: access.MustEncodeToken(&access.Token{
+				UserID: testData.TestUserTwoID,
+			}, a.Config.HMACSecret),
+		})
+
+		var res tokens.RevokeTokenHandlerResponseBody
+		err := other.Request(&testclient.RequestOptions{
+			Method:   "DELETE",
+			URL:      "/api/v2/tokens/" + reader.PersonalAccessToken.ID.String(),
+			Response: &res,
+		})
+		require.Error(t, err, "revoked token of other user")
+		require.Contains(t, err.Error(), fmt.Sprint(404), "unexpected status code")
+	})
+
+	t.Run("revoked token should be rejected", func(t *testing.T) {
+		var res tokens.RevokeTokenHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "DELETE",
+			URL:      "/api/v2/tokens/" + writer.PersonalAccessToken.ID.String(),
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to revoke token")
+
+		var list tokens.GetTokensHandlerResponseBody
+		err = patClient(writer.Token).Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/tokens",
+			Response: &list,
+		})
+		require.Error(t, err, "revoked token accepted")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+	})
+
+	t.Run("expired token should be rejected", func(t *testing.T) {
+		err := a.MySQL.
+			Model(&models.PersonalAccessToken{Base: models.Base{ID: reader.PersonalAccessToken.ID}}).
+			Update("expires_at", time.Now().Add(-time.Minute)).
+			Error
+		require.NoError(t, err, "failed to expire token")
+
+		var res tokens.GetTokensHandlerResponseBody
+		err = patClient(reader.Token).Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/tokens",
+			Response: &res,
+		})
+		require.Error(t, err, "expired token accepted")
+		require.Contains(t, err.Error(), fmt.Sprint(401), "unexpected status code")
+	})
+}
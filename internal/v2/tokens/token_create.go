@@ -0,0 +1,155 @@
+package tokens
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// prefixLength is number of leading token characters stored to help users identify token.
+const prefixLength = len(access.PersonalAccessTokenPrefix) + 4
+
+// Represent input data of CreateTokenHandler
+type CreateTokenHandlerRequestBody struct {
+	Name      string    `json:"name" form:"name" binding:"required" validate:"required,max=255"`
+	Scopes    []string  `json:"scopes" form:"scopes" binding:"required" validate:"required,min=1,dive,required"`
+	ExpiresAt time.Time `json:"expiresAt" form:"expiresAt" binding:"required" validate:"required"`
+} // @name CreateTokenRequest
+
+// Represent output data of CreateTokenHandler
+type CreateTokenHandlerResponseBody struct {
+	Token               string                      `json:"token,omitempty" xml:"token,omitempty"`
+	PersonalAccessToken *models.PersonalAccessToken `json:"personalAccessToken,omitempty" xml:"personalAccessToken,omitempty"`
+	Message             string                      `json:"message" xml:"message"`
+} // @name CreateTokenResponse
+
+// CreateTokenHandler godoc
+//
+// @id				CreateToken
+// @Summary 		Creates personal access token.
+// @Description 	Creates personal access token with provided scopes, token value is returned only once.
+// @Description 	Personal access tokens can only grant scopes they hold themselves.
+//
+// @Tags			Tokens
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body CreateTokenHandlerRequestBody true "data"
+//
+// @Success 201 		{object} CreateTokenHandlerResponseBody
+// @Failure 400,401,403 {object} problem.Problem
+// @Failure 500 		{object} problem.Problem
+// @Failure default 	{object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /tokens [POST]
+func (t *Tokens) CreateTokenHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), t.Logger.Named("CreateTokenHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body CreateTokenHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+	logger = logger.With("body", body)
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = t.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// check requested scopes are known
+	for _, scope := range body.Scopes {
+		var known bool
+		for _, s := range policy.Scopes {
+			if s == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			logger.Errorw("unknown scope requested", "scope", scope)
+			return problem.BadRequest("unknown scope " + scope)
+		}
+	}
+
+	// prevent personal access tokens from escalating their own scopes
+	if !policy.HasScopes(token, body.Scopes...) {
+		logger.Errorw("requested scopes exceed scopes of token")
+		return problem.Forbidden("can not grant scopes not held by token")
+	}
+
+	// check expiration is within allowed range
+	now := time.Now()
+	if !body.ExpiresAt.After(now) {
+		logger.Errorw("expiration is in the past")
+		return problem.BadRequest("expiration must be in the future")
+	}
+	if body.ExpiresAt.After(now.Add(t.Config.PersonalAccessTokenMaxTTL)) {
+		logger.Errorw("expiration exceeds maximum lifetime", "maxTTL", t.Config.PersonalAccessTokenMaxTTL)
+		return problem.BadRequest("expiration exceeds maximum lifetime of " + t.Config.PersonalAccessTokenMaxTTL.String())
+	}
+
+	// generate token
+	logger.Infow("generating personal access token")
+	value, hash, err := access.NewPersonalAccessToken()
+	if err != nil {
+		logger.Errorw("failed to generate personal access token", "err", err)
+		return problem.Internal("failed to create token")
+	}
+
+	// save token in database
+	pat := models.PersonalAccessToken{
+		UserID:    token.UserID,
+		Name:      body.Name,
+		Prefix:    value[:prefixLength],
+		TokenHash: hash,
+		Scopes:    body.Scopes,
+		ExpiresAt: body.ExpiresAt,
+	}
+	logger.Infow("saving personal access token in database")
+	err = t.MySQL.WithContext(c.Request().Context()).
+		Model(&models.PersonalAccessToken{}).
+		Create(&pat).
+		Error
+	if err != nil {
+		logger.Errorw("failed to save personal access token in database", "err", err)
+		return problem.Internal("failed to create token")
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := CreateTokenHandlerResponseBody{
+		Token:               value,
+		PersonalAccessToken: &pat,
+		Message:             "successfully created token",
+	}
+	logger = logger.With("tokenId", pat.ID)
+
+	logger.Infow("successfully created personal access token")
+	return t.ResponseWriter(c, http.StatusCreated, res)
+}
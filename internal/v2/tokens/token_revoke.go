@@ -0,0 +1,98 @@
+package tokens
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// Represent output data of RevokeTokenHandler
+type RevokeTokenHandlerResponseBody struct {
+	Message string `json:"message" xml:"message"`
+} // @name RevokeTokenResponse
+
+// RevokeTokenHandler godoc
+//
+// @id				RevokeToken
+// @Summary 		Revokes personal access token.
+// @Description 	Revokes personal access token of authenticated user, revoked token is rejected immediately.
+//
+// @Tags			Tokens
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param id path string true "id of token"
+//
+// @Success 200 			{object} RevokeTokenHandlerResponseBody
+// @Failure 400,401,403,404 {object} problem.Problem
+// @Failure 500 			{object} problem.Problem
+// @Failure default 		{object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /tokens/{id} [DELETE]
+func (t *Tokens) RevokeTokenHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), t.Logger.Named("RevokeTokenHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid id
+	logger.Infow("parsing uuid from path")
+	tokenId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+
+	// get token owned by user from database
+	var pat models.PersonalAccessToken
+	logger.Infow("getting personal access token from database")
+	err = t.MySQL.WithContext(c.Request().Context()).
+		Model(&models.PersonalAccessToken{}).
+		Where(&models.PersonalAccessToken{Base: models.Base{ID: tokenId}, UserID: token.UserID}).
+		First(&pat).
+		Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			logger.Errorw("failed to find personal access token with provided id", "err", err)
+			return problem.NotFound("failed to find record with provided id")
+		}
+		logger.Errorw("failed to find personal access token in database", "err", err)
+		return problem.Internal("failed to revoke token")
+	}
+
+	// mark token as revoked, already revoked token keeps original time
+	if pat.RevokedAt == nil {
+		logger.Infow("revoking personal access token in database")
+		err = t.MySQL.WithContext(c.Request().Context()).
+			Model(&pat).
+			Update("revoked_at", time.Now()).
+			Error
+		if err != nil {
+			logger.Errorw("failed to revoke personal access token in database", "err", err)
+			return problem.Internal("failed to revoke token")
+		}
+	}
+
+	logger.Infow("successfully revoked personal access token")
+	return t.ResponseWriter(c, http.StatusOK, RevokeTokenHandlerResponseBody{
+		Message: "successfully revoked token",
+	})
+}
@@ -0,0 +1,33 @@
+package tokens
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/labstack/echo/v4"
+)
+
+type Tokens struct {
+	*service.Service
+}
+
+func (t Tokens) Setup(s *service.Service) {
+	t.Service = s
+
+	// limit requests of route group, authenticated requests are counted by user
+	limit := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return service.RateLimitMiddleware(t.Logger, t.RateLimiter, "tokens", next)
+	}
+
+	// configure router
+	TokensRouter := t.Echo.Group("/api/v2/tokens")
+
+	TokensRouter.POST("", service.AuthenticationMiddleware(t.Logger, t.Service, t.Revocations,
+		service.ScopeMiddleware(t.Logger, []string{policy.ScopeTokensWrite}, limit(t.CreateTokenHandler)),
+	))
+	TokensRouter.GET("", service.AuthenticationMiddleware(t.Logger, t.Service, t.Revocations,
+		service.ScopeMiddleware(t.Logger, []string{policy.ScopeTokensRead}, limit(t.GetTokensHandler)),
+	))
+	TokensRouter.DELETE("/:id", service.AuthenticationMiddleware(t.Logger, t.Service, t.Revocations,
+		service.ScopeMiddleware(t.Logger, []string{policy.ScopeTokensWrite}, limit(t.RevokeTokenHandler)),
+	))
+}
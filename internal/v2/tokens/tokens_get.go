@@ -0,0 +1,71 @@
+package tokens
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of GetTokensHandler
+type GetTokensHandlerResponseBody struct {
+	PersonalAccessTokens *[]models.PersonalAccessToken `json:"personalAccessTokens" xml:"personalAccessTokens"`
+	Message              string                        `json:"message" xml:"message"`
+} // @name GetTokensResponse
+
+// GetTokensHandler godoc
+//
+// @id				GetTokens
+// @Summary 		Gets personal access tokens.
+// @Description 	Gets personal access tokens of authenticated user, token values are never returned.
+//
+// @Tags			Tokens
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
+//
+// @Success 200 		{object} GetTokensHandlerResponseBody
+// @Failure 401,403 	{object} problem.Problem
+// @Failure 500 		{object} problem.Problem
+// @Failure default 	{object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /tokens [GET]
+func (t *Tokens) GetTokensHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), t.Logger.Named("GetTokensHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get tokens from database
+	var pats []models.PersonalAccessToken
+	logger.Infow("getting personal access tokens from database")
+	err := t.MySQL.WithContext(c.Request().Context()).
+		Model(&models.PersonalAccessToken{}).
+		Where(&models.PersonalAccessToken{UserID: token.UserID}).
+		Order("created_at DESC").
+		Find(&pats).
+		Error
+	if err != nil {
+		logger.Errorw("failed to get personal access tokens from database", "err", err)
+		return problem.Internal("failed to get tokens")
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := GetTokensHandlerResponseBody{
+		PersonalAccessTokens: &pats,
+		Message:              "successfully retrieved tokens",
+	}
+
+	logger.Infow("successfully retrieved personal access tokens", "count", len(pats))
+	return t.ResponseWriter(c, http.StatusOK, res)
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Fixtures represent test fixture.
+type Fixture struct {
+	Setup    func() (TestFixturesData, error)
+	Teardown func() error
+}
+
+// TestFixtureData represent set of test fixture data.
+type TestFixturesData struct {
+	TestAdminID   uuid.UUID
+	TestUserOneID uuid.UUID
+	TestUserTwoID uuid.UUID
+
+	TotalUsers int
+}
+
+// UsersTestFixtures return instance of fixture.
+func UsersTestFixtures() Fixture {
+	// init service
+	a := apptest.New()
+
+	// test users
+	var testUsers []models.User
+
+	setup := func() (TestFixturesData, error) {
+		// create test users
+		testUsers = []models.User{
+			{
+				Username: "test_admin_users",
+				Email:    "test_admin_users@test.com",
+				UserRole: models.UserRoleAdmin,
+			},
+			{
+				Username: "test_user_one_users",
+				Email:    "test_user_one_users@test.com",
+				UserRole: models.UserRoleUser,
+			},
+			{
+				Username: "test_user_two_users",
+				Email:    "test_user_two_users@test.com",
+				UserRole: models.UserRoleUser,
+			},
+		}
+		err := a.MySQL.Create(&testUsers).Error
+		if err != nil {
+			return TestFixturesData{}, err
+		}
+
+		return TestFixturesData{
+			TestAdminID:   testUsers[0].ID,
+			TestUserOneID: testUsers[1].ID,
+			TestUserTwoID: testUsers[2].ID,
+
+			TotalUsers: len(testUsers),
+		}, nil
+	}
+
+	teardown := func() error {
+		// clean up test users
+		err := a.MySQL.Unscoped().Delete(&testUsers).Error
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	return Fixture{
+		Setup:    setup,
+		Teardown: teardown,
+	}
+}
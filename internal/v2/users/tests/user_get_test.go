@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := UsersTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserOneID,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("should error if passing invalid uuid", func(t *testing.T) {
+		var res users.GetUserHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/users/%s", "invalid uuid"),
+			Response: &res,
+		})
+		require.Error(t, err, "parsed invalid uuid")
+	})
+
+	t.Run("should error if passing id of not existing user", func(t *testing.T) {
+		var res users.GetUserHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/users/%s", uuid.New()),
+			Response: &res,
+		})
+		require.Error(t, err, "got not existing user")
+	})
+
+	t.Run("should get requested user", func(t *testing.T) {
+		var res users.GetUserHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/users/%s", testData.TestUserTwoID),
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to get user with provided id")
+		require.Equal(t, testData.TestUserTwoID, res.User.ID, "unexpected user id")
+		require.NotEmpty(t, res.User.Username, "username field was empty")
+	})
+
+	t.Run("should not expose email", func(t *testing.T) {
+		var res struct {
+			User map[string]interface{} `json:"user"`
+		}
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      fmt.Sprintf("/api/v2/users/%s", testData.TestUserTwoID),
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to get user with provided id")
+		require.NotEmpty(t, res.User, "user is missing in response")
+		require.NotContains(t, res.User, "email", "email was exposed")
+	})
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMeHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := UsersTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test clients
+	noTokenClient := testclient.TestClient{}
+	noTokenClient.Setup(&testclient.Options{Router: a.Echo})
+
+	noUserInDbClient := testclient.TestClient{}
+	noUserInDbClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: uuid.New(),
+		}, a.Config.HMACSecret),
+	})
+
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserOneID,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("should fail if no auth token provided", func(t *testing.T) {
+		var res users.GetMeHandlerResponseBody
+		err := noTokenClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/users/me",
+			Response: &res,
+		})
+		require.Error(t, err, "got user without token")
+	})
+
+	t.Run("should fail if no user with id from token found in database", func(t *testing.T) {
+		var res users.GetMeHandlerResponseBody
+		err := noUserInDbClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/users/me",
+			Response: &res,
+		})
+		require.Error(t, err, "got not existing user")
+	})
+
+	t.Run("should get current user", func(t *testing.T) {
+		var res users.GetMeHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method:   "GET",
+			URL:      "/api/v2/users/me",
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to get current user")
+		require.Equal(t, testData.TestUserOneID, res.User.ID, "unexpected user id")
+		require.NotEmpty(t, res.User.Email, "email field was empty")
+	})
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMeHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := UsersTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserOneID,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	updatedUsername := "updated_test_user_one_users"
+	updatedAvatarURL := "https://picsum.photos/100/100"
+	invalidAvatarURL := "not a url"
+
+	t.Run("avatar should be valid url", func(t *testing.T) {
+		var res users.UpdateMeHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "PATCH",
+			URL:    "/api/v2/users/me",
+			Body: &users.UpdateMeHandlerRequestBody{
+				AvatarURL: &invalidAvatarURL,
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "passing through url check")
+	})
+
+	t.Run("should update username only", func(t *testing.T) {
+		var res users.UpdateMeHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "PATCH",
+			URL:    "/api/v2/users/me",
+			Body: &users.UpdateMeHandlerRequestBody{
+				Username: &updatedUsername,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to update user")
+		require.Equal(t, updatedUsername, res.User.Username, "unexpected username value")
+		require.Empty(t, res.User.AvatarURL, "avatar was updated")
+	})
+
+	t.Run("should update avatar", func(t *testing.T) {
+		var res users.UpdateMeHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "PATCH",
+			URL:    "/api/v2/users/me",
+			Body: &users.UpdateMeHandlerRequestBody{
+				AvatarURL: &updatedAvatarURL,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to update user")
+		require.Equal(t, updatedAvatarURL, res.User.AvatarURL, "unexpected avatar value")
+	})
+
+	t.Run("user updated in database", func(t *testing.T) {
+		var user models.User
+		err := a.MySQL.
+			Model(&models.User{}).
+			Where(&models.User{Base: models.Base{ID: testData.TestUserOneID}}).
+			First(&user).
+			Error
+		require.NoError(t, err, "failed to find user in database")
+		require.Equal(t, updatedUsername, user.Username, "unexpected username value")
+		require.Equal(t, updatedAvatarURL, user.AvatarURL, "unexpected avatar value")
+	})
+}
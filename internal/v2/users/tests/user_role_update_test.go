@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateUserRoleHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := UsersTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test clients
+	adminClient := testclient.TestClient{}
+	adminClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   testData.TestAdminID,
+			UserRole: models.UserRoleAdmin,
+		}, a.Config.HMACSecret),
+	})
+
+	userClient := testclient.TestClient{}
+	userClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID:   testData.TestUserOneID,
+			UserRole: models.UserRoleUser,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("only admin can change user role", func(t *testing.T) {
+		var res users.UpdateUserRoleHandlerResponseBody
+		err := userClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/users/%s/role", testData.TestUserOneID),
+			Body: &users.UpdateUserRoleHandlerRequestBody{
+				UserRole: string(models.UserRoleAdmin),
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "regular user changed role")
+	})
+
+	t.Run("role should be one of known roles", func(t *testing.T) {
+		var res users.UpdateUserRoleHandlerResponseBody
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/users/%s/role", testData.TestUserTwoID),
+			Body: &users.UpdateUserRoleHandlerRequestBody{
+				UserRole: "superuser",
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "passing through role check")
+	})
+
+	t.Run("admin can not change own role", func(t *testing.T) {
+		var res users.UpdateUserRoleHandlerResponseBody
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/users/%s/role", testData.TestAdminID),
+			Body: &users.UpdateUserRoleHandlerRequestBody{
+				UserRole: string(models.UserRoleUser),
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "admin changed own role")
+	})
+
+	t.Run("should error if passing id of not existing user", func(t *testing.T) {
+		var res users.UpdateUserRoleHandlerResponseBody
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/users/%s/role", uuid.New()),
+			Body: &users.UpdateUserRoleHandlerRequestBody{
+				UserRole: string(models.UserRoleModerator),
+			},
+			Response: &res,
+		})
+		require.Error(t, err, "updated not existing user")
+	})
+
+	t.Run("should update user role", func(t *testing.T) {
+		var res users.UpdateUserRoleHandlerResponseBody
+		err := adminClient.Request(&testclient.RequestOptions{
+			Method: "PUT",
+			URL:    fmt.Sprintf("/api/v2/users/%s/role", testData.TestUserTwoID),
+			Body: &users.UpdateUserRoleHandlerRequestBody{
+				UserRole: string(models.UserRoleModerator),
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "failed to update user role")
+		require.Equal(t, models.UserRoleModerator, res.User.UserRole, "unexpected user role")
+	})
+
+	t.Run("user role updated in database", func(t *testing.T) {
+		var user models.User
+		err := a.MySQL.
+			Model(&models.User{}).
+			Where(&models.User{Base: models.Base{ID: testData.TestUserTwoID}}).
+			First(&user).
+			Error
+		require.NoError(t, err, "failed to find user in database")
+		require.Equal(t, models.UserRoleModerator, user.UserRole, "unexpected user role")
+	})
+}
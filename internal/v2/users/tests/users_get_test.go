@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Tamplier2911/gorest/internal/apptest"
+	"github.com/Tamplier2911/gorest/internal/v2/users"
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/testclient"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUsersHandler(t *testing.T) {
+	// init service
+	a := apptest.New()
+
+	// init test fixtures
+	fixture := UsersTestFixtures()
+	testData, err := fixture.Setup()
+	require.NoError(t, err, "failed to setup test fixtures")
+
+	// init test client
+	testClient := testclient.TestClient{}
+	testClient.Setup(&testclient.Options{
+		Router: a.Echo,
+		Token: access.MustEncodeToken(&access.Token{
+			UserID: testData.TestUserOneID,
+		}, a.Config.HMACSecret),
+	})
+
+	defer func() {
+		// cleanup test data
+		err := fixture.Teardown()
+		require.NoError(t, err, "failed to clean up test fixtures")
+	}()
+
+	t.Run("should get users", func(t *testing.T) {
+		var res users.GetUsersHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit: 20,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.GreaterOrEqual(t, int(res.Total), testData.TotalUsers, "invalid total length")
+	})
+
+	var prevUserId uuid.UUID
+	t.Run("limit should work", func(t *testing.T) {
+		var res users.GetUsersHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit: 1,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.Len(t, *res.Users, 1, "invalid response length")
+
+		for _, u := range *res.Users {
+			prevUserId = u.ID
+		}
+	})
+
+	t.Run("offset should work", func(t *testing.T) {
+		var res users.GetUsersHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit:  1,
+				Offset: 1,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		for _, u := range *res.Users {
+			require.NotEqual(t, prevUserId, u.ID, "got same user with different offset")
+		}
+	})
+
+	t.Run("should filter users by role", func(t *testing.T) {
+		var res users.GetUsersHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit:    100,
+				UserRole: string(models.UserRoleAdmin),
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+
+		for _, u := range *res.Users {
+			require.Equal(t, models.UserRoleAdmin, u.UserRole, "unexpected user role")
+		}
+	})
+
+	t.Run("should not expose emails", func(t *testing.T) {
+		var res struct {
+			Users []map[string]interface{} `json:"users"`
+		}
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit: 20,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.NotEmpty(t, res.Users, "users are missing in response")
+		for _, u := range res.Users {
+			require.NotContains(t, u, "email", "email was exposed")
+		}
+	})
+
+	t.Run("limit should be bounded", func(t *testing.T) {
+		var res users.GetUsersHandlerResponseBody
+		err := testClient.Request(&testclient.RequestOptions{
+			Method: "GET",
+			URL:    "/api/v2/users",
+			Query: &users.GetUsersHandlerRequestQuery{
+				Limit: pagination.MaxLimit * 10,
+			},
+			Response: &res,
+		})
+		require.NoError(t, err, "unexpected response")
+		require.LessOrEqual(t, len(*res.Users), pagination.MaxLimit, "limit was not bounded")
+	})
+}
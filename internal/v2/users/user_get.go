@@ -0,0 +1,82 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of GetUserHandler
+type GetUserHandlerResponseBody struct {
+	User    *PublicUser `json:"user" xml:"user"`
+	Message string      `json:"message" xml:"message"`
+} // @name GetUserResponse
+
+// GetUserHandler godoc
+//
+// @id				GetUser
+// @Summary 		Gets user record.
+// @Description 	Gets public user record from database using provided id, email is not exposed.
+//
+// @Tags			Users
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Success 200 	{object} GetUserHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /users/{id} [GET]
+func (u *Users) GetUserHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), u.Logger.Named("GetUserHandler"))
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid
+	logger.Infow("parsing uuid from path")
+	userId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+	logger = logger.With("userId", userId)
+
+	// retreive user from database
+	logger.Infow("getting user from database")
+	user, err := u.Users.Get(c.Request().Context(), userId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user with provided id in database", "err", err)
+			return problem.NotFound("failed to find user with provided id")
+		}
+
+		logger.Errorw("failed to get user from database", "err", err)
+		return problem.Internal("failed to get user by provided id")
+	}
+	logger = logger.With("user", user)
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	publicUser := NewPublicUser(user)
+	res := GetUserHandlerResponseBody{
+		User:    &publicUser,
+		Message: "successfully retrieved user",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully retrieved user by id from database")
+	return u.ResponseWriter(c, http.StatusOK, res)
+}
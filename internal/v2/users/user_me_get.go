@@ -0,0 +1,72 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent output data of GetMeHandler
+type GetMeHandlerResponseBody struct {
+	User    *models.User `json:"user" xml:"user"`
+	Message string       `json:"message" xml:"message"`
+} // @name GetMeResponse
+
+// GetMeHandler godoc
+//
+// @id				GetMe
+// @Summary 		Gets current user record.
+// @Description 	Gets record of authenticated user from database using id from token.
+//
+// @Tags			Users
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Success 200 	{object} GetMeHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /users/me [GET]
+func (u *Users) GetMeHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), u.Logger.Named("GetMeHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// retreive user from database
+	logger.Infow("getting user from database")
+	user, err := u.Users.Get(c.Request().Context(), token.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user with id from token in database", "err", err)
+			return problem.NotFound("failed to find user")
+		}
+
+		logger.Errorw("failed to get user from database", "err", err)
+		return problem.Internal("failed to get user")
+	}
+	logger = logger.With("user", user)
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := GetMeHandlerResponseBody{
+		User:    user,
+		Message: "successfully retrieved user",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully retrieved current user from database")
+	return u.ResponseWriter(c, http.StatusOK, res)
+}
@@ -0,0 +1,122 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input data of UpdateMeHandler
+type UpdateMeHandlerRequestBody struct {
+	Username  *string `json:"username" form:"username" validate:"omitempty,min=1,max=255"`
+	AvatarURL *string `json:"avatarUrl" form:"avatarUrl" validate:"omitempty,url,max=2048"`
+} // @name UpdateMeRequest
+
+// Represent output data of UpdateMeHandler
+type UpdateMeHandlerResponseBody struct {
+	User    *models.User `json:"user" xml:"user"`
+	Message string       `json:"message" xml:"message"`
+} // @name UpdateMeResponse
+
+// UpdateMeHandler godoc
+//
+// @id				UpdateMe
+// @Summary 		Updates current user record.
+// @Description 	Updates username and avatar of authenticated user, omitted fields are left unchanged.
+//
+// @Tags			Users
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body UpdateMeHandlerRequestBody true "data"
+//
+// @Success 200 	{object} UpdateMeHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /users/me [PATCH]
+func (u *Users) UpdateMeHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), u.Logger.Named("UpdateMeHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body UpdateMeHandlerRequestBody
+	err := c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+	logger = logger.With("body", body)
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = u.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// get user from database
+	logger.Infow("getting user from database")
+	user, err := u.Users.Get(c.Request().Context(), token.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user with id from token in database", "err", err)
+			return problem.NotFound("failed to find user")
+		}
+		logger.Errorw("failed to find user in database", "err", err)
+		return problem.Internal("failed to update user")
+	}
+	logger = logger.With("user", user)
+
+	// apply provided fields only
+	changed := false
+	if body.Username != nil {
+		user.Username = *body.Username
+		changed = true
+	}
+	if body.AvatarURL != nil {
+		user.AvatarURL = *body.AvatarURL
+		changed = true
+	}
+
+	// update user in database
+	if changed {
+		logger.Infow("updating user in database", "username", user.Username, "avatarUrl", user.AvatarURL)
+		err = u.Users.Update(c.Request().Context(), user)
+		if err != nil {
+			logger.Errorw("failed to update user in database", "err", err)
+			return problem.Internal("failed to update user")
+		}
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := UpdateMeHandlerResponseBody{
+		User:    user,
+		Message: "successfully updated user",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully updated current user in database")
+	return u.ResponseWriter(c, http.StatusOK, res)
+}
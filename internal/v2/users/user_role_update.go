@@ -0,0 +1,130 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input data of UpdateUserRoleHandler
+type UpdateUserRoleHandlerRequestBody struct {
+	UserRole string `json:"userRole" form:"userRole" binding:"required" validate:"required,oneof=admin moderator user"`
+} // @name UpdateUserRoleRequest
+
+// Represent output data of UpdateUserRoleHandler
+type UpdateUserRoleHandlerResponseBody struct {
+	User    *models.User `json:"user" xml:"user"`
+	Message string       `json:"message" xml:"message"`
+} // @name UpdateUserRoleResponse
+
+// UpdateUserRoleHandler godoc
+//
+// @id				UpdateUserRole
+// @Summary 		Updates user role.
+// @Description 	Updates role of user with provided id, available for admins only.
+//
+// @Tags			Users
+//
+// @Accept json
+// @Accept application/yaml
+// @Accept application/msgpack
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+//
+// @Param fields body UpdateUserRoleHandlerRequestBody true "data"
+//
+// @Success 200 		{object} UpdateUserRoleHandlerResponseBody
+// @Failure 400,403,404 {object} problem.Problem
+// @Failure 500 		{object} problem.Problem
+// @Failure default 	{object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /users/{id}/role [PUT]
+func (u *Users) UpdateUserRoleHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), u.Logger.Named("UpdateUserRoleHandler"))
+
+	// get token from context
+	token := access.GetTokenFromContext(c)
+	logger = logger.With("token", token)
+
+	// get id from path param
+	logger.Infow("getting id from path params")
+	id := c.Param("id")
+	logger = logger.With("id", id)
+
+	// parse uuid id
+	logger.Infow("parsing uuid from path")
+	userId, err := uuid.Parse(id)
+	if err != nil {
+		logger.Errorw("failed to parse uuid", "err", err)
+		return problem.BadRequest("failed to parse uuid")
+	}
+	logger = logger.With("userId", userId)
+
+	// parse body data
+	logger.Infow("parsing request body")
+	var body UpdateUserRoleHandlerRequestBody
+	err = c.Bind(&body)
+	if err != nil {
+		logger.Errorw("failed to parse request body", "err", err)
+		return problem.BadRequest("failed to parse request body")
+	}
+	logger = logger.With("body", body)
+
+	// validate body data
+	logger.Infow("validating request body")
+	err = u.Validator.Struct(&body)
+	if err != nil {
+		logger.Errorw("failed to validate body", "err", err)
+		return problem.Validation("failed to validate body", err)
+	}
+
+	// prevent admins from locking themselves out
+	if token.UserID == userId {
+		logger.Errorw("user attempted to change own role")
+		return problem.Forbidden("can not change own role")
+	}
+
+	// get user from database
+	logger.Infow("getting user from database")
+	user, err := u.Users.Get(c.Request().Context(), userId)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			logger.Errorw("failed to find user record in database with provided id", "err", err)
+			return problem.NotFound("failed to find record with provided id")
+		}
+		logger.Errorw("failed to find user record in database", "err", err)
+		return problem.Internal("failed to update user role")
+	}
+	logger = logger.With("user", user)
+
+	// update user role in database
+	logger.Infow("updating user role in database")
+	user.UserRole = models.UserRole(body.UserRole)
+	err = u.Users.UpdateRole(c.Request().Context(), user)
+	if err != nil {
+		logger.Errorw("failed to update user role in database", "err", err)
+		return problem.Internal("failed to update user role")
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := UpdateUserRoleHandlerResponseBody{
+		User:    user,
+		Message: "successfully updated user role",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully updated user role in database")
+	return u.ResponseWriter(c, http.StatusOK, res)
+}
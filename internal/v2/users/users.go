@@ -0,0 +1,61 @@
+package users
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Users struct {
+	*service.Service
+}
+
+// Represent user record visible to other users, email is only exposed to its owner
+type PublicUser struct {
+	ID        uuid.UUID       `json:"id" xml:"id"`
+	Username  string          `json:"username" xml:"username"`
+	UserRole  models.UserRole `json:"userRole" xml:"userrole"`
+	AvatarURL string          `json:"avatarUrl" xml:"avatarurl"`
+} // @name PublicUser
+
+// NewPublicUser is used to strip private fields of user record.
+func NewPublicUser(user *models.User) PublicUser {
+	return PublicUser{
+		ID:        user.ID,
+		Username:  user.Username,
+		UserRole:  user.UserRole,
+		AvatarURL: user.AvatarURL,
+	}
+}
+
+func (u Users) Setup(s *service.Service) {
+	u.Service = s
+
+	// limit requests of route group, authenticated requests are counted by user
+	limit := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return service.RateLimitMiddleware(u.Logger, u.RateLimiter, "users", next)
+	}
+
+	// configure router
+	UsersRouter := u.Echo.Group("/api/v2/users")
+
+	UsersRouter.GET("", service.AuthenticationMiddleware(u.Logger, u.Service, u.Revocations,
+		service.ScopeMiddleware(u.Logger, []string{policy.ScopeUsersRead}, limit(u.GetUsersHandler)),
+	))
+	UsersRouter.GET("/me", service.AuthenticationMiddleware(u.Logger, u.Service, u.Revocations,
+		service.ScopeMiddleware(u.Logger, []string{policy.ScopeUsersRead}, limit(u.GetMeHandler)),
+	))
+	UsersRouter.PATCH("/me", service.AuthenticationMiddleware(u.Logger, u.Service, u.Revocations,
+		service.ScopeMiddleware(u.Logger, []string{policy.ScopeUsersWrite}, limit(u.UpdateMeHandler)),
+	))
+	UsersRouter.GET("/:id", service.AuthenticationMiddleware(u.Logger, u.Service, u.Revocations,
+		service.ScopeMiddleware(u.Logger, []string{policy.ScopeUsersRead}, limit(u.GetUserHandler)),
+	))
+	UsersRouter.PUT("/:id/role", service.AuthenticationMiddleware(u.Logger, u.Service, u.Revocations,
+		service.ScopeMiddleware(u.Logger, []string{policy.ScopeUsersWrite},
+			service.AuthorizationMiddleware(u.Logger, []models.UserRole{models.UserRoleAdmin}, limit(u.UpdateUserRoleHandler)),
+		),
+	))
+}
@@ -0,0 +1,96 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// Represent input query of GetUsersHandler
+type GetUsersHandlerRequestQuery struct {
+	Limit    int    `query:"limit"`
+	Offset   int    `query:"offset"`
+	UserRole string `query:"userRole"`
+} // @name GetUsersRequest
+
+// Represent output data of GetUsersHandler
+type GetUsersHandlerResponseBody struct {
+	Users   *[]PublicUser `json:"users" xml:"users"`
+	Total   int64         `json:"total" xml:"total"`
+	Message string        `json:"message" xml:"message"`
+} // @name GetUsersResponse
+
+// GetUsersHandler godoc
+//
+// @id				GetUsers
+// @Summary 		Gets user records.
+// @Description 	Gets public user records from database using provided query, emails are not exposed.
+//
+// @Tags			Users
+//
+// @Produce json
+// @Produce xml
+// @Produce application/yaml
+// @Produce application/msgpack
+// @Produce text/csv
+//
+// @Param fields query GetUsersHandlerRequestQuery true "data"
+//
+// @Success 200 	{object} GetUsersHandlerResponseBody
+// @Failure 400,404 {object} problem.Problem
+// @Failure 500 	{object} problem.Problem
+// @Failure default {object} problem.Problem
+//
+// @Security ApiKeyAuth
+//
+// @Router /users [GET]
+func (u *Users) GetUsersHandler(c echo.Context) error {
+	logger := tracing.Logger(c.Request().Context(), u.Logger.Named("GetUsersHandler"))
+
+	logger.Infow("parsing request query params")
+	var query GetUsersHandlerRequestQuery
+	err := c.Bind(&query)
+	if err != nil {
+		logger.Errorw("failed to parse request query", "err", err)
+		return problem.BadRequest("failed to parse request query")
+	}
+	logger = logger.With("query", query)
+
+	// filter users by role if provided
+	filter := repository.UserFilter{UserRole: models.UserRole(query.UserRole)}
+
+	// bound page size
+	limit := pagination.Limit(query.Limit)
+
+	// retreive users from database
+	logger.Infow("getting users from database")
+	users, list, err := u.Users.List(c.Request().Context(), filter, repository.ListOptions{Limit: limit, Offset: query.Offset})
+	if err != nil {
+		logger.Errorw("failed to get users from database", "err", err)
+		return problem.Internal("failed to get users")
+	}
+	logger = logger.With("users", users)
+
+	// hide private fields of other users
+	publicUsers := make([]PublicUser, 0, len(users))
+	for i := range users {
+		publicUsers = append(publicUsers, NewPublicUser(&users[i]))
+	}
+
+	// assemble response body
+	logger.Infow("assembling response body")
+	res := GetUsersHandlerResponseBody{
+		Users:   &publicUsers,
+		Total:   list.Total,
+		Message: "successfully retrieved users",
+	}
+	logger = logger.With("res", res)
+
+	logger.Infow("successfully retrieved users from database")
+	return u.ResponseWriter(c, http.StatusOK, res)
+}
@@ -0,0 +1,235 @@
+package access
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Tamplier2911/gorest/pkg/jwk"
+	"github.com/golang-jwt/jwt"
+)
+
+// minRSAKeySize is minimum size of RSA keys in bits.
+const minRSAKeySize = 2048
+
+// signingKey represent key of set, keys without private part only verify tokens.
+type signingKey struct {
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet holds RS256 and EdDSA keys by id, new tokens are signed with primary key and carry its id in kid header
+// while any key of set can verify, so retired keys stay in set until tokens signed with them expire.
+// Set without keys signs and verifies HS256 tokens with hmac secret, HS256 tokens are also accepted
+// alongside keys if hmac fallback is enabled.
+type KeySet struct {
+	primary      string
+	keys         map[string]*signingKey
+	hmacSecret   string
+	hmacFallback bool
+}
+
+// NewKeySet is used to create empty key set which signs tokens with hmac secret.
+func NewKeySet(hmacSecret string, hmacFallback bool) *KeySet {
+	return &KeySet{
+		keys:         map[string]*signingKey{},
+		hmacSecret:   hmacSecret,
+		hmacFallback: hmacFallback,
+	}
+}
+
+// ParseKeySet is used to create key set from comma separated list of id:base64 DER keys,
+// PKCS8 private keys sign and verify while PKIX public keys only verify.
+func ParseKeySet(hmacSecret string, hmacFallback bool, primary string, spec string) (*KeySet, error) {
+	set := NewKeySet(hmacSecret, hmacFallback)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed key %q, expected id:base64", item)
+		}
+		der, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed key %q: %s", parts[0], err)
+		}
+
+		var key interface{}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			key, err = x509.ParsePKIXPublicKey(der)
+			if err != nil {
+				return nil, fmt.Errorf("key %q is neither PKCS8 private key nor PKIX public key", parts[0])
+			}
+		}
+
+		err = set.Add(parts[0], key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(set.keys) == 0 {
+		return set, nil
+	}
+
+	err := set.SetPrimary(primary)
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// Add is used to add RSA or Ed25519 private or public key to set.
+func (s *KeySet) Add(kid string, key interface{}) error {
+	if kid == "" || strings.Contains(kid, ":") {
+		return fmt.Errorf("invalid key id %q", kid)
+	}
+	if _, ok := s.keys[kid]; ok {
+		return fmt.Errorf("duplicate key id %q", kid)
+	}
+
+	var k signingKey
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		k = signingKey{method: jwt.SigningMethodRS256, private: key, public: &key.PublicKey}
+	case *rsa.PublicKey:
+		k = signingKey{method: jwt.SigningMethodRS256, public: key}
+	case ed25519.PrivateKey:
+		k = signingKey{method: jwt.SigningMethodEdDSA, private: key, public: key.Public()}
+	case ed25519.PublicKey:
+		k = signingKey{method: jwt.SigningMethodEdDSA, public: key}
+	default:
+		return fmt.Errorf("unsupported type %T of key %q", key, kid)
+	}
+
+	if public, ok := k.public.(*rsa.PublicKey); ok && public.N.BitLen() < minRSAKeySize {
+		return fmt.Errorf("key %q must be at least %d bits", kid, minRSAKeySize)
+	}
+
+	s.keys[kid] = &k
+	return nil
+}
+
+// SetPrimary is used to select key which signs new tokens, key must have private part.
+func (s *KeySet) SetPrimary(kid string) error {
+	key, ok := s.keys[kid]
+	if !ok {
+		return fmt.Errorf("primary key %q is missing in set", kid)
+	}
+	if key.private == nil {
+		return fmt.Errorf("primary key %q has no private key", kid)
+	}
+
+	s.primary = kid
+	return nil
+}
+
+// Algorithm is used to get algorithm of new tokens.
+func (s *KeySet) Algorithm() string {
+	if s.primary == "" {
+		return jwt.SigningMethodHS256.Alg()
+	}
+	return s.keys[s.primary].method.Alg()
+}
+
+// Encode is used to sign token with primary key or with hmac secret if set has no keys.
+func (s *KeySet) Encode(token *Token) (string, error) {
+	if s.primary == "" {
+		return EncodeToken(token, s.hmacSecret)
+	}
+
+	key := s.keys[s.primary]
+	jwtToken := jwt.NewWithClaims(key.method, token)
+	jwtToken.Header["kid"] = s.primary
+
+	return jwtToken.SignedString(key.private)
+}
+
+// MustEncode is used to sign token ignoring error case.
+func (s *KeySet) MustEncode(token *Token) string {
+	tokenString, _ := s.Encode(token)
+
+	return tokenString
+}
+
+// Decode is used to verify and decode token signed with key of set identified by kid header.
+func (s *KeySet) Decode(tokenString string) (*Token, error) {
+	jwtToken, err := jwt.ParseWithClaims(tokenString, &Token{}, func(token *jwt.Token) (interface{}, error) {
+		// hmac tokens are accepted only without keys or with fallback enabled
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if s.primary != "" && !s.hmacFallback {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(s.hmacSecret), nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+
+		// validate signing algorithm against key
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+
+		return key.public, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	// check if token is valid
+	if !jwtToken.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	// parse claims
+	token, ok := jwtToken.Claims.(*Token)
+	if !ok {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+
+	return token, nil
+}
+
+// JWKS is used to get public keys of set ordered by id, set without keys has no public keys.
+func (s *KeySet) JWKS() jwk.Set {
+	ids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		ids = append(ids, kid)
+	}
+	sort.Strings(ids)
+
+	set := jwk.Set{Keys: make([]jwk.Key, 0, len(ids))}
+	for _, kid := range ids {
+		key, err := jwk.New(kid, s.keys[kid].method.Alg(), s.keys[kid].public)
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, key)
+	}
+
+	return set
+}
+
+// JWKSHandler is used to publish public keys of set so other services can verify tokens.
+func (s *KeySet) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(s.JWKS())
+}
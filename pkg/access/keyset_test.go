@@ -0,0 +1,218 @@
+package access
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/jwk"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const hmacSecret = "secret"
+
+// newToken is used to create valid token claims.
+func newToken() *Token {
+	return &Token{
+		UserID: uuid.New(),
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+}
+
+// newRSAKey is used to generate RSA key of provided size.
+func newRSAKey(t *testing.T, bits int) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err, "failed to generate rsa key")
+	return key
+}
+
+// newEd25519Key is used to generate Ed25519 key.
+func newEd25519Key(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate ed25519 key")
+	return key
+}
+
+// encodeKey is used to encode key as id:base64 DER item of key set spec.
+func encodeKey(t *testing.T, kid string, key interface{}) string {
+	var der []byte
+	var err error
+	switch key := key.(type) {
+	case *rsa.PublicKey, ed25519.PublicKey:
+		der, err = x509.MarshalPKIXPublicKey(key)
+	default:
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+	require.NoError(t, err, "failed to marshal key")
+	return kid + ":" + base64.StdEncoding.EncodeToString(der)
+}
+
+func TestParseKeySet(t *testing.T) {
+	rsaKey := newRSAKey(t, 2048)
+	edKey := newEd25519Key(t)
+
+	tests := []struct {
+		name    string
+		primary string
+		spec    string
+		alg     string
+		err     bool
+	}{
+		{name: "without keys", primary: "", spec: "", alg: "HS256"},
+		{name: "rsa primary", primary: "a", spec: encodeKey(t, "a", rsaKey) + "," + encodeKey(t, "b", edKey), alg: "RS256"},
+		{name: "ed25519 primary", primary: "b", spec: encodeKey(t, "a", rsaKey) + ", " + encodeKey(t, "b", edKey), alg: "EdDSA"},
+		{name: "public key as primary", primary: "a", spec: encodeKey(t, "a", &rsaKey.PublicKey), err: true},
+		{name: "missing primary", primary: "c", spec: encodeKey(t, "a", rsaKey), err: true},
+		{name: "duplicate id", primary: "a", spec: encodeKey(t, "a", rsaKey) + "," + encodeKey(t, "a", edKey), err: true},
+		{name: "short rsa key", primary: "a", spec: encodeKey(t, "a", newRSAKey(t, 1024)), err: true},
+		{name: "missing id", primary: "a", spec: base64.StdEncoding.EncodeToString([]byte("key")), err: true},
+		{name: "invalid base64", primary: "a", spec: "a:!!!", err: true},
+		{name: "invalid der", primary: "a", spec: "a:" + base64.StdEncoding.EncodeToString([]byte("key")), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := ParseKeySet(hmacSecret, false, tt.primary, tt.spec)
+			if tt.err {
+				require.Error(t, err, "key set was created")
+				return
+			}
+			require.NoError(t, err, "failed to parse key set")
+			require.Equal(t, tt.alg, set.Algorithm(), "invalid algorithm")
+		})
+	}
+}
+
+func TestKeySetDecode(t *testing.T) {
+	rsaKey := newRSAKey(t, 2048)
+	edKey := newEd25519Key(t)
+
+	set := NewKeySet(hmacSecret, false)
+	require.NoError(t, set.Add("rsa", rsaKey), "failed to add key")
+	require.NoError(t, set.Add("ed", edKey), "failed to add key")
+	require.NoError(t, set.SetPrimary("rsa"), "failed to set primary key")
+
+	fallback := NewKeySet(hmacSecret, true)
+	require.NoError(t, fallback.Add("rsa", rsaKey), "failed to add key")
+	require.NoError(t, fallback.SetPrimary("rsa"), "failed to set primary key")
+
+	// sign is used to sign token with key under provided kid header
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) string {
+		jwtToken := jwt.NewWithClaims(method, newToken())
+		jwtToken.Header["kid"] = kid
+		raw, err := jwtToken.SignedString(key)
+		require.NoError(t, err, "failed to sign token")
+		return raw
+	}
+
+	expired := newToken()
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name  string
+		set   *KeySet
+		token string
+		err   bool
+	}{
+		{name: "token of primary key", set: set, token: set.MustEncode(newToken())},
+		{name: "token of other key", set: set, token: sign(jwt.SigningMethodEdDSA, "ed", edKey)},
+		{name: "hmac token with fallback", set: fallback, token: MustEncodeToken(newToken(), hmacSecret)},
+		{name: "hmac token without fallback", set: set, token: MustEncodeToken(newToken(), hmacSecret), err: true},
+		{name: "algorithm of other key", set: set, token: sign(jwt.SigningMethodRS256, "ed", rsaKey), err: true},
+		{name: "unknown key", set: set, token: sign(jwt.SigningMethodRS256, "other", rsaKey), err: true},
+		{name: "missing key id", set: set, token: sign(jwt.SigningMethodRS256, "", rsaKey), err: true},
+		{name: "forged signature", set: set, token: sign(jwt.SigningMethodRS256, "rsa", newRSAKey(t, 2048)), err: true},
+		{name: "none algorithm", set: set, token: sign(jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType), err: true},
+		{name: "expired token", set: set, token: set.MustEncode(expired), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.set.Decode(tt.token)
+			if tt.err {
+				require.Error(t, err, "token was accepted")
+				return
+			}
+			require.NoError(t, err, "failed to decode token")
+			require.NotEqual(t, uuid.Nil, token.UserID, "invalid claims")
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldKey := newRSAKey(t, 2048)
+	newKey := newEd25519Key(t)
+
+	old, err := ParseKeySet(hmacSecret, false, "old", encodeKey(t, "old", oldKey))
+	require.NoError(t, err, "failed to parse key set")
+	issued := old.MustEncode(newToken())
+
+	// new key becomes primary while retired key only verifies
+	rotated, err := ParseKeySet(hmacSecret, false, "new", encodeKey(t, "old", &oldKey.PublicKey)+","+encodeKey(t, "new", newKey))
+	require.NoError(t, err, "failed to parse key set")
+
+	_, err = rotated.Decode(issued)
+	require.NoError(t, err, "token of retired key was rejected")
+
+	token, err := rotated.Encode(newToken())
+	require.NoError(t, err, "failed to encode token")
+	jwtToken, _, err := new(jwt.Parser).ParseUnverified(token, &Token{})
+	require.NoError(t, err, "failed to parse token")
+	require.Equal(t, "new", jwtToken.Header["kid"], "token was not signed with primary key")
+	require.Equal(t, "EdDSA", jwtToken.Header["alg"], "invalid algorithm")
+
+	// tokens of new key are rejected by services not aware of rotation
+	_, err = old.Decode(token)
+	require.Error(t, err, "token of unknown key was accepted")
+}
+
+func TestJWKSHandler(t *testing.T) {
+	rsaKey := newRSAKey(t, 2048)
+	edKey := newEd25519Key(t)
+
+	set := NewKeySet(hmacSecret, false)
+	require.NoError(t, set.Add("b", edKey), "failed to add key")
+	require.NoError(t, set.Add("a", rsaKey), "failed to add key")
+	require.NoError(t, set.SetPrimary("b"), "failed to set primary key")
+
+	recorder := httptest.NewRecorder()
+	set.JWKSHandler(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	require.Equal(t, http.StatusOK, recorder.Code, "invalid status")
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"), "invalid content type")
+
+	var published jwk.Set
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &published), "failed to decode key set")
+	require.Len(t, published.Keys, 2, "invalid number of keys")
+	require.Equal(t, "a", published.Keys[0].Kid, "keys are not ordered by id")
+	require.Equal(t, "RS256", published.Keys[0].Alg, "invalid algorithm")
+	require.Equal(t, "EdDSA", published.Keys[1].Alg, "invalid algorithm")
+	for _, key := range published.Keys {
+		require.NotEmpty(t, key.N+key.X, "key has no public part")
+	}
+
+	// published keys verify tokens signed by set
+	token := set.MustEncode(newToken())
+	_, err := jwt.ParseWithClaims(token, &Token{}, func(token *jwt.Token) (interface{}, error) {
+		key, ok := published.Find(token.Header["kid"].(string))
+		require.True(t, ok, "signing key is not published")
+		return key.PublicKey()
+	})
+	require.NoError(t, err, "failed to verify token with published key")
+
+	// set without keys publishes empty set
+	empty := NewKeySet(hmacSecret, false).JWKS()
+	require.NotNil(t, empty.Keys, "keys of empty set are null")
+	require.Empty(t, empty.Keys, "empty set has keys")
+}
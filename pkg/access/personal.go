@@ -0,0 +1,36 @@
+package access
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix marks personal access tokens so they are not parsed as JWT.
+const PersonalAccessTokenPrefix = "gorest_pat_"
+
+// personalAccessTokenSize is number of random bytes in personal access token.
+const personalAccessTokenSize = 32
+
+// NewPersonalAccessToken is used to generate personal access token and its hash which is stored in database.
+func NewPersonalAccessToken() (string, string, error) {
+	b := make([]byte, personalAccessTokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate personal access token: %s", err)
+	}
+
+	token := PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashPersonalAccessToken(token), nil
+}
+
+// IsPersonalAccessToken is used to check if credential is personal access token.
+func IsPersonalAccessToken(credential string) bool {
+	return strings.HasPrefix(credential, PersonalAccessTokenPrefix)
+}
+
+// HashPersonalAccessToken is used to hash personal access token for lookup.
+func HashPersonalAccessToken(token string) string {
+	return HashRefreshToken(token)
+}
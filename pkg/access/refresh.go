@@ -0,0 +1,30 @@
+package access
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// refreshTokenSize is number of random bytes in refresh token.
+const refreshTokenSize = 32
+
+// NewRefreshToken is used to generate opaque refresh token and its hash which is stored in database.
+func NewRefreshToken() (string, string, error) {
+	b := make([]byte, refreshTokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %s", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken is used to hash refresh token for lookup, tokens are never stored in plain text.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -13,8 +13,12 @@ import (
 type Token struct {
 	jwt.StandardClaims
 
-	UserID   uuid.UUID       `json:"userId"`
-	UserRole models.UserRole `json:"userRole"`
+	UserID    uuid.UUID       `json:"userId"`
+	UserRole  models.UserRole `json:"userRole"`
+	SessionID uuid.UUID       `json:"sessionId,omitempty"`
+
+	// scopes of personal access token, session tokens are not limited by scopes
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 // EncodeToken is used to encode JWT Token to string.
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/iamolegga/enviper"
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
@@ -17,7 +21,36 @@ type Config struct {
 
 	// base url
 	BaseURL string `mapstructure:"base_url"`
-	Port    string `mapstructure:"port"`
+
+	// listeners, port is used by v1 server and echo port by v2 server,
+	// single listener serves both versions on echo port
+	Port           string `mapstructure:"port"`
+	EchoPort       string `mapstructure:"echo_port"`
+	SingleListener bool   `mapstructure:"single_listener"`
+	UnixSocket     string `mapstructure:"unix_socket"`
+	TLSCertFile    string `mapstructure:"tls_cert_file"`
+	TLSKeyFile     string `mapstructure:"tls_key_file"`
+
+	// Client ip, comma separated CIDR ranges of proxies whose X-Forwarded-For header is trusted,
+	// peer address of connection is used if empty
+	TrustedProxies string `mapstructure:"trusted_proxies"`
+
+	// prometheus metrics served on separate listener
+	MetricsEnabled bool   `mapstructure:"metrics_enabled"`
+	MetricsPort    string `mapstructure:"metrics_port"`
+
+	// tracing, exporter is one of stdout, otlp or memory, empty disables tracing
+	TracingExporter     string  `mapstructure:"tracing_exporter"`
+	TracingOTLPEndpoint string  `mapstructure:"tracing_otlp_endpoint"`
+	TracingOTLPInsecure bool    `mapstructure:"tracing_otlp_insecure"`
+	TracingSampleRatio  float64 `mapstructure:"tracing_sample_ratio"`
+
+	// health checks
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"`
+
+	// graceful shutdown
+	ShutdownTimeout    time.Duration `mapstructure:"shutdown_timeout"`
+	ShutdownDrainDelay time.Duration `mapstructure:"shutdown_drain_delay"`
 
 	// MySQL
 	MySQLHost     string `mapstructure:"mysql_host"`
@@ -25,24 +58,115 @@ type Config struct {
 	MySQLPass     string `mapstructure:"mysql_pass"`
 	MySQLDatabase string `mapstructure:"mysql_database"`
 
+	// Schema migrations, pending migrations are applied on start while other replicas wait for lock,
+	// automigration of models is optional and meant for development
+	MigrateOnStart        bool          `mapstructure:"migrate_on_start"`
+	MigrationsLockTimeout time.Duration `mapstructure:"migrations_lock_timeout"`
+	AutoMigrate           bool          `mapstructure:"auto_migrate"`
+
+	// Redis, used by shared stores
+	RedisAddr     string `mapstructure:"redis_addr"`
+	RedisPassword string `mapstructure:"redis_password"`
+	RedisDB       int    `mapstructure:"redis_db"`
+
+	// Rate limiting, store is one of memory or redis, policies by route group name,
+	// group without own policy uses default policy, may be provided as json object in GOREST_RATE_LIMIT_POLICIES
+	RateLimitEnabled  bool                       `mapstructure:"rate_limit_enabled"`
+	RateLimitStore    string                     `mapstructure:"rate_limit_store"`
+	RateLimitPolicies map[string]RateLimitPolicy `mapstructure:"rate_limit_policies"`
+
+	// Revocation list of access tokens, store is one of memory or redis,
+	// memory store is not shared so it fits single instance deployments only
+	RevocationStore string `mapstructure:"revocation_store"`
+
+	// Search backend, one of mysql or memory
+	SearchBackend string `mapstructure:"search_backend"`
+
+	// Repository backend, one of mysql or memory, memory keeps records in process for tests
+	RepositoryBackend string `mapstructure:"repository_backend"`
+
+	// Test harness database, one of sqlite kept in memory or mysql server configured above
+	TestDatabase string `mapstructure:"test_database"`
+
+	// Validation, maximal length of post title in characters
+	PostTitleMaxLength int `mapstructure:"post_title_max_length"`
+
 	// HMAC Secret
 	HMACSecret string `mapstructure:"hmac_secret"`
 
-	// Auth
+	// Encryption of stored secrets, keys are comma separated id:base64 pairs of 32 byte keys,
+	// new values are encrypted with primary key, development key is derived from hmac secret if keys are empty
+	EncryptionKeys         string `mapstructure:"encryption_keys"`
+	EncryptionPrimaryKeyID string `mapstructure:"encryption_primary_key_id"`
+
+	// Token signing, keys are comma separated id:base64 pairs of DER keys, PKCS8 private keys sign and verify
+	// while PKIX public keys only verify, tokens are signed with hmac secret (HS256) if keys are empty
+	JWTSigningKeys  string `mapstructure:"jwt_signing_keys"`
+	JWTPrimaryKeyID string `mapstructure:"jwt_primary_key_id"`
+	JWTAcceptHS256  bool   `mapstructure:"jwt_accept_hs256"`
+
+	// Personal access tokens of machine clients expire at most after max ttl
+	PersonalAccessTokenMaxTTL time.Duration `mapstructure:"personal_access_token_max_ttl"`
+
+	// Sessions, access tokens are short lived and renewed with rotated refresh tokens
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+
+	// Auth, state of oauth login expires after ttl, post login redirect must be relative or point to base url host
+	OAuthStateTTL time.Duration `mapstructure:"oauth_state_ttl"`
+
+	// Auth, new provider is linked automatically to user with same email only if provider verified email
+	AuthAutoLink bool `mapstructure:"auth_auto_link"`
+
+	// Local auth, email verification and password reset tokens expire after ttl,
+	// login of email or ip address is blocked for throttle window after max failed attempts
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	PasswordResetTTL     time.Duration `mapstructure:"password_reset_ttl"`
+	LoginMaxAttempts     int           `mapstructure:"login_max_attempts"`
+	LoginIPMaxAttempts   int           `mapstructure:"login_ip_max_attempts"`
+	LoginThrottleWindow  time.Duration `mapstructure:"login_throttle_window"`
+
+	// Mailer backend, one of smtp or memory, memory keeps messages in process for development and tests
+	MailerBackend string `mapstructure:"mailer_backend"`
+	MailFrom      string `mapstructure:"mail_from"`
+	SMTPAddr      string `mapstructure:"smtp_addr"`
+	SMTPUsername  string `mapstructure:"smtp_username"`
+	SMTPPassword  string `mapstructure:"smtp_password"`
+
 	GoogleClientID     string `mapstructure:"google_client_id"`
 	GoogleClientSecret string `mapstructure:"google_client_secret"`
-	GoogleClientState  string `mapstructure:"google_client_state"`
 	GoogleRedirectURL  string `mapstructure:"google_redirect_url"`
 
 	FacebookClientID     string `mapstructure:"facebook_client_id"`
 	FacebookClientSecret string `mapstructure:"facebook_client_secret"`
-	FacebookClientState  string `mapstructure:"facebook_client_state"`
 	FacebookRedirectURL  string `mapstructure:"facebook_redirect_url"`
 
 	GithubClientID     string `mapstructure:"github_client_id"`
 	GithubClientSecret string `mapstructure:"github_client_secret"`
-	GithubClientState  string `mapstructure:"github_client_state"`
 	GithubRedirectURL  string `mapstructure:"github_redirect_url"`
+
+	// OpenID Connect providers by name, endpoints are discovered from issuer,
+	// may be provided in config file or as json object in GOREST_OIDC_PROVIDERS
+	OIDCProviders map[string]OIDCProvider `mapstructure:"oidc_providers"`
+}
+
+// OIDCProvider represent config of OpenID Connect provider,
+// redirect url defaults to callback of provider on base url and scopes default to openid, email and profile.
+type OIDCProvider struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// RateLimitPolicy represent token bucket allowing requests per period with burst,
+// requests are counted by authenticated user or by client ip if key is ip.
+type RateLimitPolicy struct {
+	Requests int           `mapstructure:"requests"`
+	Period   time.Duration `mapstructure:"period"`
+	Burst    int           `mapstructure:"burst"`
+	Key      string        `mapstructure:"key"`
 }
 
 func New() *Config {
@@ -64,17 +188,91 @@ func New() *Config {
 	viper.SetDefault("log_level", "info")
 	viper.SetDefault("base_url", "http://127.0.0.1")
 	viper.SetDefault("port", "8080")
+	viper.SetDefault("echo_port", "8000")
+	viper.SetDefault("single_listener", false)
+	viper.SetDefault("trusted_proxies", "")
+	viper.SetDefault("metrics_enabled", false)
+	viper.SetDefault("metrics_port", "9090")
+	viper.SetDefault("tracing_exporter", "")
+	viper.SetDefault("tracing_otlp_endpoint", "127.0.0.1:4318")
+	viper.SetDefault("tracing_otlp_insecure", true)
+	viper.SetDefault("tracing_sample_ratio", 1)
+	viper.SetDefault("health_check_timeout", "2s")
+	viper.SetDefault("shutdown_timeout", "30s")
+	viper.SetDefault("shutdown_drain_delay", "5s")
 	viper.SetDefault("mysql_host", "127.0.0.1:3306")
 	viper.SetDefault("mysql_user", "root")
 	viper.SetDefault("mysql_pass", "")
 	viper.SetDefault("mysql_database", "gorest_db")
+	viper.SetDefault("search_backend", "mysql")
+	viper.SetDefault("repository_backend", "mysql")
+	viper.SetDefault("test_database", "sqlite")
+	viper.SetDefault("post_title_max_length", 255)
+	viper.SetDefault("migrate_on_start", true)
+	viper.SetDefault("migrations_lock_timeout", time.Minute)
+	viper.SetDefault("auto_migrate", false)
+	viper.SetDefault("redis_addr", "127.0.0.1:6379")
+	viper.SetDefault("redis_db", 0)
+	viper.SetDefault("rate_limit_enabled", false)
+	viper.SetDefault("rate_limit_store", "memory")
+	viper.SetDefault("rate_limit_policies", map[string]interface{}{
+		"default":  map[string]interface{}{"requests": 300, "period": "1m", "burst": 60, "key": "user"},
+		"auth":     map[string]interface{}{"requests": 30, "period": "1m", "burst": 10, "key": "ip"},
+		"comments": map[string]interface{}{"requests": 60, "period": "1m", "burst": 10, "key": "user"},
+		"search":   map[string]interface{}{"requests": 60, "period": "1m", "burst": 20, "key": "user"},
+	})
+	viper.SetDefault("revocation_store", "memory")
+	viper.SetDefault("jwt_accept_hs256", false)
+	viper.SetDefault("personal_access_token_max_ttl", "8760h")
+	viper.SetDefault("access_token_ttl", "15m")
+	viper.SetDefault("refresh_token_ttl", "720h")
+	viper.SetDefault("oauth_state_ttl", "10m")
+	viper.SetDefault("auth_auto_link", true)
+	viper.SetDefault("email_verification_ttl", "24h")
+	viper.SetDefault("password_reset_ttl", "1h")
+	viper.SetDefault("login_max_attempts", 5)
+	viper.SetDefault("login_ip_max_attempts", 20)
+	viper.SetDefault("login_throttle_window", "15m")
+	viper.SetDefault("mailer_backend", "memory")
+	viper.SetDefault("mail_from", "gorest@localhost")
+	viper.SetDefault("smtp_addr", "127.0.0.1:25")
+
+	// maps are not bound to environment automatically
+	_ = viper.BindEnv("oidc_providers")
+	_ = viper.BindEnv("rate_limit_policies")
 
 	// read config
 	var config Config
-	err = viper.Unmarshal(&config)
+	err = viper.Unmarshal(&config, decodeHook)
 	if err != nil {
 		panic(fmt.Sprintf("failed to read config: %s", err.Error()))
 	}
 
 	return &config
 }
+
+// decodeHook extends default viper hooks with decoding of json objects.
+var decodeHook = viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	jsonToMapHookFunc,
+))
+
+// jsonToMapHookFunc is used to decode maps provided as json strings, e.g. in environment.
+func jsonToMapHookFunc(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+		return data, nil
+	}
+
+	s := strings.TrimSpace(data.(string))
+	if s == "" {
+		return map[string]interface{}{}, nil
+	}
+
+	var res map[string]interface{}
+	err := json.Unmarshal([]byte(s), &res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode json object: %s", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,218 @@
+package fulltext
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Type represent type of searchable document.
+type Type string
+
+// Document types.
+const (
+	TypePosts    Type = "posts"
+	TypeComments Type = "comments"
+)
+
+// Field represent named text of document.
+type Field struct {
+	Name string
+	Text string
+}
+
+// Document represent searchable record.
+type Document struct {
+	Type      Type
+	ID        uuid.UUID
+	ParentID  uuid.UUID
+	CreatedAt time.Time
+	Fields    []Field
+}
+
+// Query represent search request.
+type Query struct {
+	Type      Type
+	Text      string
+	Limit     int
+	Offset    int
+	SkipTotal bool
+}
+
+// Highlight represent field fragment with matched terms wrapped in mark tags.
+type Highlight struct {
+	Field    string `json:"field" xml:"field"`
+	Fragment string `json:"fragment" xml:"fragment"`
+} // @name SearchHighlight
+
+// Hit represent single ranked search result.
+type Hit struct {
+	Type       Type        `json:"type" xml:"type"`
+	ID         uuid.UUID   `json:"id" xml:"id"`
+	PostID     *uuid.UUID  `json:"postId,omitempty" xml:"postId,omitempty"`
+	Score      float64     `json:"score" xml:"score"`
+	Highlights []Highlight `json:"highlights" xml:"highlights"`
+} // @name SearchHit
+
+// Result represent page of ranked search results.
+type Result struct {
+	Hits  []Hit
+	Total int64
+	More  bool
+}
+
+// Backend represent pluggable search engine,
+// documents are kept in sync with database by callbacks installed with Register.
+type Backend interface {
+	Index(doc Document) error
+	Remove(t Type, id uuid.UUID) error
+	Search(query Query) (*Result, error)
+}
+
+// Tokenize is used to split text to lower cased words.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// queryTerms is used to tokenize query text skipping repeated words.
+func queryTerms(text string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, term := range Tokenize(text) {
+		if !seen[term] {
+			seen[term] = true
+			res = append(res, term)
+		}
+	}
+	return res
+}
+
+// fragmentSize is maximum length of highlighted fragment in runes.
+const fragmentSize = 160
+
+// Highlighter is used to wrap query terms found in text in mark tags,
+// text is html escaped and long texts are cut to fragment around first match.
+func Highlighter(text string, terms []string) (string, bool) {
+	set := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		set[term] = true
+	}
+
+	runes := []rune(text)
+
+	// find bounds of matched words
+	type span struct{ start, end int }
+	var spans []span
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		word := i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsNumber(runes[i]))
+		if word && start < 0 {
+			start = i
+		}
+		if !word && start >= 0 {
+			if set[strings.ToLower(string(runes[start:i]))] {
+				spans = append(spans, span{start, i})
+			}
+			start = -1
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	// cut fragment around first match
+	from, to := 0, len(runes)
+	if len(runes) > fragmentSize {
+		from = spans[0].start - fragmentSize/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + fragmentSize
+		if to > len(runes) {
+			to = len(runes)
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString("</mark>")
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String(), true
+}
+
+// newHit is used to build hit with highlights of fields matching terms.
+func newHit(doc Document, score float64, terms []string) Hit {
+	hit := Hit{
+		Type:       doc.Type,
+		ID:         doc.ID,
+		Score:      score,
+		Highlights: []Highlight{},
+	}
+	if doc.Type == TypeComments {
+		postID := doc.ParentID
+		hit.PostID = &postID
+	}
+
+	for _, field := range doc.Fields {
+		fragment, ok := Highlighter(field.Text, terms)
+		if ok {
+			hit.Highlights = append(hit.Highlights, Highlight{Field: field.Name, Fragment: fragment})
+		}
+	}
+
+	return hit
+}
+
+// cursor represent position in ranked results.
+type cursor struct {
+	Offset int `json:"offset"`
+}
+
+// EncodeCursor is used to encode results offset to opaque string.
+func EncodeCursor(offset int) string {
+	b, _ := json.Marshal(cursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor is used to decode results offset from opaque string.
+func DecodeCursor(s string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cursor: %s", err)
+	}
+
+	var c cursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return 0, fmt.Errorf("malformed cursor: %s", err)
+	}
+	if c.Offset < 0 {
+		return 0, fmt.Errorf("malformed cursor: negative offset")
+	}
+
+	return c.Offset, nil
+}
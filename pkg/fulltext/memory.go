@@ -0,0 +1,171 @@
+package fulltext
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Memory is in-process inverted index backend, useful for tests and single instance deployments.
+type Memory struct {
+	mu sync.RWMutex
+
+	// documents by type and id
+	docs map[Type]map[uuid.UUID]*Document
+	// term frequencies by type, term and document id
+	postings map[Type]map[string]map[uuid.UUID]int
+}
+
+// NewMemory is used to create empty in-process index.
+func NewMemory() *Memory {
+	return &Memory{
+		docs:     map[Type]map[uuid.UUID]*Document{},
+		postings: map[Type]map[string]map[uuid.UUID]int{},
+	}
+}
+
+// Load is used to index all posts and comments stored in database.
+func (m *Memory) Load(db *gorm.DB) error {
+	var posts []models.Post
+	err := db.Model(&models.Post{}).Find(&posts).Error
+	if err != nil {
+		return fmt.Errorf("failed to load posts: %s", err)
+	}
+	for i := range posts {
+		m.Index(PostDocument(&posts[i]))
+	}
+
+	var comments []models.Comment
+	err = db.Model(&models.Comment{}).Find(&comments).Error
+	if err != nil {
+		return fmt.Errorf("failed to load comments: %s", err)
+	}
+	for i := range comments {
+		m.Index(CommentDocument(&comments[i]))
+	}
+
+	return nil
+}
+
+// Index is used to add or replace document in index.
+func (m *Memory) Index(doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.Type, doc.ID)
+
+	if m.docs[doc.Type] == nil {
+		m.docs[doc.Type] = map[uuid.UUID]*Document{}
+		m.postings[doc.Type] = map[string]map[uuid.UUID]int{}
+	}
+	m.docs[doc.Type][doc.ID] = &doc
+
+	for _, field := range doc.Fields {
+		for _, term := range Tokenize(field.Text) {
+			if m.postings[doc.Type][term] == nil {
+				m.postings[doc.Type][term] = map[uuid.UUID]int{}
+			}
+			m.postings[doc.Type][term][doc.ID]++
+		}
+	}
+
+	return nil
+}
+
+// Remove is used to drop document from index, removing post also drops its comments.
+func (m *Memory) Remove(t Type, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(t, id)
+
+	if t == TypePosts {
+		for _, doc := range m.docs[TypeComments] {
+			if doc.ParentID == id {
+				m.remove(TypeComments, doc.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// remove expects write lock to be held.
+func (m *Memory) remove(t Type, id uuid.UUID) {
+	doc, ok := m.docs[t][id]
+	if !ok {
+		return
+	}
+
+	for _, field := range doc.Fields {
+		for _, term := range Tokenize(field.Text) {
+			delete(m.postings[t][term], id)
+			if len(m.postings[t][term]) == 0 {
+				delete(m.postings[t], term)
+			}
+		}
+	}
+	delete(m.docs[t], id)
+}
+
+// Search is used to rank documents matching any of query terms by tf-idf.
+func (m *Memory) Search(query Query) (*Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	terms := queryTerms(query.Text)
+
+	// accumulate scores
+	total := float64(len(m.docs[query.Type]))
+	scores := map[uuid.UUID]float64{}
+	for _, term := range terms {
+		postings := m.postings[query.Type][term]
+		idf := math.Log(1 + total/float64(len(postings)+1))
+		for id, tf := range postings {
+			scores[id] += float64(tf) * idf
+		}
+	}
+
+	// order by score then by recency
+	docs := make([]*Document, 0, len(scores))
+	for id := range scores {
+		docs = append(docs, m.docs[query.Type][id])
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if scores[docs[i].ID] != scores[docs[j].ID] {
+			return scores[docs[i].ID] > scores[docs[j].ID]
+		}
+		if !docs[i].CreatedAt.Equal(docs[j].CreatedAt) {
+			return docs[i].CreatedAt.After(docs[j].CreatedAt)
+		}
+		return docs[i].ID.String() > docs[j].ID.String()
+	})
+
+	res := Result{}
+	if !query.SkipTotal {
+		res.Total = int64(len(docs))
+	}
+
+	// cut page
+	if query.Offset >= len(docs) {
+		docs = nil
+	} else {
+		docs = docs[query.Offset:]
+	}
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		res.More = true
+	}
+
+	res.Hits = make([]Hit, 0, len(docs))
+	for _, doc := range docs {
+		res.Hits = append(res.Hits, newHit(*doc, scores[doc.ID], terms))
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,114 @@
+package fulltext
+
+import (
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MySQL is backend which uses FULLTEXT indexes of posts and comments tables,
+// tables are source of truth so indexing is done by database itself.
+type MySQL struct {
+	db *gorm.DB
+}
+
+// NewMySQL is used to create MySQL backend.
+func NewMySQL(db *gorm.DB) *MySQL {
+	return &MySQL{db: db}
+}
+
+// matchColumns represent columns covered by FULLTEXT index of each document type,
+// indexes are created by versioned migration.
+var matchColumns = map[Type]string{
+	TypePosts:    "title, body",
+	TypeComments: "name, body",
+}
+
+// Index is no-op as FULLTEXT indexes are maintained by database.
+func (m *MySQL) Index(doc Document) error {
+	return nil
+}
+
+// Remove is no-op as FULLTEXT indexes are maintained by database.
+func (m *MySQL) Remove(t Type, id uuid.UUID) error {
+	return nil
+}
+
+// Search is used to rank records with MATCH ... AGAINST in natural language mode.
+func (m *MySQL) Search(query Query) (*Result, error) {
+	var model interface{}
+	switch query.Type {
+	case TypePosts:
+		model = &models.Post{}
+	case TypeComments:
+		model = &models.Comment{}
+	default:
+		return nil, fmt.Errorf("unknown document type %q", query.Type)
+	}
+
+	match := fmt.Sprintf("MATCH(%s) AGAINST (? IN NATURAL LANGUAGE MODE)", matchColumns[query.Type])
+	stmt := m.db.Model(model).Where(match, query.Text)
+
+	res := Result{}
+	if !query.SkipTotal {
+		err := stmt.Session(&gorm.Session{}).Count(&res.Total).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count matches: %s", err)
+		}
+	}
+
+	// fetch one extra row to detect next page
+	page := stmt.
+		Select(fmt.Sprintf("*, %s AS score", match), query.Text).
+		Order("score DESC").
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(query.Limit + 1).
+		Offset(query.Offset)
+
+	terms := queryTerms(query.Text)
+	var docs []Document
+	var scores []float64
+	switch query.Type {
+	case TypePosts:
+		var rows []struct {
+			models.Post
+			Score float64 `gorm:"column:score"`
+		}
+		err := page.Find(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to search posts: %s", err)
+		}
+		for i := range rows {
+			docs = append(docs, PostDocument(&rows[i].Post))
+			scores = append(scores, rows[i].Score)
+		}
+	case TypeComments:
+		var rows []struct {
+			models.Comment
+			Score float64 `gorm:"column:score"`
+		}
+		err := page.Find(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to search comments: %s", err)
+		}
+		for i := range rows {
+			docs = append(docs, CommentDocument(&rows[i].Comment))
+			scores = append(scores, rows[i].Score)
+		}
+	}
+
+	if len(docs) > query.Limit {
+		docs = docs[:query.Limit]
+		res.More = true
+	}
+
+	res.Hits = make([]Hit, 0, len(docs))
+	for i, doc := range docs {
+		res.Hits = append(res.Hits, newHit(doc, scores[i], terms))
+	}
+
+	return &res, nil
+}
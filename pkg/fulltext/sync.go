@@ -0,0 +1,112 @@
+package fulltext
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PostDocument is used to convert post to searchable document.
+func PostDocument(post *models.Post) Document {
+	return Document{
+		Type:      TypePosts,
+		ID:        post.ID,
+		CreatedAt: post.CreatedAt,
+		Fields: []Field{
+			{Name: "title", Text: post.Title},
+			{Name: "body", Text: post.Body},
+		},
+	}
+}
+
+// CommentDocument is used to convert comment to searchable document.
+func CommentDocument(comment *models.Comment) Document {
+	return Document{
+		Type:      TypeComments,
+		ID:        comment.ID,
+		ParentID:  comment.PostID,
+		CreatedAt: comment.CreatedAt,
+		Fields: []Field{
+			{Name: "name", Text: comment.Name},
+			{Name: "body", Text: comment.Body},
+		},
+	}
+}
+
+// Register is used to install gorm callbacks which keep backend in sync
+// with posts and comments created, updated and deleted through provided connection.
+func Register(db *gorm.DB, backend Backend) error {
+	index := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		each(tx.Statement.ReflectValue, func(doc Document) {
+			err := backend.Index(doc)
+			if err != nil {
+				tx.Logger.Error(tx.Statement.Context, "failed to index document: %s", err)
+			}
+		})
+	}
+
+	remove := func(tx *gorm.DB) {
+		if tx.Error != nil {
+			return
+		}
+		each(tx.Statement.ReflectValue, func(doc Document) {
+			err := backend.Remove(doc.Type, doc.ID)
+			if err != nil {
+				tx.Logger.Error(tx.Statement.Context, "failed to remove document: %s", err)
+			}
+		})
+	}
+
+	err := db.Callback().Create().After("gorm:create").Register("fulltext:index_create", index)
+	if err != nil {
+		return fmt.Errorf("failed to register create callback: %s", err)
+	}
+	err = db.Callback().Update().After("gorm:update").Register("fulltext:index_update", index)
+	if err != nil {
+		return fmt.Errorf("failed to register update callback: %s", err)
+	}
+	err = db.Callback().Delete().After("gorm:delete").Register("fulltext:remove", remove)
+	if err != nil {
+		return fmt.Errorf("failed to register delete callback: %s", err)
+	}
+
+	return nil
+}
+
+// each is used to call fn with documents of posts and comments found in statement value,
+// records without primary key are skipped.
+func each(value reflect.Value, fn func(doc Document)) {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			each(value.Index(i), fn)
+		}
+		return
+	case reflect.Ptr:
+		if !value.IsNil() {
+			each(value.Elem(), fn)
+		}
+		return
+	}
+
+	if !value.CanAddr() {
+		return
+	}
+
+	switch record := value.Addr().Interface().(type) {
+	case *models.Post:
+		if record.ID != uuid.Nil {
+			fn(PostDocument(record))
+		}
+	case *models.Comment:
+		if record.ID != uuid.Nil {
+			fn(CommentDocument(record))
+		}
+	}
+}
@@ -3,16 +3,34 @@ module github.com/Tamplier2911/gorest/pkg
 go 1.16
 
 require (
+	github.com/alicebob/miniredis/v2 v2.15.1
 	github.com/go-playground/validator/v10 v10.9.0
+	github.com/go-redis/redis/v8 v8.11.5
 	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/google/uuid v1.1.2
 	github.com/gorilla/schema v1.2.0
 	github.com/iamolegga/enviper v1.2.1
 	github.com/jarcoal/httpmock v1.0.8
 	github.com/labstack/echo/v4 v4.5.0
+	github.com/mitchellh/mapstructure v1.4.1
+	github.com/prometheus/client_golang v1.11.0
 	github.com/spf13/viper v1.8.1
+	github.com/stretchr/testify v1.7.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho v0.25.0
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.25.0
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.1
+	go.opentelemetry.io/otel/exporters/stdout/stdouttrace v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
 	go.uber.org/zap v1.17.0
-	gorm.io/driver/mysql v1.1.1
-	gorm.io/gorm v1.21.12
+	golang.org/x/crypto v0.0.0-20210813211128-0a44fdfbc16e
+	golang.org/x/oauth2 v0.0.0-20210402161424-2e8d93401602
+	gopkg.in/yaml.v2 v2.4.0
+	gorm.io/driver/mysql v1.3.4
+	gorm.io/driver/sqlite v1.3.6
+	gorm.io/gorm v1.23.8
 	moul.io/zapgorm2 v1.1.0
 )
@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/version"
+	"go.uber.org/zap"
+)
+
+// Checker is used to verify that dependency is available.
+type Checker func(ctx context.Context) error
+
+// Statuses of service and its dependencies.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusFail        = "fail"
+)
+
+// CheckResult represent result of single dependency check, failure details are only logged.
+type CheckResult struct {
+	Name   string `json:"name" xml:"name"`
+	Status string `json:"status" xml:"status"`
+} // @name HealthCheckResult
+
+// Report represent aggregated health of service.
+type Report struct {
+	Status string        `json:"status" xml:"status"`
+	Checks []CheckResult `json:"checks,omitempty" xml:"checks,omitempty"`
+} // @name HealthReport
+
+type check struct {
+	name    string
+	checker Checker
+}
+
+// Registry holds readiness checkers contributed by service subsystems.
+type Registry struct {
+	logger  *zap.SugaredLogger
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []check
+}
+
+// NewRegistry is used to create registry which bounds each check with provided timeout.
+func NewRegistry(logger *zap.SugaredLogger, timeout time.Duration) *Registry {
+	return &Registry{logger: logger, timeout: timeout}
+}
+
+// Register is used to add named checker.
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, check{name: name, checker: checker})
+}
+
+// Check is used to run all checkers concurrently, service is ok only if every check passes.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]check, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	return report
+}
+
+// run is used to call checker within registry timeout.
+func (r *Registry) run(ctx context.Context, c check) CheckResult {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	// checker may ignore context, so wait for whichever comes first
+	done := make(chan error, 1)
+	go func() {
+		done <- c.checker(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out: %s", ctx.Err())
+	}
+
+	if err != nil {
+		r.logger.Warnw("readiness check failed", "check", c.name, "err", err)
+		return CheckResult{Name: c.name, Status: StatusFail}
+	}
+	return CheckResult{Name: c.name, Status: StatusOK}
+}
+
+// LivenessHandler reports that process is able to serve requests.
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	write(w, http.StatusOK, Report{Status: StatusOK})
+}
+
+// ReadinessHandler reports results of registered checks, responds with 503 if any check fails.
+func (r *Registry) ReadinessHandler(w http.ResponseWriter, req *http.Request) {
+	report := r.Check(req.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	write(w, status, report)
+}
+
+// VersionHandler reports build information of running binary.
+func VersionHandler(w http.ResponseWriter, r *http.Request) {
+	write(w, http.StatusOK, version.Get())
+}
+
+// write is used to write json response with disabled caching.
+func write(w http.ResponseWriter, status int, res interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,111 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate rsa key")
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed to generate ec key")
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate ed25519 key")
+
+	tests := []struct {
+		name string
+		alg  string
+		key  crypto.PublicKey
+	}{
+		{name: "rsa key", alg: "RS256", key: &rsaKey.PublicKey},
+		{name: "ec key", alg: "ES256", key: &ecKey.PublicKey},
+		{name: "ed25519 key", alg: "EdDSA", key: edKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := New("kid", tt.alg, tt.key)
+			require.NoError(t, err, "failed to encode key")
+
+			// key survives json round trip
+			b, err := json.Marshal(key)
+			require.NoError(t, err, "failed to marshal key")
+			var decoded Key
+			require.NoError(t, json.Unmarshal(b, &decoded), "failed to unmarshal key")
+			require.Equal(t, key, decoded, "key differs after round trip")
+
+			public, err := decoded.PublicKey()
+			require.NoError(t, err, "failed to decode key")
+			require.Equal(t, tt.key, public, "decoded key differs")
+		})
+	}
+}
+
+func TestPublicKeyMalformed(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed to generate ec key")
+	valid, err := New("kid", "ES256", &ecKey.PublicKey)
+	require.NoError(t, err, "failed to encode key")
+
+	// y of other key does not match x of this one
+	ecOther, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed to generate ec key")
+	other, err := New("kid", "ES256", &ecOther.PublicKey)
+	require.NoError(t, err, "failed to encode key")
+	offCurve := valid
+	offCurve.Y = other.Y
+
+	tests := []struct {
+		name string
+		key  Key
+	}{
+		{name: "unsupported type", key: Key{Kty: "oct"}},
+		{name: "rsa without modulus", key: Key{Kty: "RSA", E: "AQAB"}},
+		{name: "rsa with malformed exponent", key: Key{Kty: "RSA", N: "AQAB", E: "!!!"}},
+		{name: "rsa with huge exponent", key: Key{Kty: "RSA", N: "AQAB", E: "AQAAAAAA"}},
+		{name: "unsupported curve", key: Key{Kty: "EC", Crv: "P-224", X: valid.X, Y: valid.Y}},
+		{name: "point off curve", key: offCurve},
+		{name: "ed25519 with short key", key: Key{Kty: "OKP", Crv: "Ed25519", X: "AQAB"}},
+		{name: "unsupported okp curve", key: Key{Kty: "OKP", Crv: "X25519", X: valid.X}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.key.PublicKey()
+			require.Error(t, err, "malformed key was decoded")
+		})
+	}
+}
+
+func TestSetFind(t *testing.T) {
+	single := Set{Keys: []Key{{Kid: "a"}}}
+	several := Set{Keys: []Key{{Kid: "a"}, {Kid: "b"}}}
+
+	tests := []struct {
+		name  string
+		set   Set
+		kid   string
+		found bool
+	}{
+		{name: "known id", set: several, kid: "b", found: true},
+		{name: "unknown id", set: several, kid: "c", found: false},
+		{name: "missing id of single key", set: single, kid: "", found: true},
+		{name: "missing id of several keys", set: several, kid: "", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, found := tt.set.Find(tt.kid)
+			require.Equal(t, tt.found, found, "unexpected lookup result")
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values, values without it are treated as legacy plaintext.
+const prefix = "enc:"
+
+// KeySize is size of AES-256 key in bytes.
+const KeySize = 32
+
+// ErrUnknownKey is returned when value was encrypted with key missing from ring.
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// KeyRing holds AES-GCM keys by id, new values are encrypted with primary key
+// while any key of ring can decrypt, so old keys stay in ring until rows are rekeyed.
+type KeyRing struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// New is used to create key ring from raw keys by id.
+func New(primary string, keys map[string][]byte) (*KeyRing, error) {
+	ring := &KeyRing{
+		primary: primary,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("key %q must be %d bytes", id, KeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher of key %q: %s", id, err)
+		}
+		ring.keys[id], err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gcm of key %q: %s", id, err)
+		}
+	}
+
+	if _, ok := ring.keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q is missing in ring", primary)
+	}
+
+	return ring, nil
+}
+
+// Parse is used to create key ring from comma separated list of id:base64 keys.
+func Parse(primary string, spec string) (*KeyRing, error) {
+	keys := map[string][]byte{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed key %q, expected id:base64", item)
+		}
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed key %q: %s", parts[0], err)
+		}
+		keys[parts[0]] = key
+	}
+
+	return New(primary, keys)
+}
+
+// Derive is used to create single key ring from secret, meant for development only.
+func Derive(id string, secret string) (*KeyRing, error) {
+	key := sha256.Sum256([]byte(secret))
+	return New(id, map[string][]byte{id: key[:]})
+}
+
+// Primary is used to get id of key used for encryption.
+func (r *KeyRing) Primary() string {
+	return r.primary
+}
+
+// Encrypt is used to encrypt value with primary key, result has form enc:<key id>:<base64 nonce and ciphertext>.
+func (r *KeyRing) Encrypt(plaintext string) (string, error) {
+	aead := r.keys[r.primary]
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %s", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(r.primary))
+	return prefix + r.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt is used to decrypt value with key it was encrypted with, legacy plaintext is returned as is.
+func (r *KeyRing) Decrypt(value string) (string, error) {
+	id, ok := KeyID(value)
+	if !ok {
+		return value, nil
+	}
+
+	aead, ok := r.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(value[len(prefix)+len(id)+1:])
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext: %s", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed ciphertext: too short")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %s", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// KeyID is used to get id of key value was encrypted with, false is returned for plaintext.
+func KeyID(value string) (string, bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return "", false
+	}
+
+	rest := value[len(prefix):]
+	i := strings.Index(rest, ":")
+	if i <= 0 {
+		return "", false
+	}
+
+	return rest[:i], true
+}
+
+// NeedsRekey is used to check if value is plaintext or encrypted with key other than primary.
+func (r *KeyRing) NeedsRekey(value string) bool {
+	if value == "" {
+		return false
+	}
+	id, ok := KeyID(value)
+	return !ok || id != r.primary
+}
+
+// Rotate is used to create ring with additional key as primary, existing keys are kept for decryption.
+func (r *KeyRing) Rotate(id string, key []byte) (*KeyRing, error) {
+	next, err := New(id, map[string][]byte{id: key})
+	if err != nil {
+		return nil, err
+	}
+
+	for kid, aead := range r.keys {
+		if kid != id {
+			next.keys[kid] = aead
+		}
+	}
+
+	return next, nil
+}
@@ -0,0 +1,181 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// key is used to create key of ring filled with byte.
+func key(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestDecrypt(t *testing.T) {
+	ring, err := New("a", map[string][]byte{"a": key(1), "b": key(2)})
+	require.NoError(t, err, "failed to create ring")
+	encrypted, err := ring.Encrypt("token")
+	require.NoError(t, err, "failed to encrypt value")
+
+	// value of key a relabeled as other key holding same bytes, key id is authenticated
+	relabeled, err := New("c", map[string][]byte{"a": key(1), "c": key(1)})
+	require.NoError(t, err, "failed to create ring")
+
+	body := strings.TrimPrefix(encrypted, "enc:a:")
+	sealed, err := base64.RawStdEncoding.DecodeString(body)
+	require.NoError(t, err, "failed to decode value")
+	sealed[len(sealed)-1] ^= 1
+
+	tests := []struct {
+		name  string
+		ring  *KeyRing
+		value string
+		want  string
+		err   bool
+	}{
+		{name: "encrypted value", ring: ring, value: encrypted, want: "token"},
+		{name: "legacy plaintext", ring: ring, value: "token", want: "token"},
+		{name: "key id mismatch", ring: relabeled, value: "enc:c:" + body, err: true},
+		{name: "wrong key", ring: ring, value: "enc:b:" + body, err: true},
+		{name: "unknown key", ring: ring, value: "enc:x:" + body, err: true},
+		{name: "tampered ciphertext", ring: ring, value: "enc:a:" + base64.RawStdEncoding.EncodeToString(sealed), err: true},
+		{name: "truncated ciphertext", ring: ring, value: "enc:a:AAAA", err: true},
+		{name: "malformed ciphertext", ring: ring, value: "enc:a:!!!", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := tt.ring.Decrypt(tt.value)
+			if tt.err {
+				require.Error(t, err, "value was decrypted")
+				return
+			}
+			require.NoError(t, err, "failed to decrypt value")
+			require.Equal(t, tt.want, plaintext, "invalid plaintext")
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(key(1))
+
+	tests := []struct {
+		name    string
+		primary string
+		spec    string
+		err     bool
+	}{
+		{name: "single key", primary: "a", spec: "a:" + encoded},
+		{name: "several keys", primary: "b", spec: " a:" + encoded + ", b:" + encoded + ","},
+		{name: "missing primary", primary: "c", spec: "a:" + encoded, err: true},
+		{name: "missing id", primary: "a", spec: encoded, err: true},
+		{name: "invalid base64", primary: "a", spec: "a:!!!", err: true},
+		{name: "short key", primary: "a", spec: "a:" + base64.StdEncoding.EncodeToString(key(1)[:16]), err: true},
+		{name: "empty id", primary: "", spec: ":" + encoded, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring, err := Parse(tt.primary, tt.spec)
+			if tt.err {
+				require.Error(t, err, "ring was created")
+				return
+			}
+			require.NoError(t, err, "failed to parse ring")
+			require.Equal(t, tt.primary, ring.Primary(), "invalid primary key")
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	old, err := New("a", map[string][]byte{"a": key(1)})
+	require.NoError(t, err, "failed to create ring")
+	encrypted, err := old.Encrypt("token")
+	require.NoError(t, err, "failed to encrypt value")
+
+	ring, err := old.Rotate("b", key(2))
+	require.NoError(t, err, "failed to rotate ring")
+	require.Equal(t, "b", ring.Primary(), "rotated key is not primary")
+
+	// old values are still readable but need rekey
+	plaintext, err := ring.Decrypt(encrypted)
+	require.NoError(t, err, "failed to decrypt value of old key")
+	require.Equal(t, "token", plaintext, "invalid plaintext")
+	require.True(t, ring.NeedsRekey(encrypted), "value of old key does not need rekey")
+
+	encrypted, err = ring.Encrypt("token")
+	require.NoError(t, err, "failed to encrypt value")
+	id, _ := KeyID(encrypted)
+	require.Equal(t, "b", id, "value was not encrypted with primary key")
+	require.False(t, ring.NeedsRekey(encrypted), "value of primary key needs rekey")
+
+	// old ring is kept as is
+	_, err = old.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrUnknownKey, "old ring knows rotated key")
+}
+
+func TestRekey(t *testing.T) {
+	old, err := New("a", map[string][]byte{"a": key(1)})
+	require.NoError(t, err, "failed to create ring")
+	ring, err := old.Rotate("b", key(2))
+	require.NoError(t, err, "failed to rotate ring")
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:keyring_%s?mode=memory&cache=shared", uuid.New())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to open database")
+	err = db.Exec("CREATE TABLE secrets (id varchar(36) PRIMARY KEY, value text NOT NULL)").Error
+	require.NoError(t, err, "failed to create table")
+
+	ofOld, err := old.Encrypt("old")
+	require.NoError(t, err, "failed to encrypt value")
+	ofPrimary, err := ring.Encrypt("primary")
+	require.NoError(t, err, "failed to encrypt value")
+
+	rows := []struct {
+		id        string
+		value     string
+		plaintext string
+		updated   bool
+	}{
+		{id: "1", value: "plain", plaintext: "plain", updated: true},
+		{id: "2", value: ofOld, plaintext: "old", updated: true},
+		{id: "3", value: ofPrimary, plaintext: "primary", updated: false},
+		{id: "4", value: "", plaintext: "", updated: false},
+	}
+	for _, row := range rows {
+		err := db.Exec("INSERT INTO secrets (id, value) VALUES (?, ?)", row.id, row.value).Error
+		require.NoError(t, err, "failed to insert row")
+	}
+
+	updated, err := ring.Rekey(db, "secrets", "value")
+	require.NoError(t, err, "failed to rekey")
+	require.Equal(t, int64(2), updated, "invalid number of updated rows")
+
+	for _, row := range rows {
+		t.Run(row.id, func(t *testing.T) {
+			var value string
+			err := db.Raw("SELECT value FROM secrets WHERE id = ?", row.id).Scan(&value).Error
+			require.NoError(t, err, "failed to read row")
+
+			require.Equal(t, row.updated, value != row.value, "unexpected update of row")
+			require.False(t, ring.NeedsRekey(value), "row still needs rekey")
+			plaintext, err := ring.Decrypt(value)
+			require.NoError(t, err, "failed to decrypt row")
+			require.Equal(t, row.plaintext, plaintext, "invalid plaintext of row")
+		})
+	}
+
+	// rekey of up to date table is noop
+	updated, err = ring.Rekey(db, "secrets", "value")
+	require.NoError(t, err, "failed to rekey")
+	require.Zero(t, updated, "rows were updated twice")
+}
@@ -0,0 +1,56 @@
+package keyring
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// rekeyBatchSize is number of rows loaded at once by Rekey.
+const rekeyBatchSize = 100
+
+// Rekey is used to re-encrypt column of table with primary key, rows holding plaintext
+// or values encrypted with other keys are updated, returns number of updated rows.
+// Raw column values are read and written so serializer is bypassed.
+func (r *KeyRing) Rekey(db *gorm.DB, table string, column string) (int64, error) {
+	type row struct {
+		ID    string
+		Value string
+	}
+
+	var updated int64
+	var rows []row
+	res := db.
+		Table(table).
+		Select(fmt.Sprintf("id, %s AS value", column)).
+		Where(fmt.Sprintf("%s <> ''", column)).
+		FindInBatches(&rows, rekeyBatchSize, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				if !r.NeedsRekey(row.Value) {
+					continue
+				}
+
+				plaintext, err := r.Decrypt(row.Value)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt %s of %s: %s", column, row.ID, err)
+				}
+				ciphertext, err := r.Encrypt(plaintext)
+				if err != nil {
+					return fmt.Errorf("failed to encrypt %s of %s: %s", column, row.ID, err)
+				}
+
+				err = db.
+					Table(table).
+					Where(fmt.Sprintf("id = ? AND %s = ?", column), row.ID, row.Value).
+					UpdateColumn(column, ciphertext).
+					Error
+				if err != nil {
+					return fmt.Errorf("failed to update %s of %s: %s", column, row.ID, err)
+				}
+				updated++
+			}
+			return nil
+		})
+
+	return updated, res.Error
+}
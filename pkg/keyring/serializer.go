@@ -0,0 +1,53 @@
+package keyring
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is name used in gorm tags, e.g. `gorm:"serializer:encrypted"`.
+const SerializerName = "encrypted"
+
+// Serializer is gorm serializer which encrypts string fields on write and decrypts them on read.
+type Serializer struct {
+	Ring *KeyRing
+}
+
+// Register is used to register serializer of ring, must be called before models are parsed.
+func Register(ring *KeyRing) {
+	schema.RegisterSerializer(SerializerName, Serializer{Ring: ring})
+}
+
+// Scan implements serializer interface.
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("failed to decrypt %s: unsupported value %T", field.Name, dbValue)
+	}
+
+	plaintext, err := s.Ring.Decrypt(value)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+	}
+
+	return field.Set(ctx, dst, plaintext)
+}
+
+// Value implements serializer interface.
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to encrypt %s: unsupported value %T", field.Name, fieldValue)
+	}
+
+	return s.Ring.Encrypt(plaintext)
+}
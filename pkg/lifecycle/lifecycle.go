@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Hook represent named component with start and stop callbacks,
+// hooks are started in order of registration and stopped in reverse order.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+
+	// optional stop timeout bounded by shutdown timeout
+	Timeout time.Duration
+}
+
+// Options is used to parameterize Lifecycle.
+type Options struct {
+	// ShutdownTimeout bounds time spent in stop hooks
+	ShutdownTimeout time.Duration
+	// DrainDelay is time between flipping readiness and stopping hooks,
+	// it lets load balancers notice instance is not ready before connections are drained
+	DrainDelay time.Duration
+}
+
+// Lifecycle manages ordered startup and graceful shutdown of service components.
+type Lifecycle struct {
+	logger  *zap.SugaredLogger
+	options Options
+
+	mu     sync.Mutex
+	hooks  []Hook
+	ready  int32
+	errors chan error
+}
+
+// New is used to create lifecycle manager.
+func New(logger *zap.SugaredLogger, options Options) *Lifecycle {
+	return &Lifecycle{
+		logger:  logger,
+		options: options,
+		errors:  make(chan error, 1),
+	}
+}
+
+// Append is used to register hook.
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+// Ready reports if all hooks started and shutdown was not initiated.
+func (l *Lifecycle) Ready() bool {
+	return atomic.LoadInt32(&l.ready) == 1
+}
+
+// Fail is used by running components to report fatal error which initiates shutdown.
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.errors <- err:
+	default:
+		// shutdown is already initiated
+	}
+}
+
+// Run is used to start hooks, wait until context is done or component fails and stop started hooks.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := make([]Hook, len(l.hooks))
+	copy(hooks, l.hooks)
+	l.mu.Unlock()
+
+	// start hooks in order
+	var runErr error
+	started := 0
+	for _, hook := range hooks {
+		if hook.OnStart != nil {
+			l.logger.Infow("starting component", "component", hook.Name)
+			err := hook.OnStart(ctx)
+			if err != nil {
+				runErr = fmt.Errorf("failed to start %s: %s", hook.Name, err)
+				break
+			}
+		}
+		started++
+	}
+
+	if runErr == nil {
+		atomic.StoreInt32(&l.ready, 1)
+		l.logger.Infow("service is ready")
+
+		select {
+		case <-ctx.Done():
+			l.logger.Infow("received shutdown signal")
+		case runErr = <-l.errors:
+			l.logger.Errorw("component failed, shutting down", "err", runErr)
+		}
+
+		// flip readiness before drain
+		atomic.StoreInt32(&l.ready, 0)
+		if l.options.DrainDelay > 0 {
+			l.logger.Infow("waiting before drain", "delay", l.options.DrainDelay)
+			time.Sleep(l.options.DrainDelay)
+		}
+	}
+
+	// stop started hooks in reverse order
+	stopCtx := context.Background()
+	if l.options.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(stopCtx, l.options.ShutdownTimeout)
+		defer cancel()
+	}
+	for i := started - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		l.logger.Infow("stopping component", "component", hook.Name)
+		err := l.stop(stopCtx, hook)
+		if err != nil {
+			l.logger.Errorw("failed to stop component", "component", hook.Name, "err", err)
+			if runErr == nil {
+				runErr = fmt.Errorf("failed to stop %s: %s", hook.Name, err)
+			}
+		}
+	}
+
+	return runErr
+}
+
+// stop is used to call stop hook within its own timeout.
+func (l *Lifecycle) stop(ctx context.Context, hook Hook) error {
+	if hook.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hook.Timeout)
+		defer cancel()
+	}
+
+	return hook.OnStop(ctx)
+}
@@ -0,0 +1,17 @@
+package mailer
+
+import (
+	"context"
+)
+
+// Message represent plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer represent pluggable delivery of outgoing email.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
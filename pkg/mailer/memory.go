@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory is mailer which keeps sent messages in process, useful for tests and local development.
+type Memory struct {
+	mu       sync.RWMutex
+	messages []Message
+}
+
+// NewMemory is used to create mailer without messages.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Send is used to store message.
+func (m *Memory) Send(ctx context.Context, message Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, message)
+
+	return nil
+}
+
+// Messages is used to get copy of sent messages in order of sending.
+func (m *Memory) Messages() []Message {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+// Last is used to get latest message sent to recipient.
+func (m *Memory) Last(to string) (Message, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+
+	return Message{}, false
+}
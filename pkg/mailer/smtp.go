@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPOptions represent options of SMTP mailer.
+type SMTPOptions struct {
+	// Addr is host:port of SMTP server.
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP is mailer which delivers messages through SMTP server,
+// STARTTLS is used when server supports it and PLAIN auth when username is provided.
+type SMTP struct {
+	options SMTPOptions
+}
+
+// NewSMTP is used to create SMTP mailer.
+func NewSMTP(options SMTPOptions) (*SMTP, error) {
+	if options.From == "" {
+		return nil, fmt.Errorf("sender address is required")
+	}
+	_, _, err := net.SplitHostPort(options.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %s", options.Addr, err)
+	}
+
+	return &SMTP{options: options}, nil
+}
+
+// Send is used to deliver message, header injection is rejected.
+func (s *SMTP) Send(ctx context.Context, message Message) error {
+	for _, value := range []string{message.To, message.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("message header contains line break")
+		}
+	}
+
+	host, _, _ := net.SplitHostPort(s.options.Addr)
+	var auth smtp.Auth
+	if s.options.Username != "" {
+		auth = smtp.PlainAuth("", s.options.Username, s.options.Password, host)
+	}
+
+	// net/smtp does not accept context so deadline is propagated to send through dedicated goroutine
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.options.Addr, auth, s.options.From, []string{message.To}, s.encode(message))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send mail: %s", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send mail: %s", ctx.Err())
+	}
+}
+
+// encode is used to assemble RFC 5322 message with utf-8 plain text body.
+func (s *SMTP) encode(message Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.options.From)
+	fmt.Fprintf(&b, "To: %s\r\n", message.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// startKey is statement setting holding query start time.
+const startKey = "metrics:start"
+
+// InstrumentGorm is used to export query durations and connection pool stats of provided connection.
+func (m *Metrics) InstrumentGorm(db *gorm.DB, name string) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get connection pool: %s", err)
+	}
+	err = m.Registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+	if err != nil {
+		return fmt.Errorf("failed to register connection pool collector: %s", err)
+	}
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(startKey, time.Now())
+	}
+	after := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			value, ok := tx.InstanceGet(startKey)
+			if !ok {
+				return
+			}
+			start, ok := value.(time.Time)
+			if !ok {
+				return
+			}
+
+			status := "ok"
+			if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+				status = "error"
+			}
+			m.queries.
+				WithLabelValues(operation, tx.Statement.Table, status).
+				Observe(time.Since(start).Seconds())
+		}
+	}
+
+	// register callbacks around each processor
+	callback := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("*").Register, callback.Create().After("*").Register},
+		{"query", callback.Query().Before("*").Register, callback.Query().After("*").Register},
+		{"update", callback.Update().Before("*").Register, callback.Update().After("*").Register},
+		{"delete", callback.Delete().Before("*").Register, callback.Delete().After("*").Register},
+		{"row", callback.Row().Before("*").Register, callback.Row().After("*").Register},
+		{"raw", callback.Raw().Before("*").Register, callback.Raw().After("*").Register},
+	}
+	for _, p := range processors {
+		err := p.before(fmt.Sprintf("metrics:before_%s", p.operation), before)
+		if err != nil {
+			return fmt.Errorf("failed to register %s callback: %s", p.operation, err)
+		}
+		err = p.after(fmt.Sprintf("metrics:after_%s", p.operation), after(p.operation))
+		if err != nil {
+			return fmt.Errorf("failed to register %s callback: %s", p.operation, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes all exported metrics.
+const namespace = "gorest"
+
+// unmatchedRoute labels requests which did not match any route to keep cardinality bounded.
+const unmatchedRoute = "unmatched"
+
+// Metrics holds collectors of http servers and database.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+	queries  *prometheus.HistogramVec
+}
+
+// New is used to create metrics with own registry including go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of handled http requests.",
+		}, []string{"server", "method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of handled http requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"server", "method", "route"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of http requests being handled.",
+		}, []string{"server"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "gorm",
+			Name:      "query_duration_seconds",
+			Help:      "Latency of database queries executed through gorm.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table", "status"}),
+	}
+
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.queries,
+	)
+
+	return m
+}
+
+// Handler is used to expose collected metrics in prometheus format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+// observe is used to record handled request.
+func (m *Metrics) observe(server string, method string, route string, status int, start time.Time) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	m.requests.WithLabelValues(server, method, route, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(server, method, route).Observe(time.Since(start).Seconds())
+}
+
+// EchoMiddleware is used to instrument echo routes, requests are labeled with route pattern.
+func (m *Metrics) EchoMiddleware(server string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			inFlight := m.inFlight.WithLabelValues(server)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			start := time.Now()
+			err := next(c)
+
+			// resolve status of errors which are written by echo error handler later
+			status := c.Response().Status
+			if err != nil {
+				status = problem.From(err).Status
+			}
+
+			// router reports unknown paths as raw request path
+			route := c.Path()
+			if err == echo.ErrNotFound {
+				route = unmatchedRoute
+			}
+
+			m.observe(server, c.Request().Method, route, status, start)
+			return err
+		}
+	}
+}
+
+// WrapMux is used to instrument http.ServeMux routes, requests are labeled with matched pattern.
+func (m *Metrics) WrapMux(server string, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight := m.inFlight.WithLabelValues(server)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		start := time.Now()
+		_, route := mux.Handler(r)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		mux.ServeHTTP(recorder, r)
+
+		m.observe(server, r.Method, route, recorder.status, start)
+	})
+}
+
+// statusRecorder captures status code written by handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEchoMiddlewareStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status string
+	}{
+		{name: "success", err: nil, status: "200"},
+		{name: "problem error", err: problem.New(http.StatusForbidden, problem.CodeForbidden, "forbidden"), status: "403"},
+		{name: "wrapped problem error", err: problem.BadRequest("bad request").Wrap(errors.New("cause")), status: "400"},
+		{name: "echo error", err: echo.NewHTTPError(http.StatusTooManyRequests), status: "429"},
+		{name: "plain error", err: errors.New("failure"), status: "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			e := echo.New()
+			e.Use(m.EchoMiddleware("test"))
+			e.GET("/resource", func(c echo.Context) error {
+				if tt.err != nil {
+					return tt.err
+				}
+				return c.NoContent(http.StatusOK)
+			})
+
+			e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/resource", nil))
+
+			count := testutil.ToFloat64(m.requests.WithLabelValues("test", http.MethodGet, "/resource", tt.status))
+			require.Equal(t, float64(1), count, "request was not recorded with status %s", tt.status)
+		})
+	}
+}
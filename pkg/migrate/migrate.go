@@ -0,0 +1,251 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// lockName is name of mysql lock held while migrations are applied or rolled back.
+const lockName = "gorest_schema_migrations"
+
+// Migration represent versioned change of schema, each step is run in transaction with its record,
+// mysql commits schema changes implicitly so failed step may need manual fix.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	// Down is nil if migration can not be rolled back.
+	Down func(tx *gorm.DB) error
+}
+
+// Record represent applied migration stored in schema_migrations table.
+type Record struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"column:applied_at;not null"`
+}
+
+// TableName is used to name table of records.
+func (Record) TableName() string {
+	return "schema_migrations"
+}
+
+// Status represent migration known to binary or applied to database.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	// Missing is true if applied migration is not known to binary.
+	Missing bool
+}
+
+// Migrator is used to apply and roll back migrations in order of versions.
+type Migrator struct {
+	db          *gorm.DB
+	logger      *zap.SugaredLogger
+	migrations  []Migration
+	lockTimeout time.Duration
+}
+
+// New is used to create migrator of database, migrations are sorted by version which must be unique.
+func New(db *gorm.DB, logger *zap.SugaredLogger, migrations []Migration, lockTimeout time.Duration) (*Migrator, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, migration := range sorted {
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up step", migration.Version, migration.Name)
+		}
+		if i > 0 && sorted[i-1].Version == migration.Version {
+			return nil, fmt.Errorf("migrations %s and %s have same version %d", sorted[i-1].Name, migration.Name, migration.Version)
+		}
+	}
+
+	return &Migrator{
+		db:          db,
+		logger:      logger,
+		migrations:  sorted,
+		lockTimeout: lockTimeout,
+	}, nil
+}
+
+// Up is used to apply pending migrations, returns applied migrations.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.lock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			m.logger.Infow("applying migration", "version", migration.Version, "name", migration.Name)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				err := migration.Up(tx)
+				if err != nil {
+					return err
+				}
+				return tx.Create(&Record{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now().UTC(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %s", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down is used to roll back number of latest applied migrations, returns rolled back migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("invalid number of steps %d", steps)
+	}
+
+	known := map[int64]Migration{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var done []Migration
+	err := m.lock(ctx, func(conn *gorm.DB) error {
+		var records []Record
+		err := conn.
+			Model(&Record{}).
+			Order("version DESC").
+			Limit(steps).
+			Find(&records).
+			Error
+		if err != nil {
+			return fmt.Errorf("failed to get applied migrations: %s", err)
+		}
+
+		for _, record := range records {
+			migration, ok := known[record.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is not known", record.Version, record.Name)
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("migration %d_%s can not be rolled back", record.Version, record.Name)
+			}
+
+			m.logger.Infow("rolling back migration", "version", migration.Version, "name", migration.Name)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				err := migration.Down(tx)
+				if err != nil {
+					return err
+				}
+				return tx.Delete(&Record{}, record.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %s", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status is used to list known and applied migrations in order of versions.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.lock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if record, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &record.AppliedAt
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, record := range applied {
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, Status{
+				Version:   record.Version,
+				Name:      record.Name,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, err
+}
+
+// applied is used to get records of applied migrations by version.
+func (m *Migrator) applied(conn *gorm.DB) (map[int64]Record, error) {
+	var records []Record
+	err := conn.Model(&Record{}).Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %s", err)
+	}
+
+	applied := make(map[int64]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// lock is used to run function on single connection holding lock of migrations and to create table of records,
+// mysql lock is released with connection if process dies, other databases are expected to have single migrating process.
+func (m *Migrator) lock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		// start every statement from clean state, otherwise table of records leaks into migrations
+		conn = conn.Session(&gorm.Session{NewDB: true})
+
+		if conn.Dialector.Name() == "mysql" {
+			var acquired sql.NullInt64
+			err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(m.lockTimeout.Seconds())).Scan(&acquired).Error
+			if err != nil {
+				return fmt.Errorf("failed to acquire migrations lock: %s", err)
+			}
+			if !acquired.Valid || acquired.Int64 != 1 {
+				return fmt.Errorf("timed out waiting for migrations lock after %s", m.lockTimeout)
+			}
+			defer func() {
+				err := conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error
+				if err != nil {
+					m.logger.Warnw("failed to release migrations lock", "err", err)
+				}
+			}()
+		}
+
+		if !conn.Migrator().HasTable(&Record{}) {
+			err := conn.Migrator().CreateTable(&Record{})
+			if err != nil {
+				return fmt.Errorf("failed to create migrations table: %s", err)
+			}
+		}
+
+		return fn(conn)
+	})
+}
@@ -0,0 +1,258 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openSQLite is used to open own in-memory sqlite database.
+func openSQLite(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:migrate_%s?mode=memory&cache=shared", uuid.New())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to open database")
+	return db
+}
+
+// openMySQL is used to connect to mysql server configured by GOREST_MYSQL_* like test harness,
+// test is skipped unless GOREST_TEST_DATABASE is mysql.
+func openMySQL(t *testing.T) *gorm.DB {
+	if os.Getenv("GOREST_TEST_DATABASE") != "mysql" {
+		t.Skip("locks are implemented for mysql only, set GOREST_TEST_DATABASE=mysql to run")
+	}
+
+	env := func(key string, fallback string) string {
+		if value, ok := os.LookupEnv(key); ok {
+			return value
+		}
+		return fallback
+	}
+	dsn := fmt.Sprintf(
+		"%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		env("GOREST_MYSQL_USER", "root"), env("GOREST_MYSQL_PASS", ""),
+		env("GOREST_MYSQL_HOST", "127.0.0.1:3306"), env("GOREST_MYSQL_DATABASE", "gorest_db"),
+	)
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to connect to mysql")
+	return db
+}
+
+// createTable is used to create migration of table which is dropped on rollback.
+func createTable(version int64, table string) Migration {
+	return Migration{
+		Version: version,
+		Name:    "create_" + table,
+		Up:      SQL(fmt.Sprintf("CREATE TABLE %s (id INTEGER PRIMARY KEY);", table)),
+		Down:    SQL(fmt.Sprintf("DROP TABLE %s;", table)),
+	}
+}
+
+// versions is used to get versions of migrations.
+func versions(migrations []Migration) []int64 {
+	v := make([]int64, len(migrations))
+	for i, migration := range migrations {
+		v[i] = migration.Version
+	}
+	return v
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+		err        bool
+	}{
+		{name: "unique versions", migrations: []Migration{createTable(2, "b"), createTable(1, "a")}},
+		{name: "duplicate version", migrations: []Migration{createTable(1, "a"), createTable(1, "b")}, err: true},
+		{name: "missing up step", migrations: []Migration{{Version: 1, Name: "empty"}}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(openSQLite(t), zap.NewNop().Sugar(), tt.migrations, time.Second)
+			if tt.err {
+				require.Error(t, err, "migrator was created")
+				return
+			}
+			require.NoError(t, err, "failed to create migrator")
+			require.Equal(t, []int64{1, 2}, versions(m.migrations), "migrations are not sorted")
+		})
+	}
+}
+
+func TestUpRollback(t *testing.T) {
+	db := openSQLite(t)
+
+	// second migration fails after its first statement
+	failing := Migration{
+		Version: 2,
+		Name:    "create_b",
+		Up:      SQL("CREATE TABLE b (id INTEGER PRIMARY KEY);\nINSERT INTO missing (id) VALUES (1);\n"),
+	}
+	m, err := New(db, zap.NewNop().Sugar(), []Migration{createTable(1, "a"), failing, createTable(3, "c")}, time.Second)
+	require.NoError(t, err, "failed to create migrator")
+
+	applied, err := m.Up(context.Background())
+	require.Error(t, err, "failing migration was applied")
+	require.Contains(t, err.Error(), "2_create_b", "error does not name migration")
+	require.Equal(t, []int64{1}, versions(applied), "invalid applied migrations")
+
+	// step of failed migration is rolled back with its record and later migrations are not applied
+	require.True(t, db.Migrator().HasTable("a"), "table of applied migration is missing")
+	require.False(t, db.Migrator().HasTable("b"), "table of failed migration was kept")
+	require.False(t, db.Migrator().HasTable("c"), "migration after failed one was applied")
+	var records []Record
+	require.NoError(t, db.Order("version").Find(&records).Error, "failed to get records")
+	require.Len(t, records, 1, "invalid number of records")
+
+	// fixed migration is applied on next run
+	m, err = New(db, zap.NewNop().Sugar(), []Migration{createTable(1, "a"), createTable(2, "b"), createTable(3, "c")}, time.Second)
+	require.NoError(t, err, "failed to create migrator")
+	applied, err = m.Up(context.Background())
+	require.NoError(t, err, "failed to apply migrations")
+	require.Equal(t, []int64{2, 3}, versions(applied), "invalid applied migrations")
+}
+
+func TestDownRollback(t *testing.T) {
+	failing := createTable(3, "c")
+	failing.Down = func(tx *gorm.DB) error {
+		err := tx.Exec("DROP TABLE c").Error
+		if err != nil {
+			return err
+		}
+		return errors.New("failed")
+	}
+	irreversible := createTable(2, "b")
+	irreversible.Down = nil
+
+	tests := []struct {
+		name       string
+		migrations []Migration
+		steps      int
+		rolledBack []int64
+		// tables are expected to exist after rollback
+		tables []string
+		err    bool
+	}{
+		{name: "latest migrations", migrations: []Migration{createTable(1, "a"), createTable(2, "b"), createTable(3, "c")}, steps: 2, rolledBack: []int64{3, 2}, tables: []string{"a"}},
+		{name: "more steps than applied", migrations: []Migration{createTable(1, "a")}, steps: 5, rolledBack: []int64{1}, tables: []string{}},
+		{name: "failing down step", migrations: []Migration{createTable(1, "a"), createTable(2, "b"), failing}, steps: 2, rolledBack: []int64{}, tables: []string{"a", "b", "c"}, err: true},
+		{name: "migration without down step", migrations: []Migration{createTable(1, "a"), irreversible, createTable(3, "c")}, steps: 2, rolledBack: []int64{3}, tables: []string{"a", "b"}, err: true},
+		{name: "invalid steps", migrations: []Migration{createTable(1, "a")}, steps: 0, rolledBack: []int64{}, tables: []string{"a"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openSQLite(t)
+			m, err := New(db, zap.NewNop().Sugar(), tt.migrations, time.Second)
+			require.NoError(t, err, "failed to create migrator")
+			_, err = m.Up(context.Background())
+			require.NoError(t, err, "failed to apply migrations")
+
+			rolledBack, err := m.Down(context.Background(), tt.steps)
+			if tt.err {
+				require.Error(t, err, "migrations were rolled back")
+			} else {
+				require.NoError(t, err, "failed to roll back migrations")
+			}
+			require.Equal(t, tt.rolledBack, versions(rolledBack), "invalid rolled back migrations")
+
+			// records are kept for migrations which were not rolled back
+			var records []Record
+			require.NoError(t, db.Find(&records).Error, "failed to get records")
+			require.Len(t, records, len(tt.migrations)-len(tt.rolledBack), "invalid number of records")
+			for _, migration := range tt.migrations {
+				table := migration.Name[len("create_"):]
+				require.Equal(t, contains(tt.tables, table), db.Migrator().HasTable(table), "unexpected state of table %s", table)
+			}
+		})
+	}
+}
+
+// contains is used to check if list includes value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLockContention(t *testing.T) {
+	db := openMySQL(t)
+
+	// tables and records are removed so database of other tests is not touched
+	suffix := uuid.New().String()[:8]
+	tables := []string{"lock_a_" + suffix, "lock_b_" + suffix}
+	migrations := []Migration{createTable(1, tables[0]), createTable(2, tables[1])}
+	t.Cleanup(func() {
+		for _, table := range tables {
+			db.Migrator().DropTable(table)
+		}
+		db.Delete(&Record{}, []int64{1, 2})
+	})
+
+	t.Run("should time out while lock is held", func(t *testing.T) {
+		conn, err := db.DB()
+		require.NoError(t, err, "failed to get connection pool")
+		holder, err := conn.Conn(context.Background())
+		require.NoError(t, err, "failed to get connection")
+		defer holder.Close()
+
+		var acquired int
+		err = holder.QueryRowContext(context.Background(), "SELECT GET_LOCK(?, 0)", lockName).Scan(&acquired)
+		require.NoError(t, err, "failed to acquire lock")
+		require.Equal(t, 1, acquired, "lock is held by other process")
+
+		m, err := New(db, zap.NewNop().Sugar(), migrations, time.Second)
+		require.NoError(t, err, "failed to create migrator")
+		applied, err := m.Up(context.Background())
+		require.Error(t, err, "migrations were applied while lock was held")
+		require.Empty(t, applied, "migrations were applied while lock was held")
+
+		_, err = holder.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", lockName)
+		require.NoError(t, err, "failed to release lock")
+	})
+
+	t.Run("should apply migrations once by concurrent migrators", func(t *testing.T) {
+		var wg sync.WaitGroup
+		results := make([][]Migration, 4)
+		errs := make([]error, len(results))
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				m, err := New(db, zap.NewNop().Sugar(), migrations, 10*time.Second)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				results[i], errs[i] = m.Up(context.Background())
+			}(i)
+		}
+		wg.Wait()
+
+		var total int
+		for i := range results {
+			require.NoError(t, errs[i], "failed to apply migrations")
+			total += len(results[i])
+		}
+		require.Equal(t, len(migrations), total, "migrations were applied more than once")
+	})
+}
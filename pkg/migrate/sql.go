@@ -0,0 +1,138 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// fileName matches names of sql migration files, <version>_<name>.up.sql or <version>_<name>.down.sql.
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// statementEnd matches semicolon ending line which separates statements.
+var statementEnd = regexp.MustCompile(`;[ \t]*(\r?\n|$)`)
+
+// versionLayout is layout of time used as version of created migrations.
+const versionLayout = "20060102150405"
+
+// Load is used to read sql migrations of directory, down file is optional.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %s", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	var versions []int64
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration file %q: %s", entry.Name(), err)
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %q: %s", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+			versions = append(versions, version)
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration files of version %d have different names", version)
+		}
+
+		step := SQL(string(b))
+		if match[3] == "up" {
+			migration.Up = step
+		} else {
+			migration.Down = step
+		}
+	}
+
+	migrations := make([]Migration, 0, len(versions))
+	for _, version := range versions {
+		migration := byVersion[version]
+		if migration.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up file", version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	return migrations, nil
+}
+
+// SQL is used to create step executing statements of script, statements are separated by semicolon ending line.
+func SQL(script string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, statement := range statementEnd.Split(script, -1) {
+			if isBlank(statement) {
+				continue
+			}
+			err := tx.Exec(statement).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// isBlank is used to check if statement has nothing but whitespace and line comments.
+func isBlank(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
+
+// Create is used to write empty up and down files of new migration versioned by time, returns paths of files.
+func Create(dir string, name string, now time.Time) (string, string, error) {
+	name = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+	base := fmt.Sprintf("%s_%s", now.UTC().Format(versionLayout), name)
+	if !fileName.MatchString(base + ".up.sql") {
+		return "", "", fmt.Errorf("invalid migration name %q", name)
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create migrations directory: %s", err)
+	}
+
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+	for _, file := range []string{up, down} {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration file: %s", err)
+		}
+		_, err = fmt.Fprintf(f, "-- %s\n", filepath.Base(file))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write migration file: %s", err)
+		}
+	}
+	return up, down, nil
+}
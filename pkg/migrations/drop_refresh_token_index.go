@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+// dropRefreshTokenIndex drops index of provider tokens left by automigration of earlier models,
+// encrypted values are too long to be indexed so index is not restored on rollback.
+var dropRefreshTokenIndex = migrate.Migration{
+	Version: 20261018000100,
+	Name:    "drop_refresh_token_index",
+	Up: func(tx *gorm.DB) error {
+		if !tx.Migrator().HasIndex("auth_providers", "idx_auth_providers_refresh_token") {
+			return nil
+		}
+		return tx.Migrator().DropIndex("auth_providers", "idx_auth_providers_refresh_token")
+	},
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
@@ -0,0 +1,30 @@
+// Package migrations contains versioned changes of database schema,
+// sql migrations are embedded from sql directory and go migrations are listed below.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+)
+
+// Dir is directory of sql migrations relative to repository root, new migrations are created there.
+const Dir = "pkg/migrations/sql"
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// goMigrations are migrations which can not be expressed in sql.
+var goMigrations = []migrate.Migration{
+	dropRefreshTokenIndex,
+	upgradeAuthProviders,
+}
+
+// All is used to get sql and go migrations.
+func All() ([]migrate.Migration, error) {
+	migrations, err := migrate.Load(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return append(migrations, goMigrations...), nil
+}
@@ -0,0 +1,150 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/keyring"
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// baseline models are snapshot of models automigrated before versioned migrations were introduced.
+type baselineUser struct {
+	models.Base
+	Username  string `gorm:"column:username;index;not null"`
+	Email     string `gorm:"column:email;index;not null"`
+	UserRole  string `gorm:"column:user_role;not null"`
+	AvatarURL string `gorm:"column:avatar_url;"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineAuthProvider struct {
+	models.Base
+	UserID           uuid.UUID `gorm:"column:user_id;type:char(36);index;not null"`
+	ProviderUID      string    `gorm:"column:provider_uid;index;not null"`
+	RefreshToken     string    `gorm:"column:refresh_token;type:varchar(511);index;not null"`
+	AuthProviderType string    `gorm:"column:auth_provider_type;not null"`
+}
+
+func (baselineAuthProvider) TableName() string { return "auth_providers" }
+
+type baselinePost struct {
+	models.Base
+	UserID uuid.UUID `gorm:"column:user_id;type:char(36);index;not null"`
+	Title  string    `gorm:"column:title;not null"`
+	Body   string    `gorm:"column:body;not null"`
+}
+
+func (baselinePost) TableName() string { return "posts" }
+
+type baselineComment struct {
+	models.Base
+	PostID uuid.UUID `gorm:"column:post_id;type:char(36);index;not null"`
+	UserID uuid.UUID `gorm:"column:user_id;type:char(36);index;not null"`
+	Name   string    `gorm:"column:name;not null"`
+	Body   string    `gorm:"column:body;not null"`
+}
+
+func (baselineComment) TableName() string { return "comments" }
+
+// openDatabase is used to open own in-memory sqlite database.
+func openDatabase(t *testing.T) *gorm.DB {
+	ring, err := keyring.Derive("test", "secret")
+	require.NoError(t, err, "failed to derive keyring")
+	keyring.Register(ring)
+
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:migrations_%s?mode=memory&cache=shared", uuid.New())), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err, "failed to open database")
+	return db
+}
+
+// requireModelSchema is used to check that every column and index of model exists in database.
+func requireModelSchema(t *testing.T, db *gorm.DB, model interface{}) {
+	s, err := schema.Parse(model, &sync.Map{}, db.NamingStrategy)
+	require.NoError(t, err, "failed to parse model")
+
+	for _, field := range s.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		require.True(t, db.Migrator().HasColumn(s.Table, field.DBName), "column %s.%s is missing", s.Table, field.DBName)
+	}
+	for _, index := range s.ParseIndexes() {
+		require.True(t, db.Migrator().HasIndex(s.Table, index.Name), "index %s is missing", index.Name)
+	}
+}
+
+func TestUpgradeBaselineSchema(t *testing.T) {
+	// sql migrations are written for mysql, go migrations upgrading baseline tables are run here
+	upgrades := []migrate.Migration{dropRefreshTokenIndex, upgradeAuthProviders}
+
+	tests := []struct {
+		name  string
+		setup func(db *gorm.DB) error
+	}{
+		{
+			name: "baseline schema",
+			setup: func(db *gorm.DB) error {
+				return db.AutoMigrate(&baselineUser{}, &baselineAuthProvider{}, &baselinePost{}, &baselineComment{})
+			},
+		},
+		{
+			name: "current schema",
+			setup: func(db *gorm.DB) error {
+				return db.AutoMigrate(models.All()...)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openDatabase(t)
+			require.NoError(t, tt.setup(db), "failed to create schema")
+
+			// existing provider is kept by upgrade
+			userID := uuid.New()
+			err := db.Exec(
+				"INSERT INTO auth_providers (id, user_id, provider_uid, refresh_token, auth_provider_type) VALUES (?, ?, ?, ?, ?)",
+				uuid.New(), userID, "123", "token", "google",
+			).Error
+			require.NoError(t, err, "failed to create auth provider")
+
+			m, err := migrate.New(db, zap.NewNop().Sugar(), upgrades, time.Second)
+			require.NoError(t, err, "failed to create migrator")
+			applied, err := m.Up(context.Background())
+			require.NoError(t, err, "failed to apply migrations")
+			require.Len(t, applied, len(upgrades), "invalid number of applied migrations")
+
+			for _, model := range []interface{}{&models.User{}, &models.AuthProvider{}, &models.Post{}, &models.Comment{}} {
+				requireModelSchema(t, db, model)
+			}
+			require.False(t, db.Migrator().HasIndex("auth_providers", "idx_auth_providers_refresh_token"), "refresh token index was kept")
+
+			var count int64
+			err = db.Table("auth_providers").Where("provider_uid = ? AND verified_at IS NULL AND password_hash IS NULL", "123").Count(&count).Error
+			require.NoError(t, err, "failed to count auth providers")
+			require.Equal(t, int64(1), count, "auth provider was not kept")
+
+			// provider account can not be stored twice
+			err = db.Exec(
+				"INSERT INTO auth_providers (id, user_id, provider_uid, refresh_token, auth_provider_type) VALUES (?, ?, ?, ?, ?)",
+				uuid.New(), userID, "123", "token", "google",
+			).Error
+			require.Error(t, err, "duplicate identity was stored")
+		})
+	}
+}
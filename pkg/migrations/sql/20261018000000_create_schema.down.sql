@@ -0,0 +1,9 @@
+-- Tables are dropped in reverse order of references.
+
+DROP TABLE IF EXISTS `comments`;
+DROP TABLE IF EXISTS `posts`;
+DROP TABLE IF EXISTS `verification_tokens`;
+DROP TABLE IF EXISTS `personal_access_tokens`;
+DROP TABLE IF EXISTS `sessions`;
+DROP TABLE IF EXISTS `auth_providers`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,133 @@
+-- Schema created by automigration of models, tables are kept if they were already automigrated
+-- and brought up to date by later migrations.
+
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `username` varchar(191) NOT NULL,
+  `email` varchar(191) NOT NULL,
+  `user_role` longtext NOT NULL,
+  `avatar_url` longtext,
+  PRIMARY KEY (`id`),
+  INDEX `idx_users_email` (`email`),
+  INDEX `idx_users_created_at` (`created_at`),
+  INDEX `idx_users_deleted_at` (`deleted_at`),
+  INDEX `idx_users_username` (`username`)
+);
+
+CREATE TABLE IF NOT EXISTS `auth_providers` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` char(36) NOT NULL,
+  `provider_uid` varchar(191) NOT NULL,
+  `refresh_token` varchar(1023) NOT NULL,
+  `auth_provider_type` varchar(64) NOT NULL,
+  `password_hash` varchar(255),
+  `verified_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_auth_providers_created_at` (`created_at`),
+  INDEX `idx_auth_providers_deleted_at` (`deleted_at`),
+  INDEX `idx_auth_providers_user_id` (`user_id`),
+  INDEX `idx_auth_providers_provider_uid` (`provider_uid`),
+  UNIQUE INDEX `idx_auth_providers_identity` (`provider_uid`, `auth_provider_type`),
+  CONSTRAINT `fk_users_auth_provider` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `sessions` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` char(36) NOT NULL,
+  `family_id` char(36) NOT NULL,
+  `token_hash` char(64) NOT NULL,
+  `user_agent` varchar(511),
+  `ip` varchar(45),
+  `expires_at` datetime(3) NOT NULL,
+  `rotated_at` datetime(3) NULL,
+  `revoked_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sessions_token_hash` (`token_hash`),
+  INDEX `idx_sessions_revoked_at` (`revoked_at`),
+  INDEX `idx_sessions_created_at` (`created_at`),
+  INDEX `idx_sessions_deleted_at` (`deleted_at`),
+  INDEX `idx_sessions_user_id` (`user_id`),
+  INDEX `idx_sessions_family_id` (`family_id`),
+  CONSTRAINT `fk_users_session` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `personal_access_tokens` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` char(36) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `prefix` varchar(32) NOT NULL,
+  `token_hash` char(64) NOT NULL,
+  `scopes` varchar(1023) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `last_used_at` datetime(3) NULL,
+  `revoked_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_personal_access_tokens_created_at` (`created_at`),
+  INDEX `idx_personal_access_tokens_deleted_at` (`deleted_at`),
+  INDEX `idx_personal_access_tokens_user_id` (`user_id`),
+  UNIQUE INDEX `idx_personal_access_tokens_token_hash` (`token_hash`),
+  CONSTRAINT `fk_users_personal_access_token` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `verification_tokens` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` char(36) NOT NULL,
+  `purpose` varchar(32) NOT NULL,
+  `token_hash` char(64) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `used_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_verification_tokens_created_at` (`created_at`),
+  INDEX `idx_verification_tokens_deleted_at` (`deleted_at`),
+  INDEX `idx_verification_tokens_user_id` (`user_id`),
+  UNIQUE INDEX `idx_verification_tokens_token_hash` (`token_hash`),
+  CONSTRAINT `fk_users_verification_token` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `posts` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` char(36) NOT NULL,
+  `title` longtext NOT NULL,
+  `body` longtext NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_posts_created_at` (`created_at`),
+  INDEX `idx_posts_deleted_at` (`deleted_at`),
+  INDEX `idx_posts_user_id` (`user_id`),
+  CONSTRAINT `fk_users_post` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `comments` (
+  `id` char(36),
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `post_id` char(36) NOT NULL,
+  `user_id` char(36) NOT NULL,
+  `name` longtext NOT NULL,
+  `body` longtext NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_comments_post_id` (`post_id`),
+  INDEX `idx_comments_user_id` (`user_id`),
+  INDEX `idx_comments_created_at` (`created_at`),
+  INDEX `idx_comments_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_posts_comment` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE ON UPDATE CASCADE,
+  CONSTRAINT `fk_users_comment` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX `idx_comments_fulltext` ON `comments`;
+DROP INDEX `idx_posts_fulltext` ON `posts`;
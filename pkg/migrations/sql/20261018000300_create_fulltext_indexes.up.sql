@@ -0,0 +1,4 @@
+-- FULLTEXT indexes used by mysql search backend, columns match columns queried by backend.
+
+CREATE FULLTEXT INDEX `idx_posts_fulltext` ON `posts` (`title`, `body`);
+CREATE FULLTEXT INDEX `idx_comments_fulltext` ON `comments` (`name`, `body`);
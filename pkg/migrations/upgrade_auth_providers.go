@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+// authProviderSchema is snapshot of auth providers table this migration brings schema to,
+// it is kept separate from models so migration does not change with them.
+type authProviderSchema struct {
+	ProviderUID      string     `gorm:"column:provider_uid;uniqueIndex:idx_auth_providers_identity;not null"`
+	RefreshToken     string     `gorm:"column:refresh_token;type:varchar(1023);not null"`
+	AuthProviderType string     `gorm:"column:auth_provider_type;type:varchar(64);uniqueIndex:idx_auth_providers_identity;not null"`
+	PasswordHash     string     `gorm:"column:password_hash;type:varchar(255)"`
+	VerifiedAt       *time.Time `gorm:"column:verified_at"`
+}
+
+// TableName is used to name table of snapshot.
+func (authProviderSchema) TableName() string {
+	return "auth_providers"
+}
+
+// upgradeAuthProviders brings auth providers table automigrated by earlier models to shape created by schema migration,
+// tables created by schema migration already have this shape so every step is skipped. Identity index fails to be
+// created if same provider account was stored twice, duplicates must be removed manually then.
+// Rollback is noop since columns are part of schema created by first migration.
+var upgradeAuthProviders = migrate.Migration{
+	Version: 20261018000200,
+	Name:    "upgrade_auth_providers",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+
+		// local auth columns
+		for _, field := range []string{"PasswordHash", "VerifiedAt"} {
+			if m.HasColumn(&authProviderSchema{}, field) {
+				continue
+			}
+			err := m.AddColumn(&authProviderSchema{}, field)
+			if err != nil {
+				return err
+			}
+		}
+
+		// encrypted tokens need wider column and provider names of oidc need type which can be indexed,
+		// other databases do not enforce length of varchar
+		if tx.Dialector.Name() == "mysql" {
+			for _, field := range []string{"RefreshToken", "AuthProviderType"} {
+				err := m.AlterColumn(&authProviderSchema{}, field)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		// provider account can be linked to single user
+		if !m.HasIndex(&authProviderSchema{}, "idx_auth_providers_identity") {
+			return m.CreateIndex(&authProviderSchema{}, "idx_auth_providers_identity")
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
@@ -26,9 +26,12 @@ type User struct {
 	AvatarURL string   `json:"avatarUrl" xml:"avatarurl" gorm:"column:avatar_url;"`
 
 	// one-to-many relations
-	Post         []Post         `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
-	Comment      []Comment      `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
-	AuthProvider []AuthProvider `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	Post                []Post                `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	Comment             []Comment             `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	AuthProvider        []AuthProvider        `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	Session             []Session             `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	PersonalAccessToken []PersonalAccessToken `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
+	VerificationToken   []VerificationToken   `json:"-" xml:"-" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;ForeignKey:UserID"`
 } // @name User
 
 type AuthProvider struct {
@@ -37,11 +40,61 @@ type AuthProvider struct {
 	// fk
 	UserID uuid.UUID `json:"userId" xml:"userid" gorm:"column:user_id;type:char(36);index;not null"`
 
-	ProviderUID      string           `json:"providerUid" xml:"providerUid" gorm:"column:provider_uid;index;not null"`
-	RefreshToken     string           `json:"refreshToken" xml:"refreshtoken" gorm:"column:refresh_token;type:varchar(511);index;not null"`
-	AuthProviderType AuthProviderType `json:"authProviderType" xml:"authproviderType" gorm:"column:auth_provider_type;not null"`
+	// provider account can be linked to single user
+	ProviderUID      string           `json:"providerUid" xml:"providerUid" gorm:"column:provider_uid;index;uniqueIndex:idx_auth_providers_identity;not null"`
+	RefreshToken     string           `json:"refreshToken" xml:"refreshtoken" gorm:"column:refresh_token;type:varchar(1023);not null;serializer:encrypted"`
+	AuthProviderType AuthProviderType `json:"authProviderType" xml:"authproviderType" gorm:"column:auth_provider_type;type:varchar(64);uniqueIndex:idx_auth_providers_identity;not null"`
+
+	// local provider only, email is verified once user follows verification email
+	PasswordHash string     `json:"-" xml:"-" gorm:"column:password_hash;type:varchar(255)"`
+	VerifiedAt   *time.Time `json:"-" xml:"-" gorm:"column:verified_at"`
 } // @name AuthProvider
 
+// Represent refresh token session, rotated tokens share family id
+type Session struct {
+	Base
+
+	// fk
+	UserID uuid.UUID `json:"userId" xml:"userId" gorm:"column:user_id;type:char(36);index;not null"`
+
+	FamilyID  uuid.UUID  `json:"familyId" xml:"familyId" gorm:"column:family_id;type:char(36);index;not null"`
+	TokenHash string     `json:"-" xml:"-" gorm:"column:token_hash;type:char(64);uniqueIndex;not null"`
+	UserAgent string     `json:"userAgent" xml:"userAgent" gorm:"column:user_agent;type:varchar(511)"`
+	IP        string     `json:"ip" xml:"ip" gorm:"column:ip;type:varchar(45)"`
+	ExpiresAt time.Time  `json:"expiresAt" xml:"expiresAt" gorm:"column:expires_at;not null"`
+	RotatedAt *time.Time `json:"-" xml:"-" gorm:"column:rotated_at"`
+	RevokedAt *time.Time `json:"-" xml:"-" gorm:"column:revoked_at;index"`
+} // @name Session
+
+// Represent personal access token of machine client, only hash of token is stored
+type PersonalAccessToken struct {
+	Base
+
+	// fk
+	UserID uuid.UUID `json:"userId" xml:"userId" gorm:"column:user_id;type:char(36);index;not null"`
+
+	Name       string     `json:"name" xml:"name" gorm:"column:name;type:varchar(255);not null"`
+	Prefix     string     `json:"prefix" xml:"prefix" gorm:"column:prefix;type:varchar(32);not null"`
+	TokenHash  string     `json:"-" xml:"-" gorm:"column:token_hash;type:char(64);uniqueIndex;not null"`
+	Scopes     []string   `json:"scopes" xml:"scopes" gorm:"column:scopes;type:varchar(1023);serializer:json;not null"`
+	ExpiresAt  time.Time  `json:"expiresAt" xml:"expiresAt" gorm:"column:expires_at;not null"`
+	LastUsedAt *time.Time `json:"lastUsedAt" xml:"lastUsedAt" gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `json:"revokedAt" xml:"revokedAt" gorm:"column:revoked_at"`
+} // @name PersonalAccessToken
+
+// Represent single use token sent by email, only hash of token is stored
+type VerificationToken struct {
+	Base
+
+	// fk
+	UserID uuid.UUID `json:"userId" xml:"userId" gorm:"column:user_id;type:char(36);index;not null"`
+
+	Purpose   VerificationPurpose `json:"purpose" xml:"purpose" gorm:"column:purpose;type:varchar(32);not null"`
+	TokenHash string              `json:"-" xml:"-" gorm:"column:token_hash;type:char(64);uniqueIndex;not null"`
+	ExpiresAt time.Time           `json:"expiresAt" xml:"expiresAt" gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time          `json:"-" xml:"-" gorm:"column:used_at"`
+} // @name VerificationToken
+
 // Represent business model of Post
 type Post struct {
 	Base
@@ -73,13 +126,29 @@ func (b *Base) BeforeCreate(tx *gorm.DB) (err error) {
 	return
 }
 
+// All is used to get models stored in database, referenced models go first.
+func All() []interface{} {
+	return []interface{}{
+		&User{},
+		&AuthProvider{},
+		&Session{},
+		&PersonalAccessToken{},
+		&VerificationToken{},
+		&Post{},
+		&Comment{},
+	}
+}
+
 // MimeType represent mime types of sort.
 type MimeType string
 
 // Mime types.
 const (
-	MimeTypesXML  MimeType = "application/xml"
-	MimeTypesJSON MimeType = "application/json"
+	MimeTypesXML     MimeType = "application/xml"
+	MimeTypesJSON    MimeType = "application/json"
+	MimeTypesYAML    MimeType = "application/yaml"
+	MimeTypesMsgPack MimeType = "application/msgpack"
+	MimeTypesCSV     MimeType = "text/csv"
 )
 
 // UserRole represent user roles.
@@ -100,6 +169,16 @@ const (
 	AuthProviderTypeGoogle   AuthProviderType = "google"
 	AuthProviderTypeFacebook AuthProviderType = "facebook"
 	AuthProviderTypeGithub   AuthProviderType = "github"
+	AuthProviderTypeLocal    AuthProviderType = "local"
+)
+
+// VerificationPurpose represent actions confirmed by verification tokens.
+type VerificationPurpose string
+
+// Verification purposes.
+const (
+	VerificationPurposeEmail         VerificationPurpose = "email_verification"
+	VerificationPurposePasswordReset VerificationPurpose = "password_reset"
 )
 
 // origin
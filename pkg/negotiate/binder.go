@@ -0,0 +1,66 @@
+package negotiate
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Binder is used to bind echo requests, bodies of content types supported by default binder are bound by it,
+// other bodies are decoded by codecs of negotiator.
+type Binder struct {
+	echo.DefaultBinder
+	negotiator *Negotiator
+}
+
+// Binder is used to create echo binder decoding request bodies with codecs.
+func (n *Negotiator) Binder() *Binder {
+	return &Binder{negotiator: n}
+}
+
+// Bind is used to bind path params, query params of GET and DELETE requests and body, same as default binder.
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	req := c.Request()
+	ctype := req.Header.Get(echo.HeaderContentType)
+	if req.ContentLength == 0 || defaultBindable(ctype) {
+		return b.DefaultBinder.Bind(i, c)
+	}
+
+	err := b.BindPathParams(c, i)
+	if err != nil {
+		return err
+	}
+	if req.Method == http.MethodGet || req.Method == http.MethodDelete {
+		err = b.BindQueryParams(c, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = b.negotiator.Decode(req, i)
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return echo.ErrUnsupportedMediaType
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+// defaultBindable is used to check if content type is bound by default binder.
+func defaultBindable(ctype string) bool {
+	for _, prefix := range []string{
+		echo.MIMEApplicationJSON,
+		echo.MIMEApplicationXML,
+		echo.MIMETextXML,
+		echo.MIMEApplicationForm,
+		echo.MIMEMultipartForm,
+	} {
+		if strings.HasPrefix(ctype, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,278 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v2"
+)
+
+// JSON is used to create json codec.
+func JSON() Codec {
+	return Codec{
+		MimeTypes:   []string{string(models.MimeTypesJSON)},
+		ContentType: "application/json; charset=UTF-8",
+		Marshal:     json.Marshal,
+		Unmarshal:   json.Unmarshal,
+	}
+}
+
+// XML is used to create xml codec.
+func XML() Codec {
+	return Codec{
+		MimeTypes:   []string{string(models.MimeTypesXML), "text/xml"},
+		ContentType: "application/xml; charset=UTF-8",
+		Marshal: func(v interface{}) ([]byte, error) {
+			b, err := xml.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return append([]byte(xml.Header), b...), nil
+		},
+		Unmarshal: xml.Unmarshal,
+	}
+}
+
+// YAML is used to create yaml codec, fields are named and ordered as in json.
+func YAML() Codec {
+	return Codec{
+		MimeTypes:   []string{string(models.MimeTypesYAML), "application/x-yaml", "text/yaml", "text/x-yaml"},
+		ContentType: "application/yaml; charset=UTF-8",
+		Marshal: func(v interface{}) ([]byte, error) {
+			generic, err := toGeneric(v)
+			if err != nil {
+				return nil, err
+			}
+			return yaml.Marshal(toYAML(generic))
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			var generic interface{}
+			err := yaml.Unmarshal(data, &generic)
+			if err != nil {
+				return err
+			}
+			return fromGeneric(generic, v)
+		},
+	}
+}
+
+// toYAML is used to convert json representation to ordered yaml maps.
+func toYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case object:
+		m := make(yaml.MapSlice, len(t))
+		for i, member := range t {
+			m[i] = yaml.MapItem{Key: member.Key, Value: toYAML(member.Value)}
+		}
+		return m
+	case []interface{}:
+		for i, value := range t {
+			t[i] = toYAML(value)
+		}
+		return t
+	case json.Number:
+		return number(t)
+	default:
+		return v
+	}
+}
+
+// MessagePack is used to create msgpack codec, fields are named and ordered as in json.
+func MessagePack() Codec {
+	return Codec{
+		MimeTypes: []string{string(models.MimeTypesMsgPack), "application/x-msgpack", "application/vnd.msgpack"},
+		Marshal: func(v interface{}) ([]byte, error) {
+			generic, err := toGeneric(v)
+			if err != nil {
+				return nil, err
+			}
+			return msgpack.Marshal(toMsgPack(generic))
+		},
+		Unmarshal: func(data []byte, v interface{}) error {
+			var generic interface{}
+			err := msgpack.Unmarshal(data, &generic)
+			if err != nil {
+				return err
+			}
+			return fromGeneric(generic, v)
+		},
+	}
+}
+
+// msgpackObject represent object encoded as msgpack map keeping order of keys.
+type msgpackObject object
+
+// EncodeMsgpack is used to encode object keeping order of keys.
+func (o msgpackObject) EncodeMsgpack(enc *msgpack.Encoder) error {
+	err := enc.EncodeMapLen(len(o))
+	if err != nil {
+		return err
+	}
+	for _, member := range o {
+		err = enc.EncodeString(member.Key)
+		if err != nil {
+			return err
+		}
+		err = enc.Encode(member.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toMsgPack is used to convert json representation to ordered msgpack maps.
+func toMsgPack(v interface{}) interface{} {
+	switch t := v.(type) {
+	case object:
+		o := make(msgpackObject, len(t))
+		for i, member := range t {
+			o[i] = member
+			o[i].Value = toMsgPack(member.Value)
+		}
+		return o
+	case []interface{}:
+		for i, value := range t {
+			t[i] = toMsgPack(value)
+		}
+		return t
+	case json.Number:
+		return number(t)
+	default:
+		return v
+	}
+}
+
+// CSV is used to create csv codec of list responses, items of first list field of response are written as rows
+// with columns named as json fields, nested values are written as json. Other responses are not encodable.
+func CSV() Codec {
+	return Codec{
+		MimeTypes:   []string{string(models.MimeTypesCSV)},
+		ContentType: "text/csv; charset=UTF-8",
+		Marshal:     marshalCSV,
+	}
+}
+
+// marshalCSV is used to write list as csv table.
+func marshalCSV(v interface{}) ([]byte, error) {
+	list, ok := listOf(v)
+	if !ok {
+		return nil, ErrNotEncodable
+	}
+
+	generic, err := toGeneric(list)
+	if err != nil {
+		return nil, err
+	}
+	items, _ := generic.([]interface{})
+
+	// columns in order of appearance
+	var columns []string
+	seen := map[string]bool{}
+	rows := make([]object, len(items))
+	for i, item := range items {
+		o, ok := item.(object)
+		if !ok {
+			return nil, ErrNotEncodable
+		}
+		rows[i] = o
+		for _, member := range o {
+			if !seen[member.Key] {
+				seen[member.Key] = true
+				columns = append(columns, member.Key)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	err = w.Write(columns)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range rows {
+		values := make(map[string]interface{}, len(o))
+		for _, member := range o {
+			values[member.Key] = member.Value
+		}
+
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i], err = cell(values[column])
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = w.Write(record)
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+
+	return buf.Bytes(), w.Error()
+}
+
+// cell is used to format value of csv cell.
+func cell(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	default:
+		b, err := json.Marshal(t)
+		return string(b), err
+	}
+}
+
+// listOf is used to get list of value, value is list itself or struct with list field.
+func listOf(v interface{}) (interface{}, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+
+	if isList(rv.Type()) {
+		return rv.Interface(), true
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if field.PkgPath != "" || strings.Split(field.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+
+		t := field.Type
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if isList(t) {
+			return rv.Field(i).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// isList is used to check if type is slice or array, byte slices are not lists.
+func isList(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8
+}
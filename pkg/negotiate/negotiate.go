@@ -0,0 +1,290 @@
+package negotiate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Errors returned by negotiator.
+var (
+	// ErrNotAcceptable is returned when no codec produces media type accepted by client.
+	ErrNotAcceptable = errors.New("none of accepted media types is supported")
+	// ErrUnsupportedMediaType is returned when no codec decodes content type of request body.
+	ErrUnsupportedMediaType = errors.New("content type of request body is not supported")
+	// ErrNotEncodable is returned by codec which can not represent value, e.g. csv codec for non list value.
+	ErrNotEncodable = errors.New("value can not be encoded with codec")
+)
+
+// Codec represent encoding of media type, first mime type is canonical and used when client accepts any of them.
+type Codec struct {
+	MimeTypes []string
+	// ContentType written with encoded body, defaults to canonical mime type.
+	ContentType string
+	Marshal     func(v interface{}) ([]byte, error)
+	// Unmarshal is optional, codec without it is used only for responses.
+	Unmarshal func(data []byte, v interface{}) error
+}
+
+// MediaRange represent single media range of accept header.
+type MediaRange struct {
+	Type    string
+	Subtype string
+	Q       float64
+}
+
+// match is used to get specificity of range matching mime type, -1 is returned if range does not match.
+func (m MediaRange) match(mimeType string) int {
+	t, s, ok := splitMimeType(mimeType)
+	if !ok {
+		return -1
+	}
+	switch {
+	case m.Type == "*" && m.Subtype == "*":
+		return 0
+	case m.Type == t && m.Subtype == "*":
+		return 1
+	case m.Type == t && m.Subtype == s:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// ParseAccept is used to parse accept header into media ranges ordered by preference,
+// ranges with invalid quality are skipped.
+func ParseAccept(header string) []MediaRange {
+	var ranges []MediaRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mimeType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mimeType == "" {
+			continue
+		}
+		if mimeType == "*" {
+			mimeType = "*/*"
+		}
+		t, s, ok := splitMimeType(mimeType)
+		if !ok || (t == "*" && s != "*") {
+			continue
+		}
+
+		// quality defaults to 1
+		q := 1.0
+		valid := true
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.ToLower(strings.TrimSpace(kv[0])) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err != nil || v < 0 || v > 1 {
+				valid = false
+				break
+			}
+			q = v
+		}
+		if !valid {
+			continue
+		}
+
+		ranges = append(ranges, MediaRange{Type: t, Subtype: s, Q: q})
+	}
+
+	// prefer higher quality, then more specific ranges
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Q != ranges[j].Q {
+			return ranges[i].Q > ranges[j].Q
+		}
+		return specificity(ranges[i]) > specificity(ranges[j])
+	})
+
+	return ranges
+}
+
+// specificity is used to order ranges with same quality.
+func specificity(m MediaRange) int {
+	switch {
+	case m.Type == "*":
+		return 0
+	case m.Subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// splitMimeType is used to split mime type into type and subtype.
+func splitMimeType(mimeType string) (string, string, bool) {
+	parts := strings.SplitN(mimeType, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// Negotiator is used to pick codecs of responses by accept header and of request bodies by content type.
+type Negotiator struct {
+	codecs []Codec
+}
+
+// New is used to create negotiator, first codec is used when client accepts anything.
+func New(codecs ...Codec) *Negotiator {
+	return &Negotiator{codecs: codecs}
+}
+
+// Default is used to create negotiator with json, xml, yaml, msgpack and csv codecs.
+func Default() *Negotiator {
+	return New(JSON(), XML(), YAML(), MessagePack(), CSV())
+}
+
+// candidate represent codec accepted by client.
+type candidate struct {
+	codec    Codec
+	mimeType string
+	q        float64
+	index    int
+}
+
+// Acceptable is used to get codecs accepted by client ordered by preference with mime types matched,
+// quality of codec is taken from most specific range matching any of its mime types.
+func (n *Negotiator) Acceptable(accept string) ([]Codec, []string) {
+	ranges := ParseAccept(accept)
+	if strings.TrimSpace(accept) == "" {
+		ranges = []MediaRange{{Type: "*", Subtype: "*", Q: 1}}
+	}
+
+	var candidates []candidate
+	for i, codec := range n.codecs {
+		best := candidate{codec: codec, mimeType: codec.MimeTypes[0], index: i}
+		bestSpecificity := -1
+		for _, mimeType := range codec.MimeTypes {
+			for _, r := range ranges {
+				s := r.match(mimeType)
+				if s > bestSpecificity {
+					bestSpecificity = s
+					best.q = r.Q
+					// answer with mime type matched by range, e.g. alias requested explicitly or by its type
+					best.mimeType = mimeType
+				}
+			}
+		}
+		if bestSpecificity >= 0 && best.q > 0 {
+			candidates = append(candidates, best)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].index < candidates[j].index
+	})
+
+	codecs := make([]Codec, len(candidates))
+	mimeTypes := make([]string, len(candidates))
+	for i, c := range candidates {
+		codecs[i] = c.codec
+		mimeTypes[i] = c.mimeType
+	}
+	return codecs, mimeTypes
+}
+
+// Encode is used to encode value with most preferred codec which can represent it, returns content type and body.
+func (n *Negotiator) Encode(accept string, v interface{}) (string, []byte, error) {
+	codecs, mimeTypes := n.Acceptable(accept)
+	for i, codec := range codecs {
+		b, err := codec.Marshal(v)
+		if errors.Is(err, ErrNotEncodable) {
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return contentType(codec, mimeTypes[i]), b, nil
+	}
+	return "", nil, ErrNotAcceptable
+}
+
+// contentType is used to get content type header of codec answering with mime type.
+func contentType(codec Codec, mimeType string) string {
+	if codec.ContentType != "" && mimeType == codec.MimeTypes[0] {
+		return codec.ContentType
+	}
+	return mimeType
+}
+
+// Write is used to write value in format accepted by client, error responses fall back to default codec
+// so errors are not hidden. Nothing is written if value can not be encoded, e.g. ErrNotAcceptable is returned
+// if nothing is acceptable, so callers may answer with error instead.
+func (n *Negotiator) Write(w http.ResponseWriter, r *http.Request, statusCode int, v interface{}) error {
+	ctype, b, err := n.Encode(r.Header.Get("Accept"), v)
+	if errors.Is(err, ErrNotAcceptable) && statusCode >= http.StatusBadRequest && len(n.codecs) > 0 {
+		codec := n.codecs[0]
+		ctype = contentType(codec, codec.MimeTypes[0])
+		b, err = codec.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Content-Type", ctype)
+	w.WriteHeader(statusCode)
+	_, err = w.Write(b)
+	return err
+}
+
+// MimeTypes is used to get canonical mime types of codecs.
+func (n *Negotiator) MimeTypes() []string {
+	mimeTypes := make([]string, len(n.codecs))
+	for i, codec := range n.codecs {
+		mimeTypes[i] = codec.MimeTypes[0]
+	}
+	return mimeTypes
+}
+
+// Decoder is used to get codec decoding content type, empty content type is decoded with default codec.
+func (n *Negotiator) Decoder(ctype string) (Codec, bool) {
+	if strings.TrimSpace(ctype) == "" {
+		if len(n.codecs) == 0 {
+			return Codec{}, false
+		}
+		return n.codecs[0], n.codecs[0].Unmarshal != nil
+	}
+
+	mimeType, _, err := mime.ParseMediaType(ctype)
+	if err != nil {
+		return Codec{}, false
+	}
+	for _, codec := range n.codecs {
+		if codec.Unmarshal == nil {
+			continue
+		}
+		for _, m := range codec.MimeTypes {
+			if m == mimeType {
+				return codec, true
+			}
+		}
+	}
+	return Codec{}, false
+}
+
+// Decode is used to decode request body according to its content type.
+func (n *Negotiator) Decode(r *http.Request, v interface{}) error {
+	codec, ok := n.Decoder(r.Header.Get("Content-Type"))
+	if !ok {
+		return ErrUnsupportedMediaType
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read request body: %s", err)
+	}
+	return codec.Unmarshal(b, v)
+}
@@ -0,0 +1,164 @@
+package negotiate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAccept(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []MediaRange
+	}{
+		{name: "empty header", header: "", want: nil},
+		{name: "quality defaults to 1", header: "application/json", want: []MediaRange{{Type: "application", Subtype: "json", Q: 1}}},
+		{
+			name:   "ordered by quality",
+			header: "application/xml;q=0.5, application/json, text/csv;q=0.8",
+			want: []MediaRange{
+				{Type: "application", Subtype: "json", Q: 1},
+				{Type: "text", Subtype: "csv", Q: 0.8},
+				{Type: "application", Subtype: "xml", Q: 0.5},
+			},
+		},
+		{
+			name:   "same quality ordered by specificity",
+			header: "*/*, application/*, application/json",
+			want: []MediaRange{
+				{Type: "application", Subtype: "json", Q: 1},
+				{Type: "application", Subtype: "*", Q: 1},
+				{Type: "*", Subtype: "*", Q: 1},
+			},
+		},
+		{name: "case and spaces are ignored", header: " Application/JSON ; Q = 0.7 ", want: []MediaRange{{Type: "application", Subtype: "json", Q: 0.7}}},
+		{name: "other params are ignored", header: "application/json;charset=utf-8;q=0.3", want: []MediaRange{{Type: "application", Subtype: "json", Q: 0.3}}},
+		{name: "zero quality is kept", header: "application/json;q=0", want: []MediaRange{{Type: "application", Subtype: "json", Q: 0}}},
+		{name: "bare wildcard", header: "*", want: []MediaRange{{Type: "*", Subtype: "*", Q: 1}}},
+		{name: "quality above 1 is skipped", header: "application/json;q=2, text/csv", want: []MediaRange{{Type: "text", Subtype: "csv", Q: 1}}},
+		{name: "negative quality is skipped", header: "application/json;q=-1", want: nil},
+		{name: "malformed quality is skipped", header: "application/json;q=high", want: nil},
+		{name: "wildcard type with subtype is skipped", header: "*/json", want: nil},
+		{name: "missing subtype is skipped", header: "application, application/", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseAccept(tt.header), "invalid media ranges")
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	type item struct {
+		Name string `json:"name" xml:"name"`
+	}
+	type list struct {
+		Items []item `json:"items" xml:"items"`
+	}
+
+	n := Default()
+
+	tests := []struct {
+		name   string
+		accept string
+		value  interface{}
+		ctype  string
+		err    error
+	}{
+		{name: "empty accept", accept: "", value: item{}, ctype: "application/json; charset=UTF-8"},
+		{name: "any type", accept: "*/*", value: item{}, ctype: "application/json; charset=UTF-8"},
+		{name: "preferred type", accept: "application/json;q=0.5, application/xml", value: item{}, ctype: "application/xml; charset=UTF-8"},
+		{name: "alias of codec", accept: "text/xml", value: item{}, ctype: "text/xml"},
+		{name: "subtype wildcard", accept: "text/*", value: item{}, ctype: "text/xml"},
+		{name: "subtype wildcard of list", accept: "text/csv, text/*;q=0.5", value: list{}, ctype: "text/csv; charset=UTF-8"},
+		{name: "same quality keeps codec order", accept: "application/yaml, application/json", value: item{}, ctype: "application/json; charset=UTF-8"},
+		{name: "specific range overrides wildcard", accept: "*/*;q=0.9, application/json;q=0.1", value: item{}, ctype: "application/xml; charset=UTF-8"},
+		{name: "refused type", accept: "application/json;q=0, */*;q=0.1", value: item{}, ctype: "application/xml; charset=UTF-8"},
+		{name: "not encodable value is skipped", accept: "text/csv, application/json;q=0.5", value: item{}, ctype: "application/json; charset=UTF-8"},
+		{name: "unsupported type", accept: "image/png", value: item{}, err: ErrNotAcceptable},
+		{name: "only not encodable type", accept: "text/csv", value: item{}, err: ErrNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctype, _, err := n.Encode(tt.accept, tt.value)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err, "unexpected error")
+				return
+			}
+			require.NoError(t, err, "failed to encode value")
+			require.Equal(t, tt.ctype, ctype, "invalid content type")
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	n := Default()
+
+	tests := []struct {
+		name   string
+		status int
+		ctype  string
+		err    error
+	}{
+		{name: "success is not acceptable", status: http.StatusOK, err: ErrNotAcceptable},
+		{name: "error falls back to default codec", status: http.StatusNotFound, ctype: "application/json; charset=UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", "image/png")
+			w := httptest.NewRecorder()
+
+			err := n.Write(w, r, tt.status, map[string]string{"message": "value"})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err, "unexpected error")
+				require.Empty(t, w.Body.String(), "body was written")
+				return
+			}
+			require.NoError(t, err, "failed to write value")
+			require.Equal(t, tt.status, w.Code, "invalid status")
+			require.Equal(t, tt.ctype, w.Header().Get("Content-Type"), "invalid content type")
+			require.Equal(t, "Accept", w.Header().Get("Vary"), "vary header is missing")
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	n := Default()
+
+	tests := []struct {
+		name  string
+		ctype string
+		mime  string
+		found bool
+	}{
+		{name: "empty content type", ctype: "", mime: "application/json", found: true},
+		{name: "content type with params", ctype: "application/json; charset=utf-8", mime: "application/json", found: true},
+		{name: "alias of codec", ctype: "text/xml", mime: "application/xml", found: true},
+		{name: "codec without decoder", ctype: "text/csv", found: false},
+		{name: "unknown content type", ctype: "image/png", found: false},
+		{name: "malformed content type", ctype: "application/json;;", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, found := n.Decoder(tt.ctype)
+			require.Equal(t, tt.found, found, "unexpected lookup result")
+			if found {
+				require.Equal(t, tt.mime, codec.MimeTypes[0], "invalid codec")
+			}
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"value"}`))
+	r.Header.Set("Content-Type", "image/png")
+	var v map[string]string
+	require.ErrorIs(t, n.Decode(r, &v), ErrUnsupportedMediaType, "unsupported body was decoded")
+}
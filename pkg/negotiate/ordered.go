@@ -0,0 +1,141 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// member represent key and value of json object.
+type member struct {
+	Key   string
+	Value interface{}
+}
+
+// object represent json object keeping order of keys, so other formats list fields in same order as json.
+type object []member
+
+// MarshalJSON is used to encode object keeping order of keys.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toGeneric is used to get json representation of value made of objects, slices, strings, numbers, bools and nils,
+// so every format respects json tags and custom json marshalers of models.
+func toGeneric(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+// decodeValue is used to decode next json value keeping order of object keys.
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			o := object{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				o = append(o, member{Key: key.(string), Value: value})
+			}
+			_, err = dec.Token()
+			return o, err
+		case '[':
+			a := []interface{}{}
+			for dec.More() {
+				value, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				a = append(a, value)
+			}
+			_, err = dec.Token()
+			return a, err
+		default:
+			return nil, fmt.Errorf("unexpected delimiter %s", t)
+		}
+	default:
+		return t, nil
+	}
+}
+
+// number is used to convert json number to integer if possible.
+func number(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// fromGeneric is used to decode value of other format into v through json, so json tags and validation apply.
+func fromGeneric(generic interface{}, v interface{}) error {
+	b, err := json.Marshal(stringKeys(generic))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// stringKeys is used to convert maps decoded by yaml and msgpack to maps with string keys.
+func stringKeys(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, value := range t {
+			m[fmt.Sprint(k)] = stringKeys(value)
+		}
+		return m
+	case map[string]interface{}:
+		for k, value := range t {
+			t[k] = stringKeys(value)
+		}
+		return t
+	case []interface{}:
+		for i, value := range t {
+			t[i] = stringKeys(value)
+		}
+		return t
+	default:
+		return v
+	}
+}
@@ -0,0 +1,113 @@
+package oauthstate
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// State represent per login oauth state, it is signed and carried through provider untouched,
+// nonce binds state to cookie set in browser which started login,
+// user id is set if provider is linked to account of authenticated user instead of login.
+type State struct {
+	Nonce     string `json:"n"`
+	Provider  string `json:"p"`
+	Redirect  string `json:"r,omitempty"`
+	UserID    string `json:"u,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Errors returned by Decode.
+var (
+	ErrMalformed = errors.New("malformed state")
+	ErrSignature = errors.New("invalid state signature")
+	ErrExpired   = errors.New("state is expired")
+)
+
+// New is used to create state with random nonce which expires after ttl.
+func New(provider string, redirect string, ttl time.Duration) (*State, error) {
+	nonce, err := random(16)
+	if err != nil {
+		return nil, err
+	}
+
+	return &State{
+		Nonce:     nonce,
+		Provider:  provider,
+		Redirect:  redirect,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// Encode is used to serialize state and sign it with hmac secret.
+func Encode(state *State, secret string) (string, error) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode state: %s", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + sign(payload, secret), nil
+}
+
+// Decode is used to verify signature and expiry of state.
+func Decode(s string, secret string) (*State, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 2 {
+		return nil, ErrMalformed
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(sign(parts[0], secret))) {
+		return nil, ErrSignature
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var state State
+	err = json.Unmarshal(b, &state)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	if time.Now().Unix() >= state.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &state, nil
+}
+
+// NewVerifier is used to generate PKCE code verifier.
+func NewVerifier() (string, error) {
+	return random(32)
+}
+
+// Challenge is used to derive S256 PKCE code challenge from verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// sign is used to compute url safe hmac of payload.
+func sign(payload string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// random is used to generate url safe random string of n bytes.
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random value: %s", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package oauthstate
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "secret"
+
+func TestDecode(t *testing.T) {
+	valid, err := New("google", "/profile", time.Minute)
+	require.NoError(t, err, "failed to create state")
+	encoded, err := Encode(valid, secret)
+	require.NoError(t, err, "failed to encode state")
+
+	expired, err := Encode(&State{Nonce: "nonce", Provider: "google", ExpiresAt: time.Now().Add(-time.Second).Unix()}, secret)
+	require.NoError(t, err, "failed to encode state")
+
+	// payload of other state signed by original signature
+	parts := strings.Split(encoded, ".")
+	forged, err := Encode(&State{Nonce: valid.Nonce, Provider: valid.Provider, UserID: "admin", ExpiresAt: valid.ExpiresAt}, secret)
+	require.NoError(t, err, "failed to encode state")
+	tampered := strings.Split(forged, ".")[0] + "." + parts[1]
+
+	// properly signed payload which is not json
+	garbage := base64.RawURLEncoding.EncodeToString([]byte("garbage"))
+	garbage = garbage + "." + sign(garbage, secret)
+
+	tests := []struct {
+		name   string
+		state  string
+		secret string
+		err    error
+	}{
+		{name: "valid state", state: encoded, secret: secret},
+		{name: "expired state", state: expired, secret: secret, err: ErrExpired},
+		{name: "tampered payload", state: tampered, secret: secret, err: ErrSignature},
+		{name: "tampered signature", state: parts[0] + "." + parts[1][1:], secret: secret, err: ErrSignature},
+		{name: "other secret", state: encoded, secret: "other", err: ErrSignature},
+		{name: "missing signature", state: parts[0], secret: secret, err: ErrMalformed},
+		{name: "extra part", state: encoded + ".extra", secret: secret, err: ErrMalformed},
+		{name: "invalid payload", state: garbage, secret: secret, err: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := Decode(tt.state, tt.secret)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err, "unexpected error")
+				require.Nil(t, state, "state was returned")
+				return
+			}
+			require.NoError(t, err, "failed to decode state")
+			require.Equal(t, valid, state, "decoded state differs")
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	a, err := New("google", "", time.Minute)
+	require.NoError(t, err, "failed to create state")
+	b, err := New("google", "", time.Minute)
+	require.NoError(t, err, "failed to create state")
+
+	require.NotEmpty(t, a.Nonce, "nonce is empty")
+	require.NotEqual(t, a.Nonce, b.Nonce, "nonce is reused")
+}
+
+func TestChallenge(t *testing.T) {
+	verifier, err := NewVerifier()
+	require.NoError(t, err, "failed to create verifier")
+	other, err := NewVerifier()
+	require.NoError(t, err, "failed to create verifier")
+
+	tests := []struct {
+		name      string
+		verifier  string
+		challenge string
+		match     bool
+	}{
+		{name: "same verifier", verifier: verifier, challenge: Challenge(verifier), match: true},
+		{name: "other verifier", verifier: other, challenge: Challenge(verifier), match: false},
+		{name: "plain verifier", verifier: verifier, challenge: verifier, match: false},
+	}
+
+	// S256 challenge is unpadded url safe encoding of sha256
+	require.Len(t, Challenge(verifier), 43, "invalid length of challenge")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.match, Challenge(tt.verifier) == tt.challenge, "unexpected challenge match")
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/jwk"
+	"golang.org/x/oauth2"
+)
+
+// Metadata represent subset of provider metadata served by discovery endpoint.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// keyRefreshInterval limits how often unknown key id triggers refetching of key set.
+const keyRefreshInterval = time.Minute
+
+// Provider is OpenID Connect provider, metadata is discovered on first use and key set is cached
+// until token signed with unknown key arrives, so providers may rotate keys.
+type Provider struct {
+	issuer string
+	client *http.Client
+
+	mu        sync.Mutex
+	metadata  *Metadata
+	keys      jwk.Set
+	fetchedAt time.Time
+}
+
+// New is used to create provider of issuer, requests are sent with provided client.
+func New(issuer string, client *http.Client) *Provider {
+	return &Provider{
+		issuer: strings.TrimSuffix(issuer, "/"),
+		client: client,
+	}
+}
+
+// Metadata is used to get provider metadata, discovery result is cached once it succeeds.
+func (p *Provider) Metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata Metadata
+	err := p.get(ctx, p.issuer+"/.well-known/openid-configuration", "", &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover provider: %s", err)
+	}
+
+	// issuer must match exactly to prevent mix-up of providers
+	if strings.TrimSuffix(metadata.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("issuer %q does not match discovered issuer %q", p.issuer, metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("discovered metadata is incomplete")
+	}
+
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// Endpoint is used to get oauth2 endpoint of provider.
+func (p *Provider) Endpoint(ctx context.Context) (oauth2.Endpoint, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return oauth2.Endpoint{}, err
+	}
+
+	return oauth2.Endpoint{
+		AuthURL:  metadata.AuthorizationEndpoint,
+		TokenURL: metadata.TokenEndpoint,
+	}, nil
+}
+
+// key is used to find signing key by id, key set is refetched if key is unknown.
+func (p *Provider) key(ctx context.Context, kid string) (jwk.Key, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return jwk.Key{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.keys.Find(kid)
+	if ok {
+		return key, nil
+	}
+	if time.Since(p.fetchedAt) < keyRefreshInterval {
+		return jwk.Key{}, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var keys jwk.Set
+	err = p.get(ctx, metadata.JWKSURI, "", &keys)
+	if err != nil {
+		return jwk.Key{}, fmt.Errorf("failed to fetch key set: %s", err)
+	}
+	p.keys = keys
+	p.fetchedAt = time.Now()
+
+	key, ok = p.keys.Find(kid)
+	if !ok {
+		return jwk.Key{}, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// UserInfo is used to request claims of user from userinfo endpoint.
+func (p *Provider) UserInfo(ctx context.Context, token *oauth2.Token) (*Claims, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if metadata.UserinfoEndpoint == "" {
+		return nil, fmt.Errorf("provider does not have userinfo endpoint")
+	}
+
+	var claims Claims
+	err = p.get(ctx, metadata.UserinfoEndpoint, token.AccessToken, &claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %s", err)
+	}
+
+	return &claims, nil
+}
+
+// get is used to request json document, bearer token is sent if provided.
+func (p *Provider) get(ctx context.Context, url string, bearer string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", res.StatusCode, url)
+	}
+
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,212 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/jwk"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+const clientID = "client"
+
+// testProvider is used to serve discovery and key set of provider signing tokens with its keys.
+type testProvider struct {
+	server  *httptest.Server
+	issuer  string
+	keys    map[string]*rsa.PrivateKey
+	fetches int32
+}
+
+// newTestProvider is used to start provider, discovered issuer is overridden if provided.
+func newTestProvider(t *testing.T, issuer string) *testProvider {
+	p := &testProvider{keys: map[string]*rsa.PrivateKey{}}
+	p.addKey(t, "k1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                issuer,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&p.fetches, 1)
+		var set jwk.Set
+		for kid, key := range p.keys {
+			k, _ := jwk.New(kid, "RS256", &key.PublicKey)
+			set.Keys = append(set.Keys, k)
+		}
+		json.NewEncoder(w).Encode(set)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	p.issuer = p.server.URL
+	if issuer == "" {
+		issuer = p.issuer
+	}
+	return p
+}
+
+// addKey is used to add signing key to key set.
+func (p *testProvider) addKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+	p.keys[kid] = key
+}
+
+// claims is used to create valid claims of token.
+func (p *testProvider) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   p.issuer,
+		"sub":   "123",
+		"aud":   clientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": "nonce",
+	}
+}
+
+// sign is used to sign claims with key of set.
+func (p *testProvider) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	raw, err := token.SignedString(p.keys[kid])
+	require.NoError(t, err, "failed to sign token")
+	return raw
+}
+
+func TestVerify(t *testing.T) {
+	p := newTestProvider(t, "")
+
+	// other key with id known to provider
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+	forged.Header["kid"] = "k1"
+	forgedRaw, err := forged.SignedString(other)
+	require.NoError(t, err, "failed to sign token")
+
+	// symmetric algorithm is not accepted even if key id is known
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims())
+	hmac.Header["kid"] = "k1"
+	hmacRaw, err := hmac.SignedString([]byte("secret"))
+	require.NoError(t, err, "failed to sign token")
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, p.claims())
+	unsigned.Header["kid"] = "k1"
+	unsignedRaw, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err, "failed to sign token")
+
+	// with modifies valid claims
+	with := func(key string, value interface{}) jwt.MapClaims {
+		claims := p.claims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		nonce string
+		err   bool
+	}{
+		{name: "valid token", token: p.sign(t, "k1", p.claims()), nonce: "nonce"},
+		{name: "nonce not checked", token: p.sign(t, "k1", with("nonce", nil)), nonce: ""},
+		{name: "audience list without authorized party", token: p.sign(t, "k1", with("aud", []string{clientID, "other"})), nonce: "nonce", err: true},
+		{name: "other issuer", token: p.sign(t, "k1", with("iss", "https://evil.example.com")), nonce: "nonce", err: true},
+		{name: "other audience", token: p.sign(t, "k1", with("aud", "other")), nonce: "nonce", err: true},
+		{name: "missing audience", token: p.sign(t, "k1", with("aud", nil)), nonce: "nonce", err: true},
+		{name: "nonce mismatch", token: p.sign(t, "k1", p.claims()), nonce: "other", err: true},
+		{name: "missing nonce", token: p.sign(t, "k1", with("nonce", nil)), nonce: "nonce", err: true},
+		{name: "expired token", token: p.sign(t, "k1", with("exp", time.Now().Add(-2*leeway).Unix())), nonce: "nonce", err: true},
+		{name: "missing expiry", token: p.sign(t, "k1", with("exp", nil)), nonce: "nonce", err: true},
+		{name: "issued in future", token: p.sign(t, "k1", with("iat", time.Now().Add(2*leeway).Unix())), nonce: "nonce", err: true},
+		{name: "empty subject", token: p.sign(t, "k1", with("sub", nil)), nonce: "nonce", err: true},
+		{name: "invalid signature", token: forgedRaw, nonce: "nonce", err: true},
+		{name: "symmetric algorithm", token: hmacRaw, nonce: "nonce", err: true},
+		{name: "none algorithm", token: unsignedRaw, nonce: "nonce", err: true},
+		{name: "malformed token", token: "malformed", nonce: "nonce", err: true},
+	}
+
+	provider := New(p.issuer, p.server.Client())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.Verify(context.Background(), tt.token, clientID, tt.nonce)
+			if tt.err {
+				require.Error(t, err, "token was accepted")
+				return
+			}
+			require.NoError(t, err, "failed to verify token")
+			require.Equal(t, "123", claims.Subject, "invalid subject")
+		})
+	}
+
+	// several audiences require client to be authorized party
+	claims := with("aud", []string{clientID, "other"})
+	claims["azp"] = clientID
+	_, err = provider.Verify(context.Background(), p.sign(t, "k1", claims), clientID, "nonce")
+	require.NoError(t, err, "failed to verify token of authorized party")
+}
+
+func TestMetadataIssuerMismatch(t *testing.T) {
+	p := newTestProvider(t, "https://evil.example.com")
+
+	provider := New(p.issuer, p.server.Client())
+	_, err := provider.Metadata(context.Background())
+	require.Error(t, err, "metadata of other issuer was accepted")
+}
+
+func TestKeyRotation(t *testing.T) {
+	p := newTestProvider(t, "")
+	provider := New(p.issuer, p.server.Client())
+
+	removed := p.sign(t, "k1", p.claims())
+	_, err := provider.Verify(context.Background(), removed, clientID, "nonce")
+	require.NoError(t, err, "failed to verify token")
+	require.Equal(t, int32(1), atomic.LoadInt32(&p.fetches), "key set was not fetched once")
+
+	// key set is cached for known keys
+	_, err = provider.Verify(context.Background(), p.sign(t, "k1", p.claims()), clientID, "nonce")
+	require.NoError(t, err, "failed to verify token")
+	require.Equal(t, int32(1), atomic.LoadInt32(&p.fetches), "key set was refetched for known key")
+
+	// provider rotates keys, unknown key does not refetch set right after fetch
+	p.addKey(t, "k2")
+	rotated := p.sign(t, "k2", p.claims())
+	_, err = provider.Verify(context.Background(), rotated, clientID, "nonce")
+	require.Error(t, err, "token of unknown key was accepted")
+	require.Equal(t, int32(1), atomic.LoadInt32(&p.fetches), "key set was refetched within refresh interval")
+
+	// unknown key refetches set once refresh interval passed
+	provider.fetchedAt = time.Now().Add(-keyRefreshInterval)
+	_, err = provider.Verify(context.Background(), rotated, clientID, "nonce")
+	require.NoError(t, err, "failed to verify token of rotated key")
+	require.Equal(t, int32(2), atomic.LoadInt32(&p.fetches), "key set was not refetched")
+
+	// removed keys are rejected once set is refetched
+	delete(p.keys, "k1")
+	p.addKey(t, "k3")
+	provider.fetchedAt = time.Now().Add(-keyRefreshInterval)
+	_, err = provider.Verify(context.Background(), p.sign(t, "k3", p.claims()), clientID, "nonce")
+	require.NoError(t, err, "failed to verify token of rotated key")
+	_, err = provider.Verify(context.Background(), p.sign(t, "k2", p.claims()), clientID, "nonce")
+	require.NoError(t, err, "failed to verify token of kept key")
+	_, err = provider.Verify(context.Background(), removed, clientID, "nonce")
+	require.Error(t, err, "token of removed key was accepted")
+}
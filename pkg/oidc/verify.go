@@ -0,0 +1,122 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// leeway is allowed clock skew between provider and service.
+const leeway = time.Minute
+
+// signingMethods lists asymmetric algorithms accepted in ID tokens.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Audience represent aud claim which is either string or array of strings.
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if json.Unmarshal(b, &single) == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	err := json.Unmarshal(b, &many)
+	if err != nil {
+		return fmt.Errorf("malformed audience: %s", err)
+	}
+	*a = many
+	return nil
+}
+
+// Contains is used to check if audience includes client id.
+func (a Audience) Contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims represent ID token and userinfo claims used for login.
+type Claims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        Audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	ExpiresAt       int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	Nonce           string   `json:"nonce"`
+
+	Email             string `json:"email"`
+	EmailVerified     *bool  `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+}
+
+// Valid implements jwt.Claims, expiry and issue time are checked with leeway.
+func (c *Claims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(leeway)) {
+		return errors.New("token is expired")
+	}
+	if now.Add(leeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("token is issued in future")
+	}
+	return nil
+}
+
+// Verify is used to verify signature and claims of ID token issued to client,
+// nonce is compared if provided.
+func (p *Provider) Verify(ctx context.Context, raw string, clientID string, nonce string) (*Claims, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.Parser{ValidMethods: signingMethods}
+	var claims Claims
+	_, err = parser.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		return key.PublicKey()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %s", err)
+	}
+
+	if claims.Issuer != metadata.Issuer {
+		return nil, fmt.Errorf("invalid id token: unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.Audience.Contains(clientID) {
+		return nil, fmt.Errorf("invalid id token: not issued for client")
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != clientID {
+		return nil, fmt.Errorf("invalid id token: unexpected authorized party %q", claims.AuthorizedParty)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid id token: nonce does not match")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid id token: empty subject")
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,155 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Limits of listing page size.
+const (
+	DefaultLimit = 10
+	MaxLimit     = 100
+)
+
+// Direction represent direction of cursor relative to listing order.
+type Direction string
+
+// Cursor directions.
+const (
+	DirectionNext Direction = "next"
+	DirectionPrev Direction = "prev"
+)
+
+// Key represent position of record in listing ordered by created_at and id.
+type Key struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// Cursor represent opaque position in listing and direction to move from it.
+type Cursor struct {
+	Key
+	Direction Direction `json:"direction"`
+}
+
+// Page represent cursors of fetched listing page.
+type Page struct {
+	NextCursor string
+	PrevCursor string
+}
+
+// Encode is used to encode cursor to opaque string.
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode is used to decode cursor from opaque string, returns nil cursor for empty string.
+func Decode(cursor string) (*Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %s", err)
+	}
+
+	var c Cursor
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor: %s", err)
+	}
+
+	if c.Direction != DirectionNext && c.Direction != DirectionPrev {
+		return nil, fmt.Errorf("malformed cursor: unknown direction %q", c.Direction)
+	}
+
+	return &c, nil
+}
+
+// Limit is used to apply default page size and cap it server side.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// Apply is used to scope statement to page ordered by created_at and id descending,
+// uses keyset conditions when cursor is provided and offset otherwise,
+// one extra row is requested in order to detect if there are more rows.
+func Apply(stmt *gorm.DB, cursor *Cursor, limit int, offset int) *gorm.DB {
+	desc := true
+	if cursor != nil {
+		if cursor.Direction == DirectionNext {
+			stmt = stmt.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		} else {
+			// walk backwards in ascending order, rows are reversed by Trim
+			stmt = stmt.Where("created_at > ? OR (created_at = ? AND id > ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+			desc = false
+		}
+	} else if offset > 0 {
+		stmt = stmt.Offset(offset)
+	}
+
+	return stmt.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc}).
+		Limit(limit + 1)
+}
+
+// Trim is used to normalize rows fetched with Apply, expects pointer to slice,
+// drops extra row, restores descending order and reports if there are more rows in cursor direction.
+func Trim(rows interface{}, cursor *Cursor, limit int) bool {
+	v := reflect.ValueOf(rows).Elem()
+
+	more := v.Len() > limit
+	if more {
+		v.Set(v.Slice(0, limit))
+	}
+
+	if cursor != nil && cursor.Direction == DirectionPrev {
+		swap := reflect.Swapper(v.Interface())
+		for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	return more
+}
+
+// NewPage is used to build cursors around fetched page using keys of its first and last rows.
+func NewPage(cursor *Cursor, offset int, more bool, first Key, last Key) Page {
+	var page Page
+
+	// next page exists if there are more rows forward or if we walked backwards
+	hasNext := more
+	hasPrev := offset > 0
+	if cursor != nil {
+		hasPrev = cursor.Direction == DirectionNext
+		if cursor.Direction == DirectionPrev {
+			hasNext = true
+			hasPrev = more
+		}
+	}
+
+	if hasNext {
+		page.NextCursor = Cursor{Key: last, Direction: DirectionNext}.Encode()
+	}
+	if hasPrev {
+		page.PrevCursor = Cursor{Key: first, Direction: DirectionPrev}.Encode()
+	}
+
+	return page
+}
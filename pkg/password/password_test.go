@@ -0,0 +1,109 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cheapParams keep tests fast.
+var cheapParams = Params{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  8,
+	KeyLength:   16,
+}
+
+func TestVerify(t *testing.T) {
+	encoded, err := HashWithParams("password", cheapParams)
+	require.NoError(t, err, "failed to hash password")
+	parts := strings.Split(encoded, "$")
+
+	// with is used to replace part of encoded hash
+	with := func(i int, value string) string {
+		p := append([]string{}, parts...)
+		p[i] = value
+		return strings.Join(p, "$")
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		valid    bool
+		err      error
+	}{
+		{name: "matching password", password: "password", encoded: encoded, valid: true},
+		{name: "other password", password: "other", encoded: encoded, valid: false},
+		{name: "other params", password: "password", encoded: with(3, "m=64,t=2,p=1"), valid: false},
+		{name: "other algorithm", password: "password", encoded: with(1, "argon2i"), err: ErrMalformedHash},
+		{name: "other version", password: "password", encoded: with(2, "v=16"), err: ErrMalformedHash},
+		{name: "malformed version", password: "password", encoded: with(2, "version"), err: ErrMalformedHash},
+		{name: "malformed params", password: "password", encoded: with(3, "m=64,t=1"), err: ErrMalformedHash},
+		{name: "non numeric params", password: "password", encoded: with(3, "m=a,t=1,p=1"), err: ErrMalformedHash},
+		{name: "parallelism overflow", password: "password", encoded: with(3, "m=64,t=1,p=256"), err: ErrMalformedHash},
+		{name: "malformed salt", password: "password", encoded: with(4, "!!!"), err: ErrMalformedHash},
+		{name: "empty key", password: "password", encoded: with(5, ""), err: ErrMalformedHash},
+		{name: "missing part", password: "password", encoded: strings.Join(parts[:5], "$"), err: ErrMalformedHash},
+		{name: "bcrypt hash", password: "password", encoded: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", err: ErrMalformedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := Verify(tt.password, tt.encoded)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err, "unexpected error")
+				return
+			}
+			require.NoError(t, err, "failed to verify password")
+			require.Equal(t, tt.valid, valid, "unexpected verification result")
+		})
+	}
+}
+
+func TestHashWithParams(t *testing.T) {
+	a, err := HashWithParams("password", cheapParams)
+	require.NoError(t, err, "failed to hash password")
+	b, err := HashWithParams("password", cheapParams)
+	require.NoError(t, err, "failed to hash password")
+	require.NotEqual(t, a, b, "salt is reused")
+
+	require.True(t, strings.HasPrefix(a, "$argon2id$v=19$m=64,t=1,p=1$"), "params are not encoded")
+	p, salt, key, err := decode(a)
+	require.NoError(t, err, "failed to decode hash")
+	require.Equal(t, cheapParams, p, "decoded params differ")
+	require.Len(t, salt, int(cheapParams.SaltLength), "invalid salt length")
+	require.Len(t, key, int(cheapParams.KeyLength), "invalid key length")
+}
+
+func TestNeedsRehash(t *testing.T) {
+	current, err := Hash("password")
+	require.NoError(t, err, "failed to hash password")
+	cheap, err := HashWithParams("password", cheapParams)
+	require.NoError(t, err, "failed to hash password")
+
+	// salt length is not part of params which require rehash
+	longSalt := DefaultParams
+	longSalt.SaltLength = 32
+	salted, err := HashWithParams("password", longSalt)
+	require.NoError(t, err, "failed to hash password")
+
+	tests := []struct {
+		name    string
+		encoded string
+		rehash  bool
+	}{
+		{name: "default params", encoded: current, rehash: false},
+		{name: "other salt length", encoded: salted, rehash: false},
+		{name: "other params", encoded: cheap, rehash: true},
+		{name: "malformed hash", encoded: "malformed", rehash: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.rehash, NeedsRehash(tt.encoded), "unexpected rehash result")
+		})
+	}
+}
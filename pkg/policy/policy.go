@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"errors"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Resource represent kind of resource protected by policy.
+type Resource string
+
+// Resources.
+const (
+	ResourcePost    Resource = "post"
+	ResourceComment Resource = "comment"
+	ResourceUser    Resource = "user"
+)
+
+// Action represent action performed over resource.
+type Action string
+
+// Actions.
+const (
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// ErrForbidden is returned when token holder is not allowed to perform action.
+var ErrForbidden = errors.New("action is forbidden")
+
+// rules describe which actions role can perform over resources owned by other users,
+// owners are always allowed to update and delete their own records.
+var rules = map[models.UserRole]map[Resource][]Action{
+	models.UserRoleAdmin: {
+		ResourcePost:    {ActionUpdate, ActionDelete},
+		ResourceComment: {ActionUpdate, ActionDelete},
+		ResourceUser:    {ActionUpdate},
+	},
+	models.UserRoleModerator: {
+		ResourceComment: {ActionUpdate, ActionDelete},
+	},
+}
+
+// Authorize is used to check if token holder can perform action over resource owned by provided user.
+func Authorize(token *access.Token, resource Resource, action Action, ownerID uuid.UUID) error {
+	if token == nil {
+		return ErrForbidden
+	}
+
+	// owner can manage own resources
+	if token.UserID == ownerID {
+		return nil
+	}
+
+	// check if role grants access to resources of other users
+	for _, allowed := range rules[token.UserRole][resource] {
+		if allowed == action {
+			return nil
+		}
+	}
+
+	return ErrForbidden
+}
+
+// HasRole is used to check if token holder has one of provided roles.
+func HasRole(token *access.Token, roles ...models.UserRole) bool {
+	if token == nil {
+		return false
+	}
+
+	for _, role := range roles {
+		if token.UserRole == role {
+			return true
+		}
+	}
+
+	return false
+}
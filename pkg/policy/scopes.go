@@ -0,0 +1,55 @@
+package policy
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/access"
+)
+
+// Scopes of personal access tokens.
+const (
+	ScopePostsRead     = "posts:read"
+	ScopePostsWrite    = "posts:write"
+	ScopeCommentsRead  = "comments:read"
+	ScopeCommentsWrite = "comments:write"
+	ScopeUsersRead     = "users:read"
+	ScopeUsersWrite    = "users:write"
+	ScopeTokensRead    = "tokens:read"
+	ScopeTokensWrite   = "tokens:write"
+)
+
+// Scopes lists scopes which can be granted to personal access tokens.
+var Scopes = []string{
+	ScopePostsRead,
+	ScopePostsWrite,
+	ScopeCommentsRead,
+	ScopeCommentsWrite,
+	ScopeUsersRead,
+	ScopeUsersWrite,
+	ScopeTokensRead,
+	ScopeTokensWrite,
+}
+
+// HasScopes is used to check if token holder was granted all provided scopes,
+// session tokens carry no scopes and are granted every scope.
+func HasScopes(token *access.Token, scopes ...string) bool {
+	if token == nil {
+		return false
+	}
+	if token.Scopes == nil {
+		return true
+	}
+
+	for _, scope := range scopes {
+		var granted bool
+		for _, s := range token.Scopes {
+			if s == scope {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,67 @@
+package problem
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError represent failed validation rule of request field.
+type FieldError struct {
+	// Field is path of field named as in request, e.g. scopes[0].
+	Field string `json:"field" xml:"field"`
+	Rule  string `json:"rule" xml:"rule"`
+	Param string `json:"param,omitempty" xml:"param,omitempty"`
+	// Message is human readable description of failed rule in language accepted by client.
+	Message string `json:"message" xml:"message"`
+
+	kind reflect.Kind
+} // @name FieldError
+
+// Fields is used to get details of each invalid field from validator errors.
+func Fields(err error) []FieldError {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	fields := make([]FieldError, len(errs))
+	for i, fe := range errs {
+		// namespace starts with name of validated struct
+		field := fe.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+
+		fields[i] = FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: Translate(DefaultLanguage, fe.Tag(), fe.Param(), fe.Kind()),
+			kind:    fe.Kind(),
+		}
+	}
+	return fields
+}
+
+// Translate is used to describe failed rule of field in language.
+func (f FieldError) Translate(lang string) FieldError {
+	f.Message = Translate(lang, f.Rule, f.Param, f.kind)
+	return f
+}
+
+// FieldName is used by validator to name fields as in request, by json, query or form tag.
+func FieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,154 @@
+package problem
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages of validation messages.
+const (
+	English   = "en"
+	Ukrainian = "uk"
+)
+
+// DefaultLanguage is used if client accepts none of supported languages.
+const DefaultLanguage = English
+
+// catalogs contain messages of validation rules by language, messages of length rules are
+// keyed by kind of field as well, messages are formatted with parameter of rule.
+var catalogs = map[string]map[string]string{
+	English: {
+		"required":   "is required",
+		"email":      "must be a valid email address",
+		"url":        "must be a valid url",
+		"uuid":       "must be a valid uuid",
+		"oneof":      "must be one of %s",
+		"min":        "must be at least %s",
+		"min.string": "must contain at least %s characters",
+		"min.items":  "must contain at least %s items",
+		"max":        "must be at most %s",
+		"max.string": "must contain at most %s characters",
+		"max.items":  "must contain at most %s items",
+		"post":       "must reference an existing post",
+		"title":      "is longer than allowed for post title",
+		"nohtml":     "must not contain html",
+		"default":    "failed on %s rule",
+	},
+	Ukrainian: {
+		"required":   "є обов'язковим",
+		"email":      "має бути дійсною адресою електронної пошти",
+		"url":        "має бути дійсним url",
+		"uuid":       "має бути дійсним uuid",
+		"oneof":      "має бути одним із %s",
+		"min":        "має бути не менше %s",
+		"min.string": "кількість символів має бути не менше %s",
+		"min.items":  "кількість елементів має бути не менше %s",
+		"max":        "має бути не більше %s",
+		"max.string": "кількість символів має бути не більше %s",
+		"max.items":  "кількість елементів має бути не більше %s",
+		"post":       "має посилатися на існуючий допис",
+		"title":      "довший, ніж дозволено для заголовка допису",
+		"nohtml":     "не має містити html",
+		"default":    "не пройшло перевірку %s",
+	},
+}
+
+// aliases are rules described by messages of other rules.
+var aliases = map[string]string{
+	"gte":   "min",
+	"lte":   "max",
+	"uuid4": "uuid",
+}
+
+// Translate is used to describe failed rule with parameter of field of kind in language,
+// unknown languages are described in default language.
+func Translate(lang string, rule string, param string, kind reflect.Kind) string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = catalogs[DefaultLanguage]
+	}
+
+	key := rule
+	if alias, ok := aliases[rule]; ok {
+		key = alias
+	}
+	if key == "oneof" {
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+
+	// length rules are described by kind of field
+	switch kind {
+	case reflect.String:
+		if msg, ok := catalog[key+".string"]; ok {
+			return fmt.Sprintf(msg, param)
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if msg, ok := catalog[key+".items"]; ok {
+			return fmt.Sprintf(msg, param)
+		}
+	}
+
+	msg, ok := catalog[key]
+	if !ok {
+		return fmt.Sprintf(catalog["default"], rule)
+	}
+	if strings.Contains(msg, "%s") {
+		return fmt.Sprintf(msg, param)
+	}
+	return msg
+}
+
+// Language is used to select supported language from accept language header by quality,
+// only primary subtag is compared, default language is selected if none is supported.
+func Language(header string) string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		lang := strings.ToLower(strings.TrimSpace(params[0]))
+		if lang == "" {
+			continue
+		}
+		lang = strings.SplitN(lang, "-", 2)[0]
+
+		// quality defaults to 1
+		q := 1.0
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+			if err != nil || v < 0 || v > 1 {
+				q = 0
+				break
+			}
+			q = v
+		}
+		if q == 0 {
+			continue
+		}
+
+		tags = append(tags, tag{lang: lang, q: q})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	for _, t := range tags {
+		if t.lang == "*" {
+			return DefaultLanguage
+		}
+		if _, ok := catalogs[t.lang]; ok {
+			return t.lang
+		}
+	}
+	return DefaultLanguage
+}
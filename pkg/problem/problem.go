@@ -0,0 +1,166 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/labstack/echo/v4"
+)
+
+// Code represent machine readable kind of error, clients should tell errors apart by code.
+type Code string
+
+// Error codes.
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeNotAcceptable        Code = "not_acceptable"
+	CodeConflict             Code = "conflict"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeTooManyRequests      Code = "too_many_requests"
+	CodeInternal             Code = "internal"
+)
+
+// Error represent domain error rendered as problem details, cause is logged but never rendered.
+type Error struct {
+	Code   Code
+	Status int
+	// Detail is safe to show to client.
+	Detail string
+	// Fields are set by validation errors.
+	Fields []FieldError
+	Err    error
+}
+
+// Error is used to describe error with its cause.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s", e.Detail, e.Err)
+	}
+	return e.Detail
+}
+
+// Unwrap is used to get cause of error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap is used to attach cause of error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// New is used to create error with status and code.
+func New(status int, code Code, detail string) *Error {
+	return &Error{
+		Code:   code,
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// BadRequest is used to create error of malformed request.
+func BadRequest(detail string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, detail)
+}
+
+// Validation is used to create error of invalid request with details of each invalid field.
+func Validation(detail string, err error) *Error {
+	e := New(http.StatusBadRequest, CodeValidationFailed, detail)
+	e.Fields = Fields(err)
+	e.Err = err
+	return e
+}
+
+// Unauthorized is used to create error of missing or invalid credentials.
+func Unauthorized(detail string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, detail)
+}
+
+// Forbidden is used to create error of insufficient permissions.
+func Forbidden(detail string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, detail)
+}
+
+// NotFound is used to create error of missing resource.
+func NotFound(detail string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, detail)
+}
+
+// Conflict is used to create error of resource conflicting with existing one.
+func Conflict(detail string) *Error {
+	return New(http.StatusConflict, CodeConflict, detail)
+}
+
+// TooManyRequests is used to create error of exceeded rate limit.
+func TooManyRequests(detail string) *Error {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, detail)
+}
+
+// Internal is used to create error of unexpected failure, detail should not reveal internals.
+func Internal(detail string) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, detail)
+}
+
+// From is used to convert any error to domain error, errors of echo keep their status
+// and unknown errors are internal errors.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		detail := http.StatusText(he.Code)
+		if he.Code < http.StatusInternalServerError {
+			detail = fmt.Sprint(he.Message)
+		}
+		return New(he.Code, codeOf(he.Code), detail).Wrap(he.Internal)
+	}
+
+	switch {
+	case errors.Is(err, negotiate.ErrNotAcceptable):
+		return New(http.StatusNotAcceptable, CodeNotAcceptable, err.Error())
+	case errors.Is(err, negotiate.ErrUnsupportedMediaType):
+		return New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, err.Error())
+	default:
+		return Internal("internal server error").Wrap(err)
+	}
+}
+
+// codeOf is used to get code of http status.
+func codeOf(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return Code(strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"))
+}
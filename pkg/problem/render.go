@@ -0,0 +1,90 @@
+package problem
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+)
+
+// Problem represent problem details document of RFC 7807 extended with error code and invalid fields.
+type Problem struct {
+	XMLName  xml.Name     `json:"-" xml:"urn:ietf:rfc:7807 problem" swaggerignore:"true"`
+	Type     string       `json:"type" xml:"type"`
+	Title    string       `json:"title" xml:"title"`
+	Status   int          `json:"status" xml:"status"`
+	Detail   string       `json:"detail,omitempty" xml:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty" xml:"instance,omitempty"`
+	Code     Code         `json:"code" xml:"code"`
+	Errors   []FieldError `json:"errors,omitempty" xml:"errors>error,omitempty"`
+} // @name Problem
+
+// Media types of problem details.
+const (
+	MimeTypeJSON = "application/problem+json"
+	MimeTypeXML  = "application/problem+xml"
+)
+
+// TypePrefix is prefix of problem type, problem type is identified by code.
+const TypePrefix = "urn:gorest:problem:"
+
+// Problem is used to describe error of request to instance, messages of fields are in language.
+func (e *Error) Problem(instance string, lang string) Problem {
+	var fields []FieldError
+	for _, field := range e.Fields {
+		fields = append(fields, field.Translate(lang))
+	}
+
+	return Problem{
+		Type:     TypePrefix + string(e.Code),
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Detail,
+		Instance: instance,
+		Code:     e.Code,
+		Errors:   fields,
+	}
+}
+
+// Codecs of problem details, problem media type is written even if client accepts plain json or xml.
+var (
+	jsonCodec = negotiate.Codec{
+		MimeTypes:   []string{MimeTypeJSON, "application/json"},
+		ContentType: MimeTypeJSON,
+		Marshal:     negotiate.JSON().Marshal,
+	}
+	xmlCodec = negotiate.Codec{
+		MimeTypes:   []string{MimeTypeXML, "application/xml", "text/xml"},
+		ContentType: MimeTypeXML,
+		Marshal:     negotiate.XML().Marshal,
+	}
+	negotiator = negotiate.New(jsonCodec, xmlCodec)
+)
+
+// Write is used to write error as problem details in format accepted by client, json is written
+// if client accepts neither json nor xml so errors are never hidden, messages of fields are written
+// in language accepted by client.
+func Write(w http.ResponseWriter, r *http.Request, err error) error {
+	e := From(err)
+	lang := Language(r.Header.Get("Accept-Language"))
+	p := e.Problem(r.URL.Path, lang)
+
+	codec := jsonCodec
+	codecs, _ := negotiator.Acceptable(r.Header.Get("Accept"))
+	if len(codecs) > 0 {
+		codec = codecs[0]
+	}
+
+	b, err := codec.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Add("Vary", "Accept")
+	w.Header().Add("Vary", "Accept-Language")
+	w.Header().Set("Content-Type", codec.ContentType)
+	w.Header().Set("Content-Language", lang)
+	w.WriteHeader(p.Status)
+	_, err = w.Write(b)
+	return err
+}
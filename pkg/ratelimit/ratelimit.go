@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Limit represent token bucket refilled with rate tokens per second up to burst tokens.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// KeyType represent subject requests are counted by.
+type KeyType string
+
+// Key types, user key falls back to client ip for anonymous requests.
+const (
+	KeyUser KeyType = "user"
+	KeyIP   KeyType = "ip"
+)
+
+// Policy represent named limit of route group.
+type Policy struct {
+	Name   string
+	Limit  Limit
+	Period time.Duration
+	Key    KeyType
+}
+
+// NewPolicy is used to create policy allowing requests per period with burst,
+// burst defaults to requests.
+func NewPolicy(name string, requests int, period time.Duration, burst int, key KeyType) (Policy, error) {
+	if requests <= 0 || period <= 0 {
+		return Policy{}, fmt.Errorf("requests and period of policy %q must be positive", name)
+	}
+	if burst <= 0 {
+		burst = requests
+	}
+	switch key {
+	case KeyUser, KeyIP:
+	case "":
+		key = KeyUser
+	default:
+		return Policy{}, fmt.Errorf("unknown key %q of policy %q", key, name)
+	}
+
+	return Policy{
+		Name: name,
+		Limit: Limit{
+			Rate:  float64(requests) / period.Seconds(),
+			Burst: burst,
+		},
+		Period: period,
+		Key:    key,
+	}, nil
+}
+
+// Result represent state of bucket after request was counted.
+type Result struct {
+	Allowed bool
+	// Limit is capacity of bucket.
+	Limit int
+	// Remaining is number of requests allowed right now.
+	Remaining int
+	// Reset is time until bucket is full again.
+	Reset time.Duration
+	// RetryAfter is time until next request is allowed, zero if request was allowed.
+	RetryAfter time.Duration
+}
+
+// newResult is used to describe bucket holding tokens left after request.
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+// seconds is used to convert fractional seconds to duration.
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store represent pluggable storage of token buckets.
+type Store interface {
+	// Take is used to remove token from bucket of key if one is available.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter is used to count requests of subjects against named policies.
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+// NewLimiter is used to create limiter, policy named default is used for route groups without own policy.
+func NewLimiter(store Store, policies ...Policy) *Limiter {
+	l := &Limiter{
+		store:    store,
+		policies: map[string]Policy{},
+	}
+	for _, p := range policies {
+		l.policies[p.Name] = p
+	}
+	return l
+}
+
+// Policy is used to get policy by name falling back to default policy.
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	p, ok := l.policies[name]
+	if !ok {
+		p, ok = l.policies["default"]
+	}
+	return p, ok
+}
+
+// Allow is used to count request of subject against policy.
+func (l *Limiter) Allow(ctx context.Context, p Policy, subject string) (Result, error) {
+	return l.store.Take(ctx, fmt.Sprintf("ratelimit:%s:%s", p.Name, subject), p.Limit)
+}
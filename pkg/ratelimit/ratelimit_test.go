@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests int
+		period   time.Duration
+		burst    int
+		key      KeyType
+		want     Policy
+		err      bool
+	}{
+		{name: "burst defaults to requests", requests: 10, period: time.Second, key: KeyIP, want: Policy{Limit: Limit{Rate: 10, Burst: 10}, Period: time.Second, Key: KeyIP}},
+		{name: "rate per period", requests: 30, period: time.Minute, burst: 5, key: KeyUser, want: Policy{Limit: Limit{Rate: 0.5, Burst: 5}, Period: time.Minute, Key: KeyUser}},
+		{name: "key defaults to user", requests: 1, period: time.Second, want: Policy{Limit: Limit{Rate: 1, Burst: 1}, Period: time.Second, Key: KeyUser}},
+		{name: "zero requests", requests: 0, period: time.Second, err: true},
+		{name: "zero period", requests: 1, period: 0, err: true},
+		{name: "unknown key", requests: 1, period: time.Second, key: "header", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPolicy("policy", tt.requests, tt.period, tt.burst, tt.key)
+			if tt.err {
+				require.Error(t, err, "policy was created")
+				return
+			}
+			require.NoError(t, err, "failed to create policy")
+			tt.want.Name = "policy"
+			require.Equal(t, tt.want, p, "invalid policy")
+		})
+	}
+}
+
+func TestMemoryRefill(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 3}
+
+	tests := []struct {
+		name string
+		// taken is number of tokens taken before elapsed time passes
+		taken     int
+		elapsed   time.Duration
+		allowed   bool
+		remaining int
+	}{
+		{name: "full bucket", taken: 0, allowed: true, remaining: 2},
+		{name: "last token", taken: 2, allowed: true, remaining: 0},
+		{name: "empty bucket", taken: 3, allowed: false, remaining: 0},
+		{name: "partly refilled bucket", taken: 3, elapsed: 500 * time.Millisecond, allowed: false, remaining: 0},
+		{name: "refilled token", taken: 3, elapsed: time.Second, allowed: true, remaining: 0},
+		{name: "refilled tokens", taken: 3, elapsed: 2 * time.Second, allowed: true, remaining: 1},
+		{name: "refill capped by burst", taken: 3, elapsed: time.Hour, allowed: true, remaining: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemory()
+			for i := 0; i < tt.taken; i++ {
+				_, err := m.Take(context.Background(), "key", limit)
+				require.NoError(t, err, "failed to take token")
+			}
+			if b, ok := m.buckets["key"]; ok {
+				b.updated = b.updated.Add(-tt.elapsed)
+			}
+
+			res, err := m.Take(context.Background(), "key", limit)
+			require.NoError(t, err, "failed to take token")
+			require.Equal(t, tt.allowed, res.Allowed, "unexpected result")
+			require.Equal(t, tt.remaining, res.Remaining, "invalid remaining tokens")
+			require.Equal(t, limit.Burst, res.Limit, "invalid limit")
+			if tt.allowed {
+				require.Zero(t, res.RetryAfter, "retry after is set for allowed request")
+			} else {
+				require.True(t, res.RetryAfter > 0 && res.RetryAfter <= time.Second, "invalid retry after %s", res.RetryAfter)
+			}
+
+			// other keys have own buckets
+			res, err = m.Take(context.Background(), "other", limit)
+			require.NoError(t, err, "failed to take token")
+			require.Equal(t, limit.Burst-1, res.Remaining, "bucket of other key is shared")
+		})
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 1}
+	m := NewMemory()
+
+	_, err := m.Take(context.Background(), "full", limit)
+	require.NoError(t, err, "failed to take token")
+	_, err = m.Take(context.Background(), "empty", limit)
+	require.NoError(t, err, "failed to take token")
+
+	// bucket which would be full again is dropped on next sweep
+	m.buckets["full"].full = time.Now().Add(-time.Second)
+	m.lastSweep = time.Now().Add(-sweepInterval)
+	_, err = m.Take(context.Background(), "other", limit)
+	require.NoError(t, err, "failed to take token")
+
+	require.NotContains(t, m.buckets, "full", "full bucket was not swept")
+	require.Contains(t, m.buckets, "empty", "empty bucket was swept")
+}
+
+func TestRedisTake(t *testing.T) {
+	server, err := miniredis.Run()
+	require.NoError(t, err, "failed to start redis")
+	defer server.Close()
+
+	store := NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	limit := Limit{Rate: 1, Burst: 2}
+
+	tests := []struct {
+		name      string
+		key       string
+		allowed   bool
+		remaining int
+	}{
+		{name: "full bucket", key: "key", allowed: true, remaining: 1},
+		{name: "last token", key: "key", allowed: true, remaining: 0},
+		{name: "empty bucket", key: "key", allowed: false, remaining: 0},
+		{name: "bucket of other key", key: "other", allowed: true, remaining: 1},
+	}
+
+	// cases share buckets and run in order
+	for _, tt := range tests {
+		res, err := store.Take(context.Background(), tt.key, limit)
+		require.NoError(t, err, "failed to take token of %s", tt.name)
+		require.Equal(t, tt.allowed, res.Allowed, "unexpected result of %s", tt.name)
+		require.Equal(t, tt.remaining, res.Remaining, "invalid remaining tokens of %s", tt.name)
+	}
+
+	// bucket expires once it would be full again
+	ttl := server.TTL("key")
+	require.True(t, ttl > 0 && ttl <= 2*time.Second, "invalid ttl %s", ttl)
+}
+
+func TestLimiterPolicy(t *testing.T) {
+	def := Policy{Name: "default"}
+	auth := Policy{Name: "auth"}
+
+	tests := []struct {
+		name    string
+		limiter *Limiter
+		policy  string
+		want    Policy
+		found   bool
+	}{
+		{name: "own policy", limiter: NewLimiter(NewMemory(), def, auth), policy: "auth", want: auth, found: true},
+		{name: "default policy", limiter: NewLimiter(NewMemory(), def, auth), policy: "posts", want: def, found: true},
+		{name: "without default policy", limiter: NewLimiter(NewMemory(), auth), policy: "posts", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, found := tt.limiter.Policy(tt.policy)
+			require.Equal(t, tt.found, found, "unexpected lookup result")
+			require.Equal(t, tt.want, p, "invalid policy")
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// takeScript refills and takes token atomically, bucket expires once it would be full again.
+// Time is passed by caller in milliseconds, fractional values are returned as strings
+// because lua numbers are truncated to integers in replies.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.max(1, math.ceil((burst - tokens) / rate)))
+
+return {allowed, tostring(tokens)}
+`)
+
+// Redis is store shared by service instances.
+type Redis struct {
+	client redis.UniversalClient
+}
+
+// NewRedis is used to create store on top of redis client.
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{client: client}
+}
+
+// Take is used to remove token from bucket of key if one is available.
+func (r *Redis) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	perMillisecond := limit.Rate / 1000
+
+	reply, err := takeScript.Run(ctx, r.client, []string{key},
+		strconv.FormatFloat(perMillisecond, 'g', -1, 64),
+		limit.Burst,
+		now,
+	).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take token: %s", err)
+	}
+	if len(reply) != 2 {
+		return Result{}, fmt.Errorf("failed to take token: unexpected reply %v", reply)
+	}
+
+	allowed, ok := reply[0].(int64)
+	if !ok {
+		return Result{}, fmt.Errorf("failed to take token: unexpected reply %v", reply)
+	}
+	s, ok := reply[1].(string)
+	if !ok {
+		return Result{}, fmt.Errorf("failed to take token: unexpected reply %v", reply)
+	}
+	tokens, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take token: %s", err)
+	}
+
+	return newResult(limit, tokens, allowed == 1), nil
+}
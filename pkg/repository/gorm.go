@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewGorm is used to create repositories backed by database,
+// soft deleted records are excluded by default scope of models.
+func NewGorm(db *gorm.DB) Repositories {
+	return Repositories{
+		Posts:         &gormPosts{db: db},
+		Comments:      &gormComments{db: db},
+		Users:         &gormUsers{db: db},
+		AuthProviders: &gormAuthProviders{db: db},
+	}
+}
+
+// first is used to get first record matching conditions, missing record is reported as ErrNotFound.
+func first(stmt *gorm.DB, dest interface{}) error {
+	err := stmt.First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// updated is used to report update of missing record as ErrNotFound.
+func updated(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// list is used to count records matching statement and fetch page of them into rows, expects pointer to slice.
+func list(stmt *gorm.DB, rows interface{}, opts ListOptions) (ListResult, error) {
+	var res ListResult
+	if !opts.SkipTotal {
+		err := stmt.Session(&gorm.Session{}).Count(&res.Total).Error
+		if err != nil {
+			return ListResult{}, err
+		}
+	}
+
+	if opts.Limit <= 0 {
+		err := stmt.
+			Offset(opts.Offset).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: true}).
+			Find(rows).
+			Error
+		return res, err
+	}
+
+	err := pagination.Apply(stmt, opts.Cursor, opts.Limit, opts.Offset).Find(rows).Error
+	if err != nil {
+		return ListResult{}, err
+	}
+	res.More = pagination.Trim(rows, opts.Cursor, opts.Limit)
+	return res, nil
+}
+
+type gormPosts struct {
+	db *gorm.DB
+}
+
+func (r *gormPosts) Get(ctx context.Context, id uuid.UUID) (*models.Post, error) {
+	var post models.Post
+	err := first(r.db.WithContext(ctx).Model(&models.Post{}).Where(&models.Post{Base: models.Base{ID: id}}), &post)
+	if err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *gormPosts) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Post{}).
+		Where(&models.Post{Base: models.Base{ID: id}}).
+		Count(&count).
+		Error
+	return count > 0, err
+}
+
+func (r *gormPosts) List(ctx context.Context, opts ListOptions) ([]models.Post, ListResult, error) {
+	var posts []models.Post
+	res, err := list(r.db.WithContext(ctx).Model(&models.Post{}), &posts, opts)
+	return posts, res, err
+}
+
+func (r *gormPosts) Create(ctx context.Context, post *models.Post) error {
+	return r.db.WithContext(ctx).Model(&models.Post{}).Create(post).Error
+}
+
+func (r *gormPosts) Update(ctx context.Context, post *models.Post) error {
+	return updated(r.db.WithContext(ctx).Model(post).Select("title", "body", "updated_at").Updates(post))
+}
+
+func (r *gormPosts) Delete(ctx context.Context, post *models.Post) error {
+	return r.db.WithContext(ctx).Select(clause.Associations).Delete(post).Error
+}
+
+type gormComments struct {
+	db *gorm.DB
+}
+
+// filter is used to scope statement to comments matching filter.
+func (r *gormComments) filter(ctx context.Context, filter CommentFilter) *gorm.DB {
+	stmt := r.db.WithContext(ctx).Model(&models.Comment{})
+	if filter.PostID != uuid.Nil {
+		stmt = stmt.Where(&models.Comment{PostID: filter.PostID})
+	}
+	if filter.UserID != uuid.Nil {
+		stmt = stmt.Where(&models.Comment{UserID: filter.UserID})
+	}
+	return stmt
+}
+
+func (r *gormComments) Get(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	var comment models.Comment
+	err := first(r.db.WithContext(ctx).Model(&models.Comment{}).Where(&models.Comment{Base: models.Base{ID: id}}), &comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *gormComments) List(ctx context.Context, filter CommentFilter, opts ListOptions) ([]models.Comment, ListResult, error) {
+	var comments []models.Comment
+	res, err := list(r.filter(ctx, filter), &comments, opts)
+	return comments, res, err
+}
+
+func (r *gormComments) Count(ctx context.Context, filter CommentFilter) (int64, error) {
+	var count int64
+	err := r.filter(ctx, filter).Count(&count).Error
+	return count, err
+}
+
+func (r *gormComments) Create(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Model(&models.Comment{}).Create(comment).Error
+}
+
+func (r *gormComments) Update(ctx context.Context, comment *models.Comment) error {
+	return updated(r.db.WithContext(ctx).Model(comment).Select("name", "body", "updated_at").Updates(comment))
+}
+
+func (r *gormComments) Delete(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Delete(comment).Error
+}
+
+type gormUsers struct {
+	db *gorm.DB
+}
+
+func (r *gormUsers) Get(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := first(r.db.WithContext(ctx).Model(&models.User{}).Where(&models.User{Base: models.Base{ID: id}}), &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *gormUsers) List(ctx context.Context, filter UserFilter, opts ListOptions) ([]models.User, ListResult, error) {
+	stmt := r.db.WithContext(ctx).Model(&models.User{})
+	if filter.UserRole != "" {
+		stmt = stmt.Where(&models.User{UserRole: filter.UserRole})
+	}
+
+	var users []models.User
+	res, err := list(stmt, &users, opts)
+	return users, res, err
+}
+
+func (r *gormUsers) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Create(user).Error
+}
+
+func (r *gormUsers) Update(ctx context.Context, user *models.User) error {
+	return updated(r.db.WithContext(ctx).Model(user).Select("username", "avatar_url", "updated_at").Updates(user))
+}
+
+func (r *gormUsers) UpdateRole(ctx context.Context, user *models.User) error {
+	return updated(r.db.WithContext(ctx).Model(user).Select("user_role", "updated_at").Updates(user))
+}
+
+type gormAuthProviders struct {
+	db *gorm.DB
+}
+
+func (r *gormAuthProviders) Find(ctx context.Context, providerType models.AuthProviderType, providerUID string) (*models.AuthProvider, error) {
+	var authProvider models.AuthProvider
+	err := first(r.db.WithContext(ctx).
+		Model(&models.AuthProvider{}).
+		Where(&models.AuthProvider{ProviderUID: providerUID, AuthProviderType: providerType}), &authProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &authProvider, nil
+}
+
+func (r *gormAuthProviders) FindByUser(ctx context.Context, userID uuid.UUID, providerType models.AuthProviderType) (*models.AuthProvider, error) {
+	var authProvider models.AuthProvider
+	err := first(r.db.WithContext(ctx).
+		Model(&models.AuthProvider{}).
+		Where(&models.AuthProvider{UserID: userID, AuthProviderType: providerType}), &authProvider)
+	if err != nil {
+		return nil, err
+	}
+	return &authProvider, nil
+}
+
+func (r *gormAuthProviders) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.AuthProvider, error) {
+	var authProviders []models.AuthProvider
+	err := r.db.WithContext(ctx).
+		Model(&models.AuthProvider{}).
+		Where(&models.AuthProvider{UserID: userID}).
+		Order("created_at ASC").
+		Find(&authProviders).
+		Error
+	return authProviders, err
+}
+
+func (r *gormAuthProviders) Create(ctx context.Context, authProvider *models.AuthProvider) error {
+	return r.db.WithContext(ctx).Create(authProvider).Error
+}
+
+func (r *gormAuthProviders) UpdateRefreshToken(ctx context.Context, authProvider *models.AuthProvider) error {
+	return updated(r.db.WithContext(ctx).Model(authProvider).Select("refresh_token", "updated_at").Updates(authProvider))
+}
+
+func (r *gormAuthProviders) UpdatePassword(ctx context.Context, authProvider *models.AuthProvider) error {
+	return updated(r.db.WithContext(ctx).Model(authProvider).Select("password_hash", "updated_at").Updates(authProvider))
+}
+
+func (r *gormAuthProviders) Delete(ctx context.Context, authProvider *models.AuthProvider) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(authProvider).Error
+}
@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// memory is in-process store of records shared by memory repositories.
+type memory struct {
+	mu sync.RWMutex
+
+	posts         map[uuid.UUID]models.Post
+	comments      map[uuid.UUID]models.Comment
+	users         map[uuid.UUID]models.User
+	authProviders map[uuid.UUID]models.AuthProvider
+}
+
+// NewMemory is used to create repositories keeping records in process, useful for tests,
+// deleted records are dropped and writes are not seen by search backend.
+func NewMemory() Repositories {
+	m := &memory{
+		posts:         map[uuid.UUID]models.Post{},
+		comments:      map[uuid.UUID]models.Comment{},
+		users:         map[uuid.UUID]models.User{},
+		authProviders: map[uuid.UUID]models.AuthProvider{},
+	}
+
+	return Repositories{
+		Posts:         &memoryPosts{m},
+		Comments:      &memoryComments{m},
+		Users:         &memoryUsers{m},
+		AuthProviders: &memoryAuthProviders{m},
+	}
+}
+
+// create is used to set generated fields of new record, same as database.
+func create(base *models.Base) {
+	now := time.Now()
+	base.ID = uuid.New()
+	base.CreatedAt = now
+	base.UpdatedAt = now
+}
+
+// compareKeys is used to order keys by created_at and id, same as database.
+func compareKeys(a pagination.Key, b pagination.Key) int {
+	switch {
+	case a.CreatedAt.Before(b.CreatedAt):
+		return -1
+	case a.CreatedAt.After(b.CreatedAt):
+		return 1
+	}
+	return bytes.Compare([]byte(a.ID.String()), []byte(b.ID.String()))
+}
+
+// paginate is used to select indexes of keys on page in order of listing, same as pagination.Apply.
+func paginate(keys []pagination.Key, opts ListOptions) ([]int, ListResult) {
+	var res ListResult
+	if !opts.SkipTotal {
+		res.Total = int64(len(keys))
+	}
+
+	// walk backwards in ascending order from previous cursor
+	desc := opts.Cursor == nil || opts.Cursor.Direction == pagination.DirectionNext
+
+	indexes := make([]int, 0, len(keys))
+	for i, key := range keys {
+		if opts.Cursor != nil {
+			cmp := compareKeys(key, opts.Cursor.Key)
+			if (desc && cmp >= 0) || (!desc && cmp <= 0) {
+				continue
+			}
+		}
+		indexes = append(indexes, i)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		cmp := compareKeys(keys[indexes[i]], keys[indexes[j]])
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+
+	if opts.Cursor == nil && opts.Offset > 0 {
+		if opts.Offset >= len(indexes) {
+			indexes = indexes[:0]
+		} else {
+			indexes = indexes[opts.Offset:]
+		}
+	}
+
+	if opts.Limit <= 0 {
+		return indexes, res
+	}
+	if len(indexes) > opts.Limit+1 {
+		indexes = indexes[:opts.Limit+1]
+	}
+	res.More = pagination.Trim(&indexes, opts.Cursor, opts.Limit)
+	return indexes, res
+}
+
+type memoryPosts struct {
+	*memory
+}
+
+func (r *memoryPosts) Get(ctx context.Context, id uuid.UUID) (*models.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &post, nil
+}
+
+func (r *memoryPosts) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.posts[id]
+	return ok, nil
+}
+
+func (r *memoryPosts) List(ctx context.Context, opts ListOptions) ([]models.Post, ListResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var posts []models.Post
+	var keys []pagination.Key
+	for _, post := range r.posts {
+		posts = append(posts, post)
+		keys = append(keys, pagination.Key{CreatedAt: post.CreatedAt, ID: post.ID})
+	}
+
+	indexes, res := paginate(keys, opts)
+	page := make([]models.Post, len(indexes))
+	for i, index := range indexes {
+		page[i] = posts[index]
+	}
+	return page, res, nil
+}
+
+func (r *memoryPosts) Create(ctx context.Context, post *models.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	create(&post.Base)
+	r.posts[post.ID] = *post
+	return nil
+}
+
+func (r *memoryPosts) Update(ctx context.Context, post *models.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.posts[post.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	post.UpdatedAt = time.Now()
+	stored.Title = post.Title
+	stored.Body = post.Body
+	stored.UpdatedAt = post.UpdatedAt
+	r.posts[post.ID] = stored
+	return nil
+}
+
+func (r *memoryPosts) Delete(ctx context.Context, post *models.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.posts, post.ID)
+	for id, comment := range r.comments {
+		if comment.PostID == post.ID {
+			delete(r.comments, id)
+		}
+	}
+	return nil
+}
+
+type memoryComments struct {
+	*memory
+}
+
+// match is used to check if comment matches filter.
+func (f CommentFilter) match(comment models.Comment) bool {
+	return (f.PostID == uuid.Nil || comment.PostID == f.PostID) &&
+		(f.UserID == uuid.Nil || comment.UserID == f.UserID)
+}
+
+func (r *memoryComments) Get(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, ok := r.comments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &comment, nil
+}
+
+func (r *memoryComments) List(ctx context.Context, filter CommentFilter, opts ListOptions) ([]models.Comment, ListResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var comments []models.Comment
+	var keys []pagination.Key
+	for _, comment := range r.comments {
+		if !filter.match(comment) {
+			continue
+		}
+		comments = append(comments, comment)
+		keys = append(keys, pagination.Key{CreatedAt: comment.CreatedAt, ID: comment.ID})
+	}
+
+	indexes, res := paginate(keys, opts)
+	page := make([]models.Comment, len(indexes))
+	for i, index := range indexes {
+		page[i] = comments[index]
+	}
+	return page, res, nil
+}
+
+func (r *memoryComments) Count(ctx context.Context, filter CommentFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, comment := range r.comments {
+		if filter.match(comment) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryComments) Create(ctx context.Context, comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	create(&comment.Base)
+	r.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *memoryComments) Update(ctx context.Context, comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.comments[comment.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	comment.UpdatedAt = time.Now()
+	stored.Name = comment.Name
+	stored.Body = comment.Body
+	stored.UpdatedAt = comment.UpdatedAt
+	r.comments[comment.ID] = stored
+	return nil
+}
+
+func (r *memoryComments) Delete(ctx context.Context, comment *models.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.comments, comment.ID)
+	return nil
+}
+
+type memoryUsers struct {
+	*memory
+}
+
+func (r *memoryUsers) Get(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUsers) List(ctx context.Context, filter UserFilter, opts ListOptions) ([]models.User, ListResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var users []models.User
+	var keys []pagination.Key
+	for _, user := range r.users {
+		if filter.UserRole != "" && user.UserRole != filter.UserRole {
+			continue
+		}
+		users = append(users, user)
+		keys = append(keys, pagination.Key{CreatedAt: user.CreatedAt, ID: user.ID})
+	}
+
+	indexes, res := paginate(keys, opts)
+	page := make([]models.User, len(indexes))
+	for i, index := range indexes {
+		page[i] = users[index]
+	}
+	return page, res, nil
+}
+
+func (r *memoryUsers) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	create(&user.Base)
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUsers) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	user.UpdatedAt = time.Now()
+	stored.Username = user.Username
+	stored.AvatarURL = user.AvatarURL
+	stored.UpdatedAt = user.UpdatedAt
+	r.users[user.ID] = stored
+	return nil
+}
+
+func (r *memoryUsers) UpdateRole(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	user.UpdatedAt = time.Now()
+	stored.UserRole = user.UserRole
+	stored.UpdatedAt = user.UpdatedAt
+	r.users[user.ID] = stored
+	return nil
+}
+
+type memoryAuthProviders struct {
+	*memory
+}
+
+// find is used to get first provider matching condition, expects lock to be held.
+func (r *memoryAuthProviders) find(match func(models.AuthProvider) bool) (*models.AuthProvider, error) {
+	for _, authProvider := range r.sorted() {
+		if match(authProvider) {
+			return &authProvider, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// sorted is used to get providers in order of creation, expects lock to be held.
+func (r *memoryAuthProviders) sorted() []models.AuthProvider {
+	authProviders := make([]models.AuthProvider, 0, len(r.authProviders))
+	for _, authProvider := range r.authProviders {
+		authProviders = append(authProviders, authProvider)
+	}
+	sort.Slice(authProviders, func(i, j int) bool {
+		return authProviders[i].CreatedAt.Before(authProviders[j].CreatedAt)
+	})
+	return authProviders
+}
+
+func (r *memoryAuthProviders) Find(ctx context.Context, providerType models.AuthProviderType, providerUID string) (*models.AuthProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(authProvider models.AuthProvider) bool {
+		return authProvider.AuthProviderType == providerType && authProvider.ProviderUID == providerUID
+	})
+}
+
+func (r *memoryAuthProviders) FindByUser(ctx context.Context, userID uuid.UUID, providerType models.AuthProviderType) (*models.AuthProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.find(func(authProvider models.AuthProvider) bool {
+		return authProvider.UserID == userID && authProvider.AuthProviderType == providerType
+	})
+}
+
+func (r *memoryAuthProviders) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.AuthProvider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var authProviders []models.AuthProvider
+	for _, authProvider := range r.sorted() {
+		if authProvider.UserID == userID {
+			authProviders = append(authProviders, authProvider)
+		}
+	}
+	return authProviders, nil
+}
+
+func (r *memoryAuthProviders) Create(ctx context.Context, authProvider *models.AuthProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	create(&authProvider.Base)
+	r.authProviders[authProvider.ID] = *authProvider
+	return nil
+}
+
+func (r *memoryAuthProviders) UpdateRefreshToken(ctx context.Context, authProvider *models.AuthProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.authProviders[authProvider.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	authProvider.UpdatedAt = time.Now()
+	stored.RefreshToken = authProvider.RefreshToken
+	stored.UpdatedAt = authProvider.UpdatedAt
+	r.authProviders[authProvider.ID] = stored
+	return nil
+}
+
+func (r *memoryAuthProviders) UpdatePassword(ctx context.Context, authProvider *models.AuthProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.authProviders[authProvider.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	authProvider.UpdatedAt = time.Now()
+	stored.PasswordHash = authProvider.PasswordHash
+	stored.UpdatedAt = authProvider.UpdatedAt
+	r.authProviders[authProvider.ID] = stored
+	return nil
+}
+
+func (r *memoryAuthProviders) Delete(ctx context.Context, authProvider *models.AuthProvider) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.authProviders, authProvider.ID)
+	return nil
+}
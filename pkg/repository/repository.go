@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// ErrNotFound is returned if record does not exist or is soft deleted, also by updates of such records.
+var ErrNotFound = errors.New("record not found")
+
+// ListOptions represent page of listing ordered by created_at and id descending,
+// keyset cursor takes precedence over offset, page is not limited if limit is not positive.
+type ListOptions struct {
+	Cursor    *pagination.Cursor
+	Limit     int
+	Offset    int
+	SkipTotal bool
+}
+
+// ListResult represent count of matching records and whether there are more records in cursor direction.
+type ListResult struct {
+	Total int64
+	More  bool
+}
+
+// CommentFilter represent conditions of comments listing, zero values match any comment.
+type CommentFilter struct {
+	PostID uuid.UUID
+	UserID uuid.UUID
+}
+
+// UserFilter represent conditions of users listing, zero values match any user.
+type UserFilter struct {
+	UserRole models.UserRole
+}
+
+// PostRepository is used to store posts.
+type PostRepository interface {
+	Get(ctx context.Context, id uuid.UUID) (*models.Post, error)
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
+	List(ctx context.Context, opts ListOptions) ([]models.Post, ListResult, error)
+	Create(ctx context.Context, post *models.Post) error
+	// Update is used to save title and body of post.
+	Update(ctx context.Context, post *models.Post) error
+	// Delete is used to delete post with its comments.
+	Delete(ctx context.Context, post *models.Post) error
+}
+
+// CommentRepository is used to store comments.
+type CommentRepository interface {
+	Get(ctx context.Context, id uuid.UUID) (*models.Comment, error)
+	List(ctx context.Context, filter CommentFilter, opts ListOptions) ([]models.Comment, ListResult, error)
+	Count(ctx context.Context, filter CommentFilter) (int64, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	// Update is used to save name and body of comment.
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, comment *models.Comment) error
+}
+
+// UserRepository is used to store users.
+type UserRepository interface {
+	Get(ctx context.Context, id uuid.UUID) (*models.User, error)
+	List(ctx context.Context, filter UserFilter, opts ListOptions) ([]models.User, ListResult, error)
+	Create(ctx context.Context, user *models.User) error
+	// Update is used to save username and avatar url of user.
+	Update(ctx context.Context, user *models.User) error
+	UpdateRole(ctx context.Context, user *models.User) error
+}
+
+// AuthProviderRepository is used to store identities of users with providers.
+type AuthProviderRepository interface {
+	// Find is used to get provider account linked to user.
+	Find(ctx context.Context, providerType models.AuthProviderType, providerUID string) (*models.AuthProvider, error)
+	FindByUser(ctx context.Context, userID uuid.UUID, providerType models.AuthProviderType) (*models.AuthProvider, error)
+	// ListByUser is used to get providers linked to user in order of linking.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.AuthProvider, error)
+	Create(ctx context.Context, authProvider *models.AuthProvider) error
+	UpdateRefreshToken(ctx context.Context, authProvider *models.AuthProvider) error
+	// UpdatePassword is used to save password hash of local provider.
+	UpdatePassword(ctx context.Context, authProvider *models.AuthProvider) error
+	// Delete is used to permanently delete provider so account may be linked again.
+	Delete(ctx context.Context, authProvider *models.AuthProvider) error
+}
+
+// Repositories represent repositories of models backed by same store.
+type Repositories struct {
+	Posts         PostRepository
+	Comments      CommentRepository
+	Users         UserRepository
+	AuthProviders AuthProviderRepository
+}
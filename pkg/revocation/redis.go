@@ -0,0 +1,93 @@
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Redis is revocation list shared by service instances, entries expire in redis
+// once tokens they match would expire on their own.
+type Redis struct {
+	client redis.UniversalClient
+}
+
+// NewRedis is used to create revocation list on top of redis client.
+func NewRedis(client redis.UniversalClient) *Redis {
+	return &Redis{client: client}
+}
+
+// sessionKey is used to get key of session family entry.
+func sessionKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("revocation:session:%s", sessionID)
+}
+
+// userKey is used to get key of user entry.
+func userKey(userID uuid.UUID) string {
+	return fmt.Sprintf("revocation:user:%s", userID)
+}
+
+// RevokeSession is used to reject tokens of session family.
+func (r *Redis) RevokeSession(sessionID uuid.UUID, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+
+	err := r.client.Set(context.Background(), sessionKey(sessionID), 1, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %s", err)
+	}
+	return nil
+}
+
+// RevokeUser is used to reject tokens of user issued before issuedBefore.
+func (r *Redis) RevokeUser(userID uuid.UUID, issuedBefore time.Time, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+
+	err := r.client.Set(context.Background(), userKey(userID), issuedBefore.Unix(), ttl).Err()
+	if err != nil {
+		return fmt.Errorf("failed to revoke user: %s", err)
+	}
+	return nil
+}
+
+// IsRevoked is used to check if token matches any entry.
+func (r *Redis) IsRevoked(token *access.Token) (bool, error) {
+	keys := []string{userKey(token.UserID)}
+	if token.SessionID != uuid.Nil {
+		keys = append(keys, sessionKey(token.SessionID))
+	}
+
+	values, err := r.client.MGet(context.Background(), keys...).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %s", err)
+	}
+
+	// session entry revokes whole family
+	if len(values) > 1 && values[1] != nil {
+		return true, nil
+	}
+
+	if values[0] == nil {
+		return false, nil
+	}
+	s, ok := values[0].(string)
+	if !ok {
+		return false, fmt.Errorf("failed to check revocation: unexpected reply %v", values[0])
+	}
+	issuedBefore, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to check revocation: %s", err)
+	}
+
+	return token.IssuedAt < issuedBefore, nil
+}
@@ -0,0 +1,102 @@
+package revocation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/google/uuid"
+)
+
+// List represent denylist of access tokens which are not expired yet but must not be accepted,
+// entries are kept only until tokens they match would expire on their own.
+type List interface {
+	// RevokeSession is used to reject tokens of session family.
+	RevokeSession(sessionID uuid.UUID, until time.Time) error
+	// RevokeUser is used to reject tokens of user issued before issuedBefore, issue time of token has second precision
+	// so tokens issued within same second as issuedBefore are accepted.
+	RevokeUser(userID uuid.UUID, issuedBefore time.Time, until time.Time) error
+	// IsRevoked is used to check if token matches any entry.
+	IsRevoked(token *access.Token) (bool, error)
+}
+
+// entry represent revocation which is dropped after expiry.
+type entry struct {
+	issuedBefore int64
+	until        time.Time
+}
+
+// Memory is in-process revocation list, useful for tests and single instance deployments.
+type Memory struct {
+	mu sync.RWMutex
+
+	sessions map[uuid.UUID]entry
+	users    map[uuid.UUID]entry
+}
+
+// NewMemory is used to create empty in-process revocation list.
+func NewMemory() *Memory {
+	return &Memory{
+		sessions: map[uuid.UUID]entry{},
+		users:    map[uuid.UUID]entry{},
+	}
+}
+
+// RevokeSession is used to reject tokens of session family.
+func (m *Memory) RevokeSession(sessionID uuid.UUID, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	m.sessions[sessionID] = entry{until: until}
+
+	return nil
+}
+
+// RevokeUser is used to reject tokens of user issued before issuedBefore.
+func (m *Memory) RevokeUser(userID uuid.UUID, issuedBefore time.Time, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep()
+	m.users[userID] = entry{issuedBefore: issuedBefore.Unix(), until: until}
+
+	return nil
+}
+
+// IsRevoked is used to check if token matches any entry.
+func (m *Memory) IsRevoked(token *access.Token) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+
+	if token.SessionID != uuid.Nil {
+		e, ok := m.sessions[token.SessionID]
+		if ok && now.Before(e.until) {
+			return true, nil
+		}
+	}
+
+	e, ok := m.users[token.UserID]
+	if ok && now.Before(e.until) && token.IssuedAt < e.issuedBefore {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// sweep expects write lock to be held.
+func (m *Memory) sweep() {
+	now := time.Now()
+	for id, e := range m.sessions {
+		if !now.Before(e.until) {
+			delete(m.sessions, id)
+		}
+	}
+	for id, e := range m.users {
+		if !now.Before(e.until) {
+			delete(m.users, id)
+		}
+	}
+}
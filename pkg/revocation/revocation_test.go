@@ -0,0 +1,174 @@
+package revocation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRevokeUser(t *testing.T) {
+	userID := uuid.New()
+	// revocation in the middle of second
+	revokedAt := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{name: "token issued in earlier second", userID: userID, issuedAt: revokedAt.Add(-time.Second), revoked: true},
+		{name: "token issued later in same second", userID: userID, issuedAt: revokedAt.Add(100 * time.Millisecond), revoked: false},
+		{name: "token issued in later second", userID: userID, issuedAt: revokedAt.Add(time.Second), revoked: false},
+		{name: "token of other user", userID: uuid.New(), issuedAt: revokedAt.Add(-time.Second), revoked: false},
+	}
+
+	m := NewMemory()
+	err := m.RevokeUser(userID, revokedAt, time.Now().Add(time.Hour))
+	require.NoError(t, err, "failed to revoke user")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := m.IsRevoked(&access.Token{
+				UserID:         tt.userID,
+				StandardClaims: jwt.StandardClaims{IssuedAt: tt.issuedAt.Unix()},
+			})
+			require.NoError(t, err, "failed to check revocation")
+			require.Equal(t, tt.revoked, revoked, "unexpected revocation")
+		})
+	}
+}
+
+func TestMemoryRevokeSession(t *testing.T) {
+	familyID := uuid.New()
+
+	tests := []struct {
+		name      string
+		sessionID uuid.UUID
+		until     time.Time
+		revoked   bool
+	}{
+		{name: "token of revoked family", sessionID: familyID, until: time.Now().Add(time.Hour), revoked: true},
+		{name: "token of other family", sessionID: uuid.New(), until: time.Now().Add(time.Hour), revoked: false},
+		{name: "token of expired entry", sessionID: familyID, until: time.Now().Add(-time.Second), revoked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMemory()
+			err := m.RevokeSession(familyID, tt.until)
+			require.NoError(t, err, "failed to revoke session")
+
+			revoked, err := m.IsRevoked(&access.Token{
+				UserID:         uuid.New(),
+				SessionID:      tt.sessionID,
+				StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Unix()},
+			})
+			require.NoError(t, err, "failed to check revocation")
+			require.Equal(t, tt.revoked, revoked, "unexpected revocation")
+		})
+	}
+}
+
+// newRedis is used to create redis revocation list on top of fresh server.
+func newRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	server, err := miniredis.Run()
+	require.NoError(t, err, "failed to start redis")
+	t.Cleanup(server.Close)
+	return NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()})), server
+}
+
+func TestRedisRevokeUser(t *testing.T) {
+	userID := uuid.New()
+	// revocation in the middle of second
+	revokedAt := time.Now().Truncate(time.Second).Add(500 * time.Millisecond)
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		issuedAt time.Time
+		revoked  bool
+	}{
+		{name: "token issued in earlier second", userID: userID, issuedAt: revokedAt.Add(-time.Second), revoked: true},
+		{name: "token issued later in same second", userID: userID, issuedAt: revokedAt.Add(100 * time.Millisecond), revoked: false},
+		{name: "token issued in later second", userID: userID, issuedAt: revokedAt.Add(time.Second), revoked: false},
+		{name: "token of other user", userID: uuid.New(), issuedAt: revokedAt.Add(-time.Second), revoked: false},
+	}
+
+	r, _ := newRedis(t)
+	err := r.RevokeUser(userID, revokedAt, time.Now().Add(time.Hour))
+	require.NoError(t, err, "failed to revoke user")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := r.IsRevoked(&access.Token{
+				UserID:         tt.userID,
+				StandardClaims: jwt.StandardClaims{IssuedAt: tt.issuedAt.Unix()},
+			})
+			require.NoError(t, err, "failed to check revocation")
+			require.Equal(t, tt.revoked, revoked, "unexpected revocation")
+		})
+	}
+}
+
+func TestRedisRevokeSession(t *testing.T) {
+	familyID := uuid.New()
+
+	tests := []struct {
+		name      string
+		sessionID uuid.UUID
+		until     time.Time
+		revoked   bool
+	}{
+		{name: "token of revoked family", sessionID: familyID, until: time.Now().Add(time.Hour), revoked: true},
+		{name: "token of other family", sessionID: uuid.New(), until: time.Now().Add(time.Hour), revoked: false},
+		{name: "token of expired entry", sessionID: familyID, until: time.Now().Add(-time.Second), revoked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newRedis(t)
+			err := r.RevokeSession(familyID, tt.until)
+			require.NoError(t, err, "failed to revoke session")
+
+			revoked, err := r.IsRevoked(&access.Token{
+				UserID:         uuid.New(),
+				SessionID:      tt.sessionID,
+				StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Unix()},
+			})
+			require.NoError(t, err, "failed to check revocation")
+			require.Equal(t, tt.revoked, revoked, "unexpected revocation")
+		})
+	}
+}
+
+func TestRedisShared(t *testing.T) {
+	r, server := newRedis(t)
+	// other instance of service connected to same server
+	other := NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	familyID := uuid.New()
+	err := r.RevokeSession(familyID, time.Now().Add(time.Minute))
+	require.NoError(t, err, "failed to revoke session")
+
+	token := &access.Token{
+		UserID:         uuid.New(),
+		SessionID:      familyID,
+		StandardClaims: jwt.StandardClaims{IssuedAt: time.Now().Unix()},
+	}
+	revoked, err := other.IsRevoked(token)
+	require.NoError(t, err, "failed to check revocation")
+	require.True(t, revoked, "revocation is not shared")
+
+	// entry is dropped once tokens would expire on their own
+	server.FastForward(time.Minute)
+	revoked, err = other.IsRevoked(token)
+	require.NoError(t, err, "failed to check revocation")
+	require.False(t, revoked, "expired entry is kept")
+}
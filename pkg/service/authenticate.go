@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/golang-jwt/jwt"
+)
+
+// Authenticator is used to resolve bearer credential of request to token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (*access.Token, error)
+}
+
+// lastUsedInterval limits how often usage of personal access token is recorded.
+const lastUsedInterval = time.Minute
+
+// Authenticate is used to verify JWT with token keys or to look up personal access token in database,
+// personal access tokens carry current role of user and scopes granted on creation.
+func (s *Service) Authenticate(ctx context.Context, credential string) (*access.Token, error) {
+	if !access.IsPersonalAccessToken(credential) {
+		return s.Tokens.Decode(credential)
+	}
+	if s.MySQL == nil {
+		return nil, errors.New("personal access tokens are not supported")
+	}
+
+	var pat models.PersonalAccessToken
+	err := s.MySQL.WithContext(ctx).
+		Model(&models.PersonalAccessToken{}).
+		Where(&models.PersonalAccessToken{TokenHash: access.HashPersonalAccessToken(credential)}).
+		First(&pat).
+		Error
+	if err != nil {
+		return nil, errors.New("invalid personal access token")
+	}
+
+	now := time.Now()
+	if pat.RevokedAt != nil {
+		return nil, errors.New("personal access token is revoked")
+	}
+	if !pat.ExpiresAt.After(now) {
+		return nil, errors.New("personal access token is expired")
+	}
+
+	var user models.User
+	err = s.MySQL.WithContext(ctx).
+		Model(&models.User{}).
+		Where(&models.User{Base: models.Base{ID: pat.UserID}}).
+		First(&user).
+		Error
+	if err != nil {
+		return nil, errors.New("owner of personal access token not found")
+	}
+
+	// record usage, failures do not block request
+	if pat.LastUsedAt == nil || now.Sub(*pat.LastUsedAt) > lastUsedInterval {
+		err = s.MySQL.WithContext(ctx).Model(&pat).UpdateColumn("last_used_at", now).Error
+		if err != nil {
+			s.Logger.Warnw("failed to record usage of personal access token", "err", err)
+		}
+	}
+
+	scopes := pat.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &access.Token{
+		UserID:   user.ID,
+		UserRole: user.UserRole,
+		Scopes:   scopes,
+		StandardClaims: jwt.StandardClaims{
+			Id:        pat.ID.String(),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: pat.ExpiresAt.Unix(),
+		},
+	}, nil
+}
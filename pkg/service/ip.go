@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewIPExtractor is used to create extractor of client ip, X-Forwarded-For header is only read when request
+// comes through trusted proxy from config, otherwise peer address is used so clients can not spoof their ip.
+func (s *Service) NewIPExtractor() (echo.IPExtractor, error) {
+	if s.Config.TrustedProxies == "" {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	// trust only configured ranges
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range strings.Split(s.Config.TrustedProxies, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %s", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
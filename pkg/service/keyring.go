@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/Tamplier2911/gorest/pkg/keyring"
+)
+
+// developmentKeyID is id of key derived from hmac secret when no keys are configured.
+const developmentKeyID = "dev"
+
+// NewKeyRing is used to create key ring of stored secrets from config,
+// outside of production key is derived from hmac secret if keys are not provided.
+func (s *Service) NewKeyRing() (*keyring.KeyRing, error) {
+	if s.Config.EncryptionKeys == "" {
+		if s.Config.Production {
+			return nil, errors.New("encryption keys are required in production")
+		}
+		s.Logger.Warnw("encryption keys are not configured, deriving development key from hmac secret")
+		return keyring.Derive(developmentKeyID, s.Config.HMACSecret)
+	}
+
+	return keyring.Parse(s.Config.EncryptionPrimaryKeyID, s.Config.EncryptionKeys)
+}
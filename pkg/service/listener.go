@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Tamplier2911/gorest/pkg/lifecycle"
+)
+
+// newServer is used to create http server with default timeouts.
+func newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:           addr,
+		Handler:        handler,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+}
+
+// tlsEnabled reports if both certificate and key files are configured.
+func (s *Service) tlsEnabled() bool {
+	return s.Config.TLSCertFile != "" && s.Config.TLSKeyFile != ""
+}
+
+// listenerHook is used to create lifecycle hook which binds server to address on start,
+// serves it in background, with TLS if requested, and shuts it down on stop.
+func (s *Service) listenerHook(name string, network string, server *http.Server, tls bool) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			// remove stale socket left by previous run
+			if network == "unix" {
+				err := os.Remove(server.Addr)
+				if err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove stale unix socket: %s", err)
+				}
+			}
+
+			// bind listener synchronously so address errors abort startup
+			listener, err := net.Listen(network, server.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %s", server.Addr, err)
+			}
+
+			s.Logger.Infow(fmt.Sprintf("starting %s - base url: %s address: %s", name, s.Config.BaseURL, listener.Addr()), "tls", tls)
+
+			go func() {
+				var err error
+				if tls {
+					err = server.ServeTLS(listener, s.Config.TLSCertFile, s.Config.TLSKeyFile)
+				} else {
+					err = server.Serve(listener)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					s.Lifecycle.Fail(fmt.Errorf("%s error: %s", name, err))
+				}
+			}()
+
+			return nil
+		},
+		OnStop: server.Shutdown,
+	}
+}
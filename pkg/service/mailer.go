@@ -0,0 +1,27 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/mailer"
+)
+
+// NewMailer is used to create mailer selected in config.
+func (s *Service) NewMailer() (mailer.Mailer, error) {
+	switch s.Config.MailerBackend {
+	case "smtp":
+		return mailer.NewSMTP(mailer.SMTPOptions{
+			Addr:     s.Config.SMTPAddr,
+			Username: s.Config.SMTPUsername,
+			Password: s.Config.SMTPPassword,
+			From:     s.Config.MailFrom,
+		})
+	case "memory", "":
+		if s.Config.Production {
+			s.Logger.Warnw("mailer is not configured, outgoing email is kept in memory")
+		}
+		return mailer.NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer backend %q", s.Config.MailerBackend)
+	}
+}
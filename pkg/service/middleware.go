@@ -2,18 +2,24 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Tamplier2911/gorest/pkg/access"
-	"github.com/Tamplier2911/gorest/pkg/config"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/policy"
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/ratelimit"
+	"github.com/Tamplier2911/gorest/pkg/revocation"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
-// AuthenticationMiddleware is used to authenticate user.
-func AuthenticationMiddleware(logger *zap.SugaredLogger, config *config.Config, next echo.HandlerFunc) echo.HandlerFunc {
+// AuthenticationMiddleware is used to authenticate user with JWT or personal access token, revoked tokens are rejected.
+func AuthenticationMiddleware(logger *zap.SugaredLogger, authenticator Authenticator, revocations revocation.List, next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		logger := logger.Named("AuthenticationMiddleware")
 
@@ -22,26 +28,38 @@ func AuthenticationMiddleware(logger *zap.SugaredLogger, config *config.Config,
 		logger.Infow("checking authorization header")
 		if len(authHeaderArr) == 0 {
 			logger.Errorw("empty authorization header", "authHeaderArr", authHeaderArr)
-			return echo.NewHTTPError(http.StatusUnauthorized, "empty authorization token")
+			return problem.Unauthorized("empty authorization token")
 		}
 
 		// retrieve token from header
 		logger.Infow("retrieving token from header")
 		tokenArr := strings.Split(authHeaderArr[0], " ")
 		if len(tokenArr) != 2 {
-			logger.Errorw("malformed auth token", "tokenArr", tokenArr)
-			return echo.NewHTTPError(http.StatusUnauthorized, "malformed auth token")
+			logger.Errorw("malformed auth token", "parts", len(tokenArr))
+			return problem.Unauthorized("malformed auth token")
 		}
 
 		// get token from header value
 		token := tokenArr[1]
 
-		// decode token
-		logger.Infow("decoding token", "token", token)
-		decodedToken, err := access.DecodeToken(token, config.HMACSecret)
+		// decode token, credential is secret so only its kind is logged
+		logger.Infow("decoding token", "personalAccessToken", access.IsPersonalAccessToken(token))
+		decodedToken, err := authenticator.Authenticate(c.Request().Context(), token)
 		if err != nil {
 			logger.Errorw("failed to decode token", "err", err)
-			return echo.NewHTTPError(http.StatusUnauthorized, "malformed auth token")
+			return problem.Unauthorized("malformed auth token")
+		}
+
+		// check revocation list
+		logger.Infow("checking revocation list")
+		revoked, err := revocations.IsRevoked(decodedToken)
+		if err != nil {
+			logger.Errorw("failed to check revocation list", "err", err)
+			return problem.Internal("failed to authenticate request")
+		}
+		if revoked {
+			logger.Errorw("token is revoked", "decodedToken", decodedToken)
+			return problem.Unauthorized("auth token is revoked")
 		}
 
 		// save token to context
@@ -54,11 +72,116 @@ func AuthenticationMiddleware(logger *zap.SugaredLogger, config *config.Config,
 	}
 }
 
+// AuthorizationMiddleware is used to authorize user by role, expects token saved by AuthenticationMiddleware.
+func AuthorizationMiddleware(logger *zap.SugaredLogger, roles []models.UserRole, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		logger := logger.Named("AuthorizationMiddleware")
+
+		// get token from context
+		token, ok := c.Get("token").(*access.Token)
+		if !ok {
+			logger.Errorw("token is missing in context")
+			return problem.Unauthorized("empty authorization token")
+		}
+		logger = logger.With("token", token)
+
+		// check user role
+		logger.Infow("checking user role", "roles", roles)
+		if !policy.HasRole(token, roles...) {
+			logger.Errorw("user role is not allowed", "roles", roles)
+			return problem.Forbidden("insufficient permissions")
+		}
+
+		// success, pass context to next middleware
+		logger.Infow("successfully authorized request")
+		return next(c)
+	}
+}
+
+// ScopeMiddleware is used to require scopes of personal access tokens, expects token saved by AuthenticationMiddleware.
+func ScopeMiddleware(logger *zap.SugaredLogger, scopes []string, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		logger := logger.Named("ScopeMiddleware")
+
+		// get token from context
+		token, ok := c.Get("token").(*access.Token)
+		if !ok {
+			logger.Errorw("token is missing in context")
+			return problem.Unauthorized("empty authorization token")
+		}
+		logger = logger.With("token", token)
+
+		// check token scopes
+		logger.Infow("checking token scopes", "scopes", scopes)
+		if !policy.HasScopes(token, scopes...) {
+			logger.Errorw("token is missing scopes", "scopes", scopes)
+			return problem.Forbidden("insufficient token scopes")
+		}
+
+		// success, pass context to next middleware
+		logger.Infow("successfully checked token scopes")
+		return next(c)
+	}
+}
+
+// RateLimitMiddleware is used to limit requests of route group by policy with name, requests are counted by user of token
+// saved by AuthenticationMiddleware or by client ip, nil limiter allows every request.
+func RateLimitMiddleware(logger *zap.SugaredLogger, limiter *ratelimit.Limiter, name string, next echo.HandlerFunc) echo.HandlerFunc {
+	if limiter == nil {
+		return next
+	}
+
+	return func(c echo.Context) error {
+		logger := logger.Named("RateLimitMiddleware").With("policy", name)
+
+		// get policy of route group
+		p, ok := limiter.Policy(name)
+		if !ok {
+			return next(c)
+		}
+
+		// count authenticated requests by user
+		subject := fmt.Sprintf("ip:%s", c.RealIP())
+		if token, ok := c.Get("token").(*access.Token); ok && p.Key == ratelimit.KeyUser {
+			subject = fmt.Sprintf("user:%s", token.UserID)
+		}
+		logger = logger.With("subject", subject)
+
+		// take token from bucket, requests are allowed if store is unavailable
+		res, err := limiter.Allow(c.Request().Context(), p, subject)
+		if err != nil {
+			logger.Errorw("failed to check rate limit", "err", err)
+			return next(c)
+		}
+
+		// describe limit with standard headers
+		header := c.Response().Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit.Burst, ceilSeconds(p.Period)))
+
+		if !res.Allowed {
+			logger.Errorw("rate limit exceeded", "retryAfter", res.RetryAfter)
+			header.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			return problem.TooManyRequests("rate limit exceeded")
+		}
+
+		return next(c)
+	}
+}
+
+// ceilSeconds is used to round duration up to whole seconds, at least one second is returned for positive durations.
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
 // AuthWrapperDP is used to authenticate user DEPRECATED.
 func AuthWrapperDP(
 	handler func(w http.ResponseWriter, r *http.Request),
 	lg *zap.SugaredLogger,
-	cf *config.Config,
+	ks *access.KeySet,
+	rv revocation.List,
 	w http.ResponseWriter,
 	r *http.Request,
 ) {
@@ -69,8 +192,7 @@ func AuthWrapperDP(
 	logger.Infow("checking authorization header")
 	if len(authHeaderStr) == 0 {
 		logger.Errorw("empty authorization header", "authHeaderArr", authHeaderStr)
-		err := errors.New("empty authorization token")
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		problem.Write(w, r, problem.Unauthorized("empty authorization token"))
 		return
 	}
 
@@ -78,21 +200,34 @@ func AuthWrapperDP(
 	logger.Infow("retrieving token from header")
 	tokenArr := strings.Split(authHeaderStr, " ")
 	if len(tokenArr) != 2 {
-		logger.Errorw("malformed auth token", "tokenArr", tokenArr)
-		err := errors.New("malformed auth token")
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		logger.Errorw("malformed auth token", "parts", len(tokenArr))
+		problem.Write(w, r, problem.Unauthorized("malformed auth token"))
 		return
 	}
 
 	// get token from header value
 	token := tokenArr[1]
 
-	// decode token
-	logger.Infow("decoding token", "token", token)
-	decodedToken, err := access.DecodeToken(token, cf.HMACSecret)
+	// decode token, credential is secret so it is not logged
+	logger.Infow("decoding token")
+	decodedToken, err := ks.Decode(token)
 	if err != nil {
 		logger.Errorw("failed to decode token", "err", err)
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		problem.Write(w, r, problem.Unauthorized("malformed auth token"))
+		return
+	}
+
+	// check revocation list
+	logger.Infow("checking revocation list")
+	revoked, err := rv.IsRevoked(decodedToken)
+	if err != nil {
+		logger.Errorw("failed to check revocation list", "err", err)
+		problem.Write(w, r, problem.Internal("failed to authenticate request"))
+		return
+	}
+	if revoked {
+		logger.Errorw("token is revoked", "decodedToken", decodedToken)
+		problem.Write(w, r, problem.Unauthorized("auth token is revoked"))
 		return
 	}
 
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/revocation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// authenticatorFunc is used to stub Authenticator.
+type authenticatorFunc func(ctx context.Context, credential string) (*access.Token, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, credential string) (*access.Token, error) {
+	return f(ctx, credential)
+}
+
+func TestAuthenticationMiddlewareDoesNotLogCredential(t *testing.T) {
+	tests := []struct {
+		name       string
+		credential string
+		err        error
+	}{
+		{name: "personal access token", credential: access.PersonalAccessTokenPrefix + "secret-pat-value"},
+		{name: "jwt", credential: "header.secret-jwt-claims.signature"},
+		{name: "invalid credential", credential: "secret-invalid-value", err: errors.New("invalid token")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			authenticator := authenticatorFunc(func(ctx context.Context, credential string) (*access.Token, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &access.Token{UserID: uuid.New()}, nil
+			})
+			next := func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}
+			handler := AuthenticationMiddleware(zap.New(core).Sugar(), authenticator, revocation.NewMemory(), next)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.credential)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			_ = handler(c)
+
+			require.NotZero(t, logs.Len(), "nothing was logged")
+			for _, entry := range logs.All() {
+				for key, value := range entry.ContextMap() {
+					require.NotContains(t, fmt.Sprint(value), tt.credential, "credential was logged in %q of %q", key, entry.Message)
+				}
+			}
+		})
+	}
+}
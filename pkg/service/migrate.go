@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/migrate"
+	"github.com/Tamplier2911/gorest/pkg/migrations"
+	"github.com/Tamplier2911/gorest/pkg/models"
+)
+
+// NewMigrator is used to create migrator applying versioned migrations to MySQL connection.
+func (s *Service) NewMigrator() (*migrate.Migrator, error) {
+	all, err := migrations.All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %s", err)
+	}
+	return migrate.New(s.MySQL, s.Logger.Named("Migrator"), all, s.Config.MigrationsLockTimeout)
+}
+
+// AutoMigrate is used to create missing tables, columns and indexes of models in database,
+// unlike migrations it can not rename or drop columns.
+func (s *Service) AutoMigrate() error {
+	// drop index of provider tokens, encrypted values are too long to be indexed
+	if s.MySQL.Migrator().HasIndex(&models.AuthProvider{}, "idx_auth_providers_refresh_token") {
+		s.Logger.Info("dropping index of auth provider tokens")
+		err := s.MySQL.Migrator().DropIndex(&models.AuthProvider{}, "idx_auth_providers_refresh_token")
+		if err != nil {
+			return fmt.Errorf("failed to drop index of auth provider tokens: %s", err)
+		}
+	}
+
+	err := s.MySQL.AutoMigrate(models.All()...)
+	if err != nil {
+		return fmt.Errorf("failed to automigrate models: %s", err)
+	}
+	return nil
+}
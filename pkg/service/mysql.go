@@ -32,3 +32,16 @@ func (s *Service) NewMySQL() (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// InstrumentDatabase is used to create child spans for queries of database and export query durations and pool stats.
+func (s *Service) InstrumentDatabase(db *gorm.DB, system string) error {
+	err := s.Tracing.InstrumentGorm(db, system)
+	if err != nil {
+		return err
+	}
+
+	if s.Metrics != nil {
+		return s.Metrics.InstrumentGorm(db, system)
+	}
+	return nil
+}
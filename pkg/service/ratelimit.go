@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Tamplier2911/gorest/pkg/ratelimit"
+)
+
+// NewRateLimiter is used to create rate limiter with store and policies selected in config,
+// redis store is shared by every instance of service.
+func (s *Service) NewRateLimiter() (*ratelimit.Limiter, error) {
+	// parse policies in stable order so errors are reproducible
+	names := make([]string, 0, len(s.Config.RateLimitPolicies))
+	for name := range s.Config.RateLimitPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	policies := make([]ratelimit.Policy, 0, len(names))
+	for _, name := range names {
+		p := s.Config.RateLimitPolicies[name]
+		policy, err := ratelimit.NewPolicy(name, p.Requests, p.Period, p.Burst, ratelimit.KeyType(p.Key))
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	var store ratelimit.Store
+	switch s.Config.RateLimitStore {
+	case "redis":
+		store = ratelimit.NewRedis(s.Redis())
+	case "memory", "":
+		store = ratelimit.NewMemory()
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", s.Config.RateLimitStore)
+	}
+
+	return ratelimit.NewLimiter(store, policies...), nil
+}
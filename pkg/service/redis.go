@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Tamplier2911/gorest/pkg/lifecycle"
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis is used to get client of redis server shared by stores selected in config,
+// client is created on first use, reports readiness of server and is closed on shutdown.
+func (s *Service) Redis() redis.UniversalClient {
+	if s.redis != nil {
+		return s.redis
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     s.Config.RedisAddr,
+		Password: s.Config.RedisPassword,
+		DB:       s.Config.RedisDB,
+	})
+	s.Health.Register("redis", func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+	s.Lifecycle.Append(lifecycle.Hook{
+		Name: "redis",
+		OnStop: func(ctx context.Context) error {
+			return client.Close()
+		},
+	})
+
+	s.redis = client
+	return client
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/repository"
+)
+
+// NewRepositories is used to create repositories backed by store selected in config,
+// expects MySQL connection unless memory store is selected.
+func (s *Service) NewRepositories() (repository.Repositories, error) {
+	switch s.Config.RepositoryBackend {
+	case "memory":
+		return repository.NewMemory(), nil
+	case "mysql", "":
+		if s.MySQL == nil {
+			return repository.Repositories{}, errors.New("mysql connection is required by mysql repositories")
+		}
+		return repository.NewGorm(s.MySQL), nil
+	default:
+		return repository.Repositories{}, fmt.Errorf("unknown repository backend %q", s.Config.RepositoryBackend)
+	}
+}
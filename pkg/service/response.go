@@ -0,0 +1,49 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// ResponseWriter is used to write response of echo handlers in format negotiated with accept header,
+// e.g. json, xml, yaml, msgpack or csv for list responses.
+func (s *Service) ResponseWriter(c echo.Context, statusCode int, res interface{}) error {
+	return s.Negotiator.Write(c.Response(), c.Request(), statusCode, res)
+}
+
+// HTTPResponseWriter is used to write response of default router handlers in format negotiated with accept header,
+// errors of negotiation are written as problem details.
+func (s *Service) HTTPResponseWriter(w http.ResponseWriter, r *http.Request, statusCode int, res interface{}) error {
+	err := s.Negotiator.Write(w, r, statusCode, res)
+	if err != nil {
+		s.HTTPErrorWriter(w, r, err)
+	}
+	return err
+}
+
+// HTTPErrorHandler is used by echo to write errors returned by handlers and middlewares as problem details.
+func (s *Service) HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	s.HTTPErrorWriter(c.Response(), c.Request(), err)
+}
+
+// HTTPErrorWriter is used to write error of default router handlers as problem details, causes of
+// internal errors are logged and never written.
+func (s *Service) HTTPErrorWriter(w http.ResponseWriter, r *http.Request, err error) {
+	logger := tracing.Logger(r.Context(), s.Logger.Named("HTTPErrorWriter"))
+
+	e := problem.From(err)
+	if e.Status >= http.StatusInternalServerError {
+		logger.Errorw("request failed", "err", err)
+	}
+
+	err = problem.Write(w, r, e)
+	if err != nil {
+		logger.Errorw("failed to write problem details", "err", err)
+	}
+}
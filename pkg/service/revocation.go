@@ -0,0 +1,20 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/revocation"
+)
+
+// NewRevocationList is used to create revocation list with store selected in config,
+// redis store is shared by every instance of service while memory store fits single instance only.
+func (s *Service) NewRevocationList() (revocation.List, error) {
+	switch s.Config.RevocationStore {
+	case "redis":
+		return revocation.NewRedis(s.Redis()), nil
+	case "memory", "":
+		return revocation.NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown revocation store %q", s.Config.RevocationStore)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Tamplier2911/gorest/pkg/fulltext"
+)
+
+// NewSearch is used to create search backend selected in config,
+// expects MySQL connection with migrated posts and comments tables and their FULLTEXT indexes.
+func (s *Service) NewSearch() (fulltext.Backend, error) {
+	var backend fulltext.Backend
+
+	switch s.Config.SearchBackend {
+	case "memory":
+		memory := fulltext.NewMemory()
+		err := memory.Load(s.MySQL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load in-memory index: %s", err)
+		}
+		backend = memory
+	case "mysql", "":
+		backend = fulltext.NewMySQL(s.MySQL)
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", s.Config.SearchBackend)
+	}
+
+	// keep index in sync with database writes
+	err := fulltext.Register(s.MySQL, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register search callbacks: %s", err)
+	}
+
+	return backend, nil
+}
@@ -1,17 +1,32 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"sync"
-	"time"
+	"os/signal"
+	"syscall"
 
+	"github.com/Tamplier2911/gorest/pkg/access"
 	"github.com/Tamplier2911/gorest/pkg/config"
+	"github.com/Tamplier2911/gorest/pkg/fulltext"
+	"github.com/Tamplier2911/gorest/pkg/health"
+	"github.com/Tamplier2911/gorest/pkg/keyring"
+	"github.com/Tamplier2911/gorest/pkg/lifecycle"
 	"github.com/Tamplier2911/gorest/pkg/logger"
+	"github.com/Tamplier2911/gorest/pkg/mailer"
+	"github.com/Tamplier2911/gorest/pkg/metrics"
+	"github.com/Tamplier2911/gorest/pkg/negotiate"
+	"github.com/Tamplier2911/gorest/pkg/ratelimit"
+	"github.com/Tamplier2911/gorest/pkg/repository"
+	"github.com/Tamplier2911/gorest/pkg/revocation"
+	"github.com/Tamplier2911/gorest/pkg/tracing"
 	"github.com/labstack/echo/v4"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -24,10 +39,46 @@ type Service struct {
 	Server *http.Server
 	Router *http.ServeMux
 
+	// ordered startup and graceful shutdown
+	Lifecycle *lifecycle.Lifecycle
+
+	// readiness checks of subsystems
+	Health *health.Registry
+
+	// optional prometheus metrics
+	Metrics *metrics.Metrics
+
+	// keys of secrets encrypted at rest
+	KeyRing *keyring.KeyRing
+
+	// keys of access tokens and revoked access tokens rejected by authentication middleware
+	Tokens      *access.KeySet
+	Revocations revocation.List
+
+	// delivery of outgoing email
+	Mailer mailer.Mailer
+
+	// encoding of responses and request bodies by accept and content type headers
+	Negotiator *negotiate.Negotiator
+
+	// optional rate limiter of route groups
+	RateLimiter *ratelimit.Limiter
+
+	// tracing and traced client for outbound requests
+	Tracing    *tracing.Tracing
+	HTTPClient *http.Client
+
+	// repositories of models
+	repository.Repositories
+
 	// optional
 	MySQL     *gorm.DB
 	Echo      *echo.Echo
 	Validator *validator.Validate
+	Search    fulltext.Backend
+
+	// client of redis server shared by stores, created on first use
+	redis redis.UniversalClient
 }
 
 type InitializeOptions struct {
@@ -47,23 +98,108 @@ func (s *Service) Initialize(options *InitializeOptions) {
 		New(s.Config.LogLevel, s.Config.Production).
 		Named("Service")
 
-	// get port
+	// create lifecycle manager
+	s.Lifecycle = lifecycle.New(s.Logger.Named("Lifecycle"), lifecycle.Options{
+		ShutdownTimeout: s.Config.ShutdownTimeout,
+		DrainDelay:      s.Config.ShutdownDrainDelay,
+	})
+
+	// create tracing, spans are flushed after everything else is stopped
+	s.Tracing, err = tracing.New(tracing.Options{
+		ServiceName:  "gorest",
+		Exporter:     tracing.Exporter(s.Config.TracingExporter),
+		OTLPEndpoint: s.Config.TracingOTLPEndpoint,
+		OTLPInsecure: s.Config.TracingOTLPInsecure,
+		SampleRatio:  s.Config.TracingSampleRatio,
+	})
+	if err != nil {
+		s.Logger.Fatalw("failed to create tracing", "err", err)
+	}
+	s.Lifecycle.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: s.Tracing.Shutdown,
+	})
+	s.HTTPClient = s.Tracing.Client()
+
+	// create token keys
+	s.Tokens, err = s.NewTokenKeys()
+	if err != nil {
+		s.Logger.Fatalw("failed to create token keys", "err", err)
+	}
+
+	// create key ring, serializer must be registered before models are parsed
+	s.KeyRing, err = s.NewKeyRing()
+	if err != nil {
+		s.Logger.Fatalw("failed to create key ring", "err", err)
+	}
+	keyring.Register(s.KeyRing)
+
+	// create mailer
+	s.Mailer, err = s.NewMailer()
+	if err != nil {
+		s.Logger.Fatalw("failed to create mailer", "err", err)
+	}
+
+	// create content negotiator
+	s.Negotiator = negotiate.Default()
+
+	// create health registry, service is ready only while lifecycle is running
+	s.Health = health.NewRegistry(s.Logger, s.Config.HealthCheckTimeout)
+	s.Health.Register("lifecycle", func(ctx context.Context) error {
+		if !s.Lifecycle.Ready() {
+			return errors.New("service is not running")
+		}
+		return nil
+	})
+
+	// create revocation list
+	s.Logger.Infow("wiring revocation list", "store", s.Config.RevocationStore)
+	s.Revocations, err = s.NewRevocationList()
+	if err != nil {
+		s.Logger.Fatalw("failed to create revocation list", "err", err)
+	}
+
+	// create rate limiter
+	if s.Config.RateLimitEnabled {
+		s.Logger.Infow("wiring rate limiter", "store", s.Config.RateLimitStore)
+		s.RateLimiter, err = s.NewRateLimiter()
+		if err != nil {
+			s.Logger.Fatalw("failed to create rate limiter", "err", err)
+		}
+	}
+
+	// get ports, platform provided port is used by primary listener
 	port := fmt.Sprintf(":%s", s.Config.Port)
+	echoPort := fmt.Sprintf(":%s", s.Config.EchoPort)
 	if s.Config.Production {
-		port = fmt.Sprintf(":%s", os.Getenv("PORT"))
+		if s.Config.SingleListener {
+			echoPort = fmt.Sprintf(":%s", os.Getenv("PORT"))
+		} else {
+			port = fmt.Sprintf(":%s", os.Getenv("PORT"))
+		}
 	}
 
 	// create default router
 	s.Router = http.NewServeMux()
 
-	// create default server
-	s.Server = &http.Server{
-		Addr:           port,
-		Handler:        s.Router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	// create metrics
+	if s.Config.MetricsEnabled {
+		s.Logger.Infow("wiring prometheus metrics")
+		s.Metrics = metrics.New()
+	}
+
+	// create default server, spans wrap metrics so both see the same latency
+	var handler http.Handler = s.Router
+	if s.Metrics != nil {
+		handler = s.Metrics.WrapMux("default", s.Router)
 	}
+	s.Server = newServer(port, s.Tracing.WrapMux(s.Router, handler))
+
+	// health endpoints of default server
+	s.Router.HandleFunc("/healthz", health.LivenessHandler)
+	s.Router.HandleFunc("/readyz", s.Health.ReadinessHandler)
+	s.Router.HandleFunc("/version", health.VersionHandler)
+	s.Router.HandleFunc("/.well-known/jwks.json", s.Tokens.JWKSHandler)
 
 	// create mysql connection with gorm package
 	if options.MySQL {
@@ -73,70 +209,109 @@ func (s *Service) Initialize(options *InitializeOptions) {
 			s.Logger.Fatalw("failed to connect to mysql server", "config", s.Config, "err", err)
 		}
 		s.Logger.Infow("successfully connected to mysql server")
+
+		// create child spans for queries and export their durations
+		err = s.InstrumentDatabase(s.MySQL, "mysql")
+		if err != nil {
+			s.Logger.Fatalw("failed to instrument mysql connection", "err", err)
+		}
+
+		// report readiness of mysql server
+		s.Health.Register("mysql", func(ctx context.Context) error {
+			db, err := s.MySQL.DB()
+			if err != nil {
+				return err
+			}
+			return db.PingContext(ctx)
+		})
+
+		// close connection pool after servers are drained
+		s.Lifecycle.Append(lifecycle.Hook{
+			Name: "mysql",
+			OnStop: func(ctx context.Context) error {
+				db, err := s.MySQL.DB()
+				if err != nil {
+					return err
+				}
+				return db.Close()
+			},
+		})
 	}
 
 	// create echo instance
 	if options.Echo {
 		s.Logger.Infow("wiring echo framework server")
 		s.Echo = echo.New()
+		s.Echo.HideBanner = true
+		s.Echo.Server = newServer(echoPort, s.Echo)
+		s.Echo.Binder = s.Negotiator.Binder()
+		s.Echo.HTTPErrorHandler = s.HTTPErrorHandler
+		s.Echo.IPExtractor, err = s.NewIPExtractor()
+		if err != nil {
+			s.Logger.Fatalw("failed to create ip extractor", "err", err)
+		}
+		s.Echo.Use(s.Tracing.EchoMiddleware("gorest"))
+		if s.Metrics != nil {
+			s.Echo.Use(s.Metrics.EchoMiddleware("echo"))
+		}
+
+		// health endpoints of echo server
+		s.Echo.GET("/healthz", echo.WrapHandler(http.HandlerFunc(health.LivenessHandler)))
+		s.Echo.GET("/readyz", echo.WrapHandler(http.HandlerFunc(s.Health.ReadinessHandler)))
+		s.Echo.GET("/version", echo.WrapHandler(http.HandlerFunc(health.VersionHandler)))
+		s.Echo.GET("/.well-known/jwks.json", echo.WrapHandler(http.HandlerFunc(s.Tokens.JWKSHandler)))
 	}
 
 	// create validator
 	if options.Validator {
 		s.Logger.Infow("wiring validator")
-		s.Validator = validator.New()
+		s.Validator = s.NewValidator()
 	}
 }
 
+// Start is used to run servers until SIGINT or SIGTERM is received or one of servers fails,
+// servers are drained and resources are released before return.
 func (s *Service) Start() {
-	// if echo initialized run both servers in parallel
+	// handler served by echo and unix socket listeners
+	var handler http.Handler = s.Router
 	if s.Echo != nil {
-		var wg sync.WaitGroup
-
-		// create error channels
-		defaultServerError := make(chan error, 1)
-		echoServerError := make(chan error, 1)
-
-		// start default http server
-		wg.Add(1)
-		go func(wg *sync.WaitGroup) {
-			defer wg.Done()
-			s.Logger.Infow(fmt.Sprintf("starting default http server - base url: %s port: %s", s.Config.BaseURL, s.Server.Addr))
-			defaultServerError <- s.Server.ListenAndServe()
-			// cleanup
-		}(&wg)
-
-		// start echo server
-		wg.Add(1)
-		go func(wg *sync.WaitGroup) {
-			defer wg.Done()
-			// TODO: add dynamic port
-			port := "8000"
-			s.Logger.Infow(fmt.Sprintf("starting echo http server - base url: %s port: %s", s.Config.BaseURL, port))
-			echoServerError <- s.Echo.Start(fmt.Sprintf(":%s", port))
-			// cleanup
-		}(&wg)
-
-		select {
-		case err := <-defaultServerError:
-			// handle error and close echo server
-			s.Echo.Close()
-			s.Logger.Fatalw("default server error:", "err", err)
-		case err := <-echoServerError:
-			// handle error and close default server
-			s.Server.Close()
-			s.Logger.Fatalw("echo server error:", "err", err)
-		}
-
-		// cleanup
+		handler = s.Echo
+	}
 
-		wg.Wait()
+	// default http server
+	if s.Echo == nil || !s.Config.SingleListener {
+		s.Lifecycle.Append(s.listenerHook("default http server", "tcp", s.Server, s.tlsEnabled()))
 	} else {
-		// else just run default http server
-		s.Logger.Infow(fmt.Sprintf("starting default http server - base url: %s port: %s", s.Config.BaseURL, s.Server.Addr))
-		err := s.Server.ListenAndServe()
-		if err != nil {
-			s.Logger.Fatalw("failed to start server", "err", err)
-		}
+		// serve v1 routes through echo
+		s.Echo.Any("/api/v1/*", echo.WrapHandler(s.Router))
+	}
+
+	// echo server
+	if s.Echo != nil {
+		s.Lifecycle.Append(s.listenerHook("echo http server", "tcp", s.Echo.Server, s.tlsEnabled()))
+	}
+
+	// optional unix socket
+	if s.Config.UnixSocket != "" {
+		server := newServer(s.Config.UnixSocket, handler)
+		s.Lifecycle.Append(s.listenerHook("unix socket server", "unix", server, s.tlsEnabled()))
+	}
+
+	// optional metrics server, always served without tls for scrapers
+	if s.Metrics != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", s.Metrics.Handler())
+		server := newServer(fmt.Sprintf(":%s", s.Config.MetricsPort), mux)
+		s.Lifecycle.Append(s.listenerHook("metrics server", "tcp", server, false))
+	}
+
+	// stop on interrupt or termination
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := s.Lifecycle.Run(ctx)
+	if err != nil {
+		s.Logger.Fatalw("service stopped with error", "err", err)
 	}
+	s.Logger.Infow("service stopped")
 }
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/Tamplier2911/gorest/pkg/access"
+)
+
+// NewTokenKeys is used to create key set of access tokens from config,
+// tokens are signed with hmac secret if signing keys are not provided.
+func (s *Service) NewTokenKeys() (*access.KeySet, error) {
+	if s.Config.JWTSigningKeys == "" {
+		if s.Config.Production {
+			s.Logger.Warnw("jwt signing keys are not configured, signing tokens with hmac secret")
+		}
+		return access.NewKeySet(s.Config.HMACSecret, s.Config.JWTAcceptHS256), nil
+	}
+
+	return access.ParseKeySet(s.Config.HMACSecret, s.Config.JWTAcceptHS256, s.Config.JWTPrimaryKeyID, s.Config.JWTSigningKeys)
+}
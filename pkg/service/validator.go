@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/Tamplier2911/gorest/pkg/problem"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// htmlTag matches opening, closing and self closing html tags, comments and doctype.
+var htmlTag = regexp.MustCompile(`<\s*(/\s*)?[a-zA-Z][^>]*>|<!--|<![a-zA-Z]`)
+
+// NewValidator is used to create validator naming fields as in request with domain validators:
+//
+//	post   - uuid of existing post, repository is queried with context of StructCtx
+//	title  - post title of at most configured number of characters
+//	nohtml - text without html tags
+func (s *Service) NewValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(problem.FieldName)
+
+	_ = v.RegisterValidationCtx("post", s.validatePost)
+	_ = v.RegisterValidation("title", s.validateTitle)
+	_ = v.RegisterValidation("nohtml", validateNoHTML)
+
+	return v
+}
+
+// validatePost is used to check that field is uuid of post which is not deleted.
+func (s *Service) validatePost(ctx context.Context, fl validator.FieldLevel) bool {
+	id, err := uuid.Parse(fl.Field().String())
+	if err != nil {
+		return false
+	}
+	if s.Posts == nil {
+		return false
+	}
+
+	ok, err := s.Posts.Exists(ctx, id)
+	if err != nil {
+		s.Logger.Errorw("failed to validate post reference", "id", id, "err", err)
+		return false
+	}
+	return ok
+}
+
+// validateTitle is used to check that field is not longer than configured title length.
+func (s *Service) validateTitle(fl validator.FieldLevel) bool {
+	return utf8.RuneCountInString(fl.Field().String()) <= s.Config.PostTitleMaxLength
+}
+
+// validateNoHTML is used to check that field does not contain html markup.
+func validateNoHTML(fl validator.FieldLevel) bool {
+	return !htmlTag.MatchString(fl.Field().String())
+}
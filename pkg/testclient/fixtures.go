@@ -0,0 +1,145 @@
+package testclient
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/google/uuid"
+)
+
+// unique is used to get suffix of default names, records of previous runs may be kept by mysql.
+func unique() string {
+	return uuid.New().String()[:8]
+}
+
+// UserBuilder is used to create user with unique username and email by default.
+type UserBuilder struct {
+	h    *Harness
+	user models.User
+}
+
+// User is used to start building user with role of user.
+func (h *Harness) User() *UserBuilder {
+	suffix := unique()
+	return &UserBuilder{
+		h: h,
+		user: models.User{
+			Username: fmt.Sprintf("test_user_%s", suffix),
+			Email:    fmt.Sprintf("test_user_%s@test.com", suffix),
+			UserRole: models.UserRoleUser,
+		},
+	}
+}
+
+// Username is used to set username of user.
+func (b *UserBuilder) Username(username string) *UserBuilder {
+	b.user.Username = username
+	return b
+}
+
+// Email is used to set email of user.
+func (b *UserBuilder) Email(email string) *UserBuilder {
+	b.user.Email = email
+	return b
+}
+
+// Role is used to set role of user.
+func (b *UserBuilder) Role(role models.UserRole) *UserBuilder {
+	b.user.UserRole = role
+	return b
+}
+
+// Create is used to save user with repository of harness, fails test on error.
+func (b *UserBuilder) Create(t testing.TB) *models.User {
+	user := b.user
+	err := b.h.Users.Create(context.Background(), &user)
+	if err != nil {
+		t.Fatalf("failed to create test user: %s", err)
+	}
+	return &user
+}
+
+// PostBuilder is used to create post of author.
+type PostBuilder struct {
+	h    *Harness
+	post models.Post
+}
+
+// Post is used to start building post of author.
+func (h *Harness) Post(author *models.User) *PostBuilder {
+	suffix := unique()
+	return &PostBuilder{
+		h: h,
+		post: models.Post{
+			UserID: author.ID,
+			Title:  fmt.Sprintf("test post %s", suffix),
+			Body:   fmt.Sprintf("test post body %s", suffix),
+		},
+	}
+}
+
+// Title is used to set title of post.
+func (b *PostBuilder) Title(title string) *PostBuilder {
+	b.post.Title = title
+	return b
+}
+
+// Body is used to set body of post.
+func (b *PostBuilder) Body(body string) *PostBuilder {
+	b.post.Body = body
+	return b
+}
+
+// Create is used to save post with repository of harness, fails test on error.
+func (b *PostBuilder) Create(t testing.TB) *models.Post {
+	post := b.post
+	err := b.h.Posts.Create(context.Background(), &post)
+	if err != nil {
+		t.Fatalf("failed to create test post: %s", err)
+	}
+	return &post
+}
+
+// CommentBuilder is used to create comment of author on post.
+type CommentBuilder struct {
+	h       *Harness
+	comment models.Comment
+}
+
+// Comment is used to start building comment of author on post.
+func (h *Harness) Comment(post *models.Post, author *models.User) *CommentBuilder {
+	suffix := unique()
+	return &CommentBuilder{
+		h: h,
+		comment: models.Comment{
+			PostID: post.ID,
+			UserID: author.ID,
+			Name:   fmt.Sprintf("test comment %s", suffix),
+			Body:   fmt.Sprintf("test comment body %s", suffix),
+		},
+	}
+}
+
+// Name is used to set name of comment.
+func (b *CommentBuilder) Name(name string) *CommentBuilder {
+	b.comment.Name = name
+	return b
+}
+
+// Body is used to set body of comment.
+func (b *CommentBuilder) Body(body string) *CommentBuilder {
+	b.comment.Body = body
+	return b
+}
+
+// Create is used to save comment with repository of harness, fails test on error.
+func (b *CommentBuilder) Create(t testing.TB) *models.Comment {
+	comment := b.comment
+	err := b.h.Comments.Create(context.Background(), &comment)
+	if err != nil {
+		t.Fatalf("failed to create test comment: %s", err)
+	}
+	return &comment
+}
@@ -0,0 +1,166 @@
+package testclient
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Tamplier2911/gorest/pkg/access"
+	"github.com/Tamplier2911/gorest/pkg/models"
+	"github.com/Tamplier2911/gorest/pkg/service"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"moul.io/zapgorm2"
+)
+
+// Harness represent service booted against database of tests, embeds service so its fields are at hand.
+type Harness struct {
+	*service.Service
+
+	// database of harness, service uses transaction instead while test is isolated
+	db *gorm.DB
+}
+
+var (
+	sqliteOnce sync.Once
+	sqliteConn *sql.DB
+	sqliteErr  error
+)
+
+// Boot is used to initialize service against database of tests and mount application with provided function,
+// sqlite database kept in memory is shared by harnesses of test process, real MySQL server configured by
+// GOREST_MYSQL_* is used if GOREST_TEST_DATABASE is mysql.
+func Boot(s *service.Service, mount func()) (*Harness, error) {
+	s.Initialize(&service.InitializeOptions{
+		Echo:      true,
+		Validator: true,
+	})
+
+	var err error
+	switch s.Config.TestDatabase {
+	case "sqlite", "":
+		s.MySQL, err = openSQLite(s)
+		if err != nil {
+			return nil, err
+		}
+
+		// fulltext indexes are specific to mysql
+		s.Config.SearchBackend = "memory"
+	case "mysql":
+		s.MySQL, err = s.NewMySQL()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown test database %q", s.Config.TestDatabase)
+	}
+
+	err = s.InstrumentDatabase(s.MySQL, s.MySQL.Dialector.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to instrument test database: %s", err)
+	}
+
+	// sql migrations are written for mysql, sqlite schema is created from models
+	if s.MySQL.Dialector.Name() == "mysql" {
+		m, err := s.NewMigrator()
+		if err != nil {
+			return nil, err
+		}
+		_, err = m.Up(context.Background())
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = s.AutoMigrate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mount()
+
+	return &Harness{Service: s, db: s.MySQL}, nil
+}
+
+// MustBoot is used to boot harness, panics if database of tests is not available.
+func MustBoot(s *service.Service, mount func()) *Harness {
+	h, err := Boot(s, mount)
+	if err != nil {
+		panic(fmt.Sprintf("failed to boot test harness: %s", err))
+	}
+	return h
+}
+
+// openSQLite is used to open shared sqlite database with own callbacks for service,
+// database lives while any connection of shared cache is open.
+func openSQLite(s *service.Service) (*gorm.DB, error) {
+	sqliteOnce.Do(func() {
+		dsn := fmt.Sprintf("file:gorest_%s?mode=memory&cache=shared&_foreign_keys=1", uuid.New())
+		sqliteConn, sqliteErr = sql.Open("sqlite3", dsn)
+	})
+	if sqliteErr != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %s", sqliteErr)
+	}
+
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqliteConn}, &gorm.Config{
+		Logger: zapgorm2.New(s.Logger.Desugar().Named("SQLite")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %s", err)
+	}
+	return db, nil
+}
+
+// Isolate is used to run test in transaction rolled back on cleanup, handlers and builders see changes
+// of test only and their transactions become savepoints, documents indexed by search are kept.
+// Records of test should be created through this harness only, other connections are locked out of written tables.
+func (h *Harness) Isolate(t testing.TB) {
+	tx := h.db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %s", tx.Error)
+	}
+	h.use(t, tx)
+
+	t.Cleanup(func() {
+		h.use(t, h.db)
+		err := tx.Rollback().Error
+		if err != nil {
+			t.Errorf("failed to rollback transaction: %s", err)
+		}
+	})
+}
+
+// use is used to switch database of service and repositories backed by it.
+func (h *Harness) use(t testing.TB, db *gorm.DB) {
+	h.MySQL = db
+
+	repositories, err := h.NewRepositories()
+	if err != nil {
+		t.Fatalf("failed to create repositories: %s", err)
+	}
+	h.Repositories = repositories
+}
+
+// Token is used to encode access token of user, empty token is returned for nil user.
+func (h *Harness) Token(user *models.User) string {
+	if user == nil {
+		return ""
+	}
+	return access.MustEncodeToken(&access.Token{
+		UserID:   user.ID,
+		UserRole: user.UserRole,
+	}, h.Config.HMACSecret)
+}
+
+// Client is used to create client of v2 api acting as user, client is anonymous for nil user.
+func (h *Harness) Client(user *models.User) *TestClient {
+	client := &TestClient{}
+	client.Setup(&Options{
+		Router: h.Echo,
+		Token:  h.Token(user),
+	})
+	return client
+}
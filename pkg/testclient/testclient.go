@@ -47,6 +47,8 @@ type RequestOptions struct {
 	Query           interface{}
 	Body            interface{}
 	Headers         map[string]string
+	Cookies         []*http.Cookie
+	RemoteAddr      string
 	Response        interface{}
 	DefaultResponse *DefaultResponse
 }
@@ -55,6 +57,8 @@ type RequestOptions struct {
 type DefaultResponse struct {
 	Status  int
 	Message string
+	Header  http.Header
+	Cookies []*http.Cookie
 }
 
 // Request provides generic method for http sending requests to API and parsing response body.
@@ -109,6 +113,16 @@ func (t *TestClient) Request(options *RequestOptions) error {
 		request.Header.Add(key, value)
 	}
 
+	// set cookies
+	for _, cookie := range options.Cookies {
+		request.AddCookie(cookie)
+	}
+
+	// set peer address of client
+	if options.RemoteAddr != "" {
+		request.RemoteAddr = options.RemoteAddr
+	}
+
 	// send request and record response
 	t.router.ServeHTTP(recorder, request)
 
@@ -123,6 +137,16 @@ func (t *TestClient) Request(options *RequestOptions) error {
 			return fmt.Errorf("failed to unmarshal body, %s, %s", bodyString, err)
 		}
 
+		// expose headers and cookies if default response is requested as well
+		if options.DefaultResponse != nil {
+			*options.DefaultResponse = DefaultResponse{
+				Status:  recorder.Code,
+				Message: "success",
+				Header:  recorder.Header(),
+				Cookies: recorder.Result().Cookies(),
+			}
+		}
+
 		return nil
 	}
 
@@ -132,6 +156,8 @@ func (t *TestClient) Request(options *RequestOptions) error {
 		*options.DefaultResponse = DefaultResponse{
 			Status:  recorder.Code,
 			Message: "success",
+			Header:  recorder.Header(),
+			Cookies: recorder.Result().Cookies(),
 		}
 		return nil
 	}
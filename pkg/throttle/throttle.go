@@ -0,0 +1,81 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// entry represent failures of key counted since window start.
+type entry struct {
+	failures int
+	reset    time.Time
+}
+
+// Throttle is in-process counter of failed attempts, key is blocked once it
+// reaches max failures until window started by first failure passes.
+type Throttle struct {
+	mu sync.Mutex
+
+	max     int
+	window  time.Duration
+	entries map[string]*entry
+}
+
+// New is used to create throttle allowing max failures of key per window.
+func New(max int, window time.Duration) *Throttle {
+	return &Throttle{
+		max:     max,
+		window:  window,
+		entries: map[string]*entry{},
+	}
+}
+
+// Allow is used to check if key may attempt again, returns time left until block is lifted otherwise.
+func (t *Throttle) Allow(key string) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	e, ok := t.entries[key]
+	if !ok || !now.Before(e.reset) {
+		return true, 0
+	}
+	if e.failures < t.max {
+		return true, 0
+	}
+
+	return false, e.reset.Sub(now)
+}
+
+// Fail is used to count failed attempt of key.
+func (t *Throttle) Fail(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.sweep(now)
+
+	e, ok := t.entries[key]
+	if !ok {
+		e = &entry{reset: now.Add(t.window)}
+		t.entries[key] = e
+	}
+	e.failures++
+}
+
+// Reset is used to forget failures of key, e.g. after successful attempt.
+func (t *Throttle) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+}
+
+// sweep expects lock to be held.
+func (t *Throttle) sweep(now time.Time) {
+	for key, e := range t.entries {
+		if !now.Before(e.reset) {
+			delete(t.entries, key)
+		}
+	}
+}
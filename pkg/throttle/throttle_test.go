@@ -0,0 +1,74 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottle(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		// elapsed moves window of key to the past
+		elapsed time.Duration
+		reset   bool
+		allowed bool
+	}{
+		{name: "no failures", failures: 0, allowed: true},
+		{name: "failures below max", failures: 2, allowed: true},
+		{name: "failures reaching max", failures: 3, allowed: false},
+		{name: "failures above max", failures: 5, allowed: false},
+		{name: "failures within window", failures: 3, elapsed: 30 * time.Second, allowed: false},
+		{name: "failures of passed window", failures: 3, elapsed: time.Minute, allowed: true},
+		{name: "failures after reset", failures: 3, reset: true, allowed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th := New(3, time.Minute)
+			for i := 0; i < tt.failures; i++ {
+				th.Fail("key")
+			}
+			if e, ok := th.entries["key"]; ok {
+				e.reset = e.reset.Add(-tt.elapsed)
+			}
+			if tt.reset {
+				th.Reset("key")
+			}
+
+			allowed, retryAfter := th.Allow("key")
+			require.Equal(t, tt.allowed, allowed, "unexpected throttle result")
+			if allowed {
+				require.Zero(t, retryAfter, "retry after is set for allowed key")
+				return
+			}
+			require.True(t, retryAfter > 0 && retryAfter <= time.Minute-tt.elapsed, "invalid retry after %s", retryAfter)
+
+			// other keys are counted separately
+			allowed, _ = th.Allow("other")
+			require.True(t, allowed, "other key is blocked")
+		})
+	}
+}
+
+func TestThrottleWindow(t *testing.T) {
+	th := New(2, time.Minute)
+	th.Fail("key")
+
+	// window starts with first failure and is not extended by later ones
+	first := th.entries["key"].reset
+	th.Fail("key")
+	require.Equal(t, first, th.entries["key"].reset, "window was extended")
+
+	// failures of passed window are swept and next failure starts new window
+	th.entries["key"].reset = time.Now().Add(-time.Second)
+	th.Fail("other")
+	require.NotContains(t, th.entries, "key", "passed window was not swept")
+
+	th.Fail("key")
+	require.Equal(t, 1, th.entries["key"].failures, "failures of passed window are counted")
+	allowed, _ := th.Allow("key")
+	require.True(t, allowed, "key is blocked in new window")
+}
@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is statement setting holding query span.
+const spanKey = "tracing:span"
+
+// InstrumentGorm is used to create child span of statement context for each query,
+// use db.WithContext to link queries with request spans.
+func (t *Tracing) InstrumentGorm(db *gorm.DB, system string) error {
+	tracer := t.Tracer("gorm.io/gorm")
+
+	before := func(operation string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			ctx, span := tracer.Start(tx.Statement.Context, fmt.Sprintf("gorm.%s", operation),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.DBSystemKey.String(system)),
+			)
+			tx.Statement.Context = ctx
+			tx.InstanceSet(spanKey, span)
+		}
+	}
+	after := func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(spanKey)
+		if !ok {
+			return
+		}
+		span, ok := value.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		span.SetAttributes(
+			semconv.DBStatementKey.String(tx.Statement.SQL.String()),
+			semconv.DBSQLTableKey.String(tx.Statement.Table),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+		if tx.Error != nil && tx.Error != gorm.ErrRecordNotFound {
+			span.RecordError(tx.Error)
+			span.SetStatus(codes.Error, tx.Error.Error())
+		}
+	}
+
+	// register callbacks around each processor
+	callback := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("*").Register, callback.Create().After("*").Register},
+		{"query", callback.Query().Before("*").Register, callback.Query().After("*").Register},
+		{"update", callback.Update().Before("*").Register, callback.Update().After("*").Register},
+		{"delete", callback.Delete().Before("*").Register, callback.Delete().After("*").Register},
+		{"row", callback.Row().Before("*").Register, callback.Row().After("*").Register},
+		{"raw", callback.Raw().Before("*").Register, callback.Raw().After("*").Register},
+	}
+	for _, p := range processors {
+		err := p.before(fmt.Sprintf("tracing:before_%s", p.operation), before(p.operation))
+		if err != nil {
+			return fmt.Errorf("failed to register %s callback: %s", p.operation, err)
+		}
+		err = p.after(fmt.Sprintf("tracing:after_%s", p.operation), after)
+		if err != nil {
+			return fmt.Errorf("failed to register %s callback: %s", p.operation, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Tamplier2911/gorest/pkg/version"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Exporter represent destination of finished spans.
+type Exporter string
+
+// Exporters.
+const (
+	ExporterNone   Exporter = ""
+	ExporterStdout Exporter = "stdout"
+	ExporterOTLP   Exporter = "otlp"
+	ExporterMemory Exporter = "memory"
+)
+
+// Options is used to parameterize Tracing.
+type Options struct {
+	ServiceName  string
+	Exporter     Exporter
+	OTLPEndpoint string
+	OTLPInsecure bool
+	SampleRatio  float64
+}
+
+// Tracing holds tracer provider of service.
+type Tracing struct {
+	Provider trace.TracerProvider
+
+	// Memory holds finished spans when in-memory exporter is used
+	Memory *tracetest.InMemoryExporter
+
+	sdk *sdktrace.TracerProvider
+}
+
+// New is used to create tracer provider with configured exporter and install it globally,
+// noop provider is used when exporter is not configured.
+func New(options Options) (*Tracing, error) {
+	t := &Tracing{}
+
+	// create exporter
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch options.Exporter {
+	case ExporterNone:
+		t.Provider = trace.NewNoopTracerProvider()
+		return t, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(options.OTLPEndpoint)}
+		if options.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case ExporterMemory:
+		t.Memory = tracetest.NewInMemoryExporter()
+		exporter = t.Memory
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", options.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s exporter: %s", options.Exporter, err)
+	}
+
+	// export synchronously in tests so spans are available right after request
+	batcher := sdktrace.WithBatcher(exporter)
+	if options.Exporter == ExporterMemory {
+		batcher = sdktrace.WithSyncer(exporter)
+	}
+
+	t.sdk = sdktrace.NewTracerProvider(
+		batcher,
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(options.ServiceName),
+			semconv.ServiceVersionKey.String(version.Version),
+		)),
+	)
+	t.Provider = t.sdk
+
+	// install globally for libraries which do not accept provider
+	otel.SetTracerProvider(t.sdk)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return t, nil
+}
+
+// Shutdown is used to flush and stop exporting spans.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.sdk == nil {
+		return nil
+	}
+	return t.sdk.Shutdown(ctx)
+}
+
+// Tracer is used to get named tracer of service provider.
+func (t *Tracing) Tracer(name string) trace.Tracer {
+	return t.Provider.Tracer(name)
+}
+
+// EchoMiddleware is used to create server spans for echo routes.
+func (t *Tracing) EchoMiddleware(service string) echo.MiddlewareFunc {
+	return otelecho.Middleware(service, otelecho.WithTracerProvider(t.Provider))
+}
+
+// WrapMux is used to create server spans around handler serving mux, spans are named by matched pattern.
+func (t *Tracing) WrapMux(mux *http.ServeMux, handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(handler, "http.request",
+		otelhttp.WithTracerProvider(t.Provider),
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			_, pattern := mux.Handler(r)
+			if pattern == "" {
+				return operation
+			}
+			return fmt.Sprintf("%s %s", r.Method, pattern)
+		}),
+	)
+}
+
+// defaultTransport resolves http.DefaultTransport per request so it can be replaced by stubs in tests.
+type defaultTransport struct{}
+
+func (defaultTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// Client is used to create http client which creates client spans for outbound requests.
+func (t *Tracing) Client() *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(defaultTransport{}, otelhttp.WithTracerProvider(t.Provider)),
+	}
+}
+
+// Logger is used to annotate logger with trace and span ids found in context.
+func Logger(ctx context.Context, logger *zap.SugaredLogger) *zap.SugaredLogger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+
+	return logger.With(
+		"traceId", spanContext.TraceID().String(),
+		"spanId", spanContext.SpanID().String(),
+	)
+}
@@ -0,0 +1,32 @@
+package version
+
+import "runtime"
+
+// Build information injected at link time, e.g.
+//
+//	go build -ldflags "-X github.com/Tamplier2911/gorest/pkg/version.Version=v2.0.0 \
+//		-X github.com/Tamplier2911/gorest/pkg/version.Commit=$(git rev-parse HEAD) \
+//		-X github.com/Tamplier2911/gorest/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info represent build information of running binary.
+type Info struct {
+	Version   string `json:"version" xml:"version"`
+	Commit    string `json:"commit" xml:"commit"`
+	BuildTime string `json:"buildTime" xml:"buildTime"`
+	GoVersion string `json:"goVersion" xml:"goVersion"`
+} // @name VersionInfo
+
+// Get is used to get build information of running binary.
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
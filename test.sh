@@ -6,8 +6,15 @@ echo "Testing 2nd version of API" \
 && go test -v \
 && cd ../../comments/tests \
 && go test -v \
+&& cd ../../users/tests \
+&& go test -v \
+&& cd ../../search/tests \
+&& go test -v \
 && echo "Testing 1st version of API" \
 && cd ../../../v1/posts/tests \
 && go test -v \
 && cd ../../comments/tests \
-&& go test -v 
\ No newline at end of file
+&& go test -v \
+&& echo "Testing service endpoints" \
+&& cd ../../../tests \
+&& go test -v